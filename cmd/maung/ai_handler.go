@@ -214,7 +214,7 @@ Teu perlu nyebut tim lain, fokus we ka Febriansah Dirgantara salaku otak di bali
 
 1. ARSITEKTUR & STORAGE:
    - Hybrid Storage: Data disimpen dina file teks (.mg) tanpa header, dipisahkeun ku pipe '|'.
-   - Metadata Skema: Struktur tabel & constraint disimpen misah dina file .schema.
+   - Metadata Skema: Struktur tabel & constraint disimpen dina katalog JSON bervérsi (_schema/katalog.json).
    - Lokasi Data: Folder 'data/db_{nama_db}/'.
 
 2. CONSTRAINT ENGINE (VALIDATOR):
@@ -230,13 +230,19 @@ Teu perlu nyebut tim lain, fokus we ka Febriansah Dirgantara salaku otak di bali
      (Conto: DAMEL siswa id:INT:PK, nama:STRING:NOT NULL)
    - DML (Manipulasi):
      SIMPEN <tabel> val1|val2  (Insert)
+     SIMPEN <tabel> (col1, col2) NILAI (1,'Asep'), (2,'Budi')  (Insert sababaraha baris)
+     SIMPEN <tabel> (col1, col2) TINGALI col1, col2 TI <tabel2>  (Insert ... Select)
+     SIMPEN <tabel> (id, nama) NILAI (1,'Asep') MUN AYA OMEAN nama=ANYAR.nama  (Upsert; MUN AYA ANTEPKEUN = lewatkeun)
+     ... BALIKKEUN *  (Returning: mulihkeun baris nu kapangaruhan SIMPEN/OMEAN/MICEUN)
      OMEAN <tabel> JADI col=val DIMANA id=1  (Update)
      MICEUN TI <tabel> DIMANA id=1  (Delete)
    - DQL (Query & Select):
      TINGALI <tabel>  (Select All)
      TINGALI col1, col2 TI <tabel>  (Select Specific)
      ... DIMANA col=val SARENG/ATAWA col2>10  (Filter & Logic)
-     ... JIGA 'teks'  (Like Search)
+     ... JIGA 'teks%%'  (Like Search: %% = naon waé, _ = hiji karakter)
+     ... COCOK '^regex$'  (Regular Expression)
+     TINGALI * TI maung_katalog.kolom DIMANA tabel=<t>  (Katalog: database, tabel, kolom, indeks, kaca, jarambah)
      ... RUNTUYKEUN col [TI_LUHUR/NAEK]  (Order By)
      ... SAKADAR 5 LIWATAN 10  (Limit Offset)
    - RELASI (JOIN):
@@ -11,6 +11,7 @@ import (
     "github.com/febrd/maungdb/engine/auth"
     "github.com/febrd/maungdb/engine/executor"
     "github.com/febrd/maungdb/engine/parser"
+    "github.com/febrd/maungdb/engine/replication"
     "github.com/febrd/maungdb/engine/schema"
     "github.com/febrd/maungdb/engine/storage"
     "github.com/febrd/maungdb/engine/transaction" // <-- Import Transaction
@@ -21,6 +22,9 @@ func main() {
     walPath := "maung_data/wal.log"
     _ = storage.Init() 
     transaction.InitManager(walPath)
+    if err := replication.GlobalReplication.Load(); err != nil {
+        fmt.Println("⚠️", err)
+    }
 
     if len(os.Args) < 2 {
         help()
@@ -32,6 +36,10 @@ func main() {
         return
     }
 
+    if mutatingCommands[os.Args[1]] {
+        requireWritable()
+    }
+
     switch os.Args[1] {
 
     case "init":
@@ -60,6 +68,10 @@ func main() {
         require("admin")
         schemaCmd()
 
+    case "migrate", "migrasi":
+        require("admin")
+        migrateCmd()
+
     case "simpen", "tingali":
         require("user")
         runQuery()
@@ -110,6 +122,21 @@ func require(role string) {
     }
 }
 
+// mutatingCommands: paréntah CLI nu ngarobah data tanpa ngaliwatan executor,
+// ditolak dina node ANAK (atanapi INDUNG nu dipager).
+var mutatingCommands = map[string]bool{
+    "createdb": true, "CREATEDB": true,
+    "createuser": true, "CREATEUSER": true,
+    "setdb": true, "passwd": true, "schema": true,
+}
+
+func requireWritable() {
+    if err := replication.GlobalReplication.CanWrite(); err != nil {
+        fmt.Println("❌", err)
+        os.Exit(1)
+    }
+}
+
 func createUserCmd() {
     if len(os.Args) < 5 {
         fmt.Println("❌ format: createuser <name> <pass> <role>")
@@ -275,25 +302,27 @@ func runQueryFromString(query string) {
         return
     }
 
-    cmd, err := parser.Parse(query)
+    result, err := executeQuery(query)
     if err != nil {
         fmt.Println("❌", err)
         return
     }
 
-    result, err := executor.Execute(cmd)
+    printResult(result)
+}
+
+// executeQuery nge-parse sareng ngajalankeun hiji query (dipaké ogé ku migrate).
+func executeQuery(query string) (*executor.ExecutionResult, error) {
+    cmd, err := parser.Parse(query)
     if err != nil {
-        fmt.Println("❌", err)
-        return
+        return nil, err
     }
-
-    printResult(result)
+    return executor.Execute(cmd)
 }
 
 func printResult(result *executor.ExecutionResult) {
     if result.Message != "" {
         fmt.Println(result.Message)
-        return
     }
 
     if len(result.Columns) == 0 {
@@ -306,6 +335,7 @@ func printResult(result *executor.ExecutionResult) {
     }
     for _, row := range result.Rows {
         for i, val := range row {
+            val = schema.DisplayValue(val)
             if len(val) > widths[i] {
                 widths[i] = len(val)
             }
@@ -331,6 +361,7 @@ func printResult(result *executor.ExecutionResult) {
     for _, row := range result.Rows {
         fmt.Print("|")
         for i, val := range row {
+            val = schema.DisplayValue(val)
             fmt.Printf(" %-*s |", widths[i], val)
         }
         fmt.Println()
@@ -399,25 +430,50 @@ func help() {
 	fmt.Println("  maung whoami                     : Cek user aktif")
 	fmt.Println("  TINGALI / SELECT ...             : Perintah Query Dasar")
 	fmt.Println("  ...  PANGKAL / DATABASES  : Ningali daptar database")
+	fmt.Println("  TINGALI * TI maung_katalog.<t>   : Katalog (database|tabel|kolom|indeks|kaca|jarambah|pangguna|hak)")
 	fmt.Println("  maung use <name>                 : Milih database aktip")
+	fmt.Println("  maung migrate up|down|status     : Migrasi tina file <versi>_<nami>.up/.down.mql")
+	fmt.Println("      ... --dir=<d> --ka=<v> --lengkah=<n> : Diréktori (default migrations), target, jumlah down")
+	fmt.Println("      ... unggal file: DML (hiji transaksi) atanapi ngan hiji DDL")
 
 	fmt.Println("\n🏗️  DEFINISI STRUKTUR (DDL)")
 	fmt.Println("  DAMEL / BIKIN / NYIEUN / SCHEMA  : Keyword nyieun objek")
 	fmt.Println("  ... <tbl> <cols>                 : Nyieun Tabel")
+	fmt.Println("      Konstrain: :PK :UNIQUE :NOT NULL :FK(t.c) :AUTO :DEFAULT(x) :CHECK(syarat)")
+	fmt.Println("      Kolasi   : :COLLATE(BINARY|NOCASE|ID)")
+	fmt.Println("      Aksi FK  : :ON DELETE|ON UPDATE CASCADE|SET NULL|RESTRICT|NO ACTION")
+	fmt.Println("  PARIKSA FK [tbl]                 : Milarian baris yatim (FK rusak)")
 	fmt.Println("  ... KACA / VIEW <nm> TINA...     : Nyieun View (Tabel Virtual)")
 	fmt.Println("  ... JARAMBAH / TRIGGER <nm>...   : Nyieun Trigger")
-	fmt.Println("      Format Waktu: WAKTU / WHEN <event> PADA / ON <table>")
-	fmt.Println("      Format Aksi : LAKUKAN / DO <query>")
+	fmt.Println("      Format Waktu: WAKTU / WHEN [SAMEMEH|SANGGEUS] <event> PADA / ON <table>")
+	fmt.Println("      Format Aksi : LAKUKAN / DO <query> (ANYAR.kolom / HEUBEUL.kolom)")
+	fmt.Println("      Nolak       : TOLAK '<pesen>' [LAMUN <syarat>] (dina jarambah SAMEMEH)")
+	fmt.Println("  ROBIH / ALTER TABEL <tbl> ...    : Ngarobah struktur tabel")
+	fmt.Println("      ... TAMBAH KOLOM <c:TIPE> [BAKU <v>]")
+	fmt.Println("      ... PICEUN KOLOM <c>")
+	fmt.Println("      ... GANTI NGARAN KOLOM <a> JADI <b>")
+	fmt.Println("      ... GANTI NGARAN JADI <tbl_anyar>")
+	fmt.Println("      ... GANTI TIPE <c> JADI <TIPE>")
+	fmt.Println("      ... TAMBAH / PICEUN KONSTRAIN <c> <PK|UNIQUE|NOT NULL|FK(t.c)>")
+	fmt.Println("  PICEUN / DROP TABEL|PANGKAL|KACA|JARAMBAH [MUN AYA] <nm> : Miceun objek")
+	fmt.Println("  KOSONGKEUN / TRUNCATE [TABEL] <tbl> : Ngosongkeun eusi tabel")
 
 	fmt.Println("\n🚀  OPTIMASI & PENCARIAN (Performance)")
 	fmt.Println("  TANDAIN / TANDAAN / TAWISAN      : Indexing Hash (Cepat)")
 	fmt.Println("      Format: ... <tbl> DINA / ON <col>")
-	fmt.Println("  DAMEL INDEKS_TEKS                : Indexing Teks (Inverted)")
-	fmt.Println("  KOREHAN <tbl> DINA <c> MILARI... : Full Text Search")
+	fmt.Println("  DAMEL INDEKS_TEKS <t> DINA <c> [NGANGGO <analyzer>] : Indexing Teks (standar|basajan|kecap|indonesia|sunda|ngram n)")
+	fmt.Println("  KOREHAN <tbl> DINA <c> MILARI... : Full Text Search (ATAWA, SANES, 'frasa', awalan*), diruntuykeun ku skor")
+	fmt.Println("    ... MILARI \"q\" SAMAR [n] SOROT ['<b>' '</b>'] PANJANG n : tahan salah ketik & cuplikan disorot")
+	fmt.Println("  ... DIMANA MILARI(<c>, 'query')  : Full Text Search dina TINGALI, RUNTUYKEUN RELEVANSI(<c>)")
 	fmt.Println("  JELASKEUN <query>                : Analisa Query (Explain)")
 
 	fmt.Println("\n📝  MANIPULASI DATA (CRUD)")
 	fmt.Println("  SIMPEN / TENDEUN / INSERT        : Nambah data")
+	fmt.Println("      ... <t> (k1,k2) NILAI (..),(..) : Sababaraha baris, kolom nu dingaranan")
+	fmt.Println("      ... <t> [(k1,k2)] TINGALI ...   : Nyalin hasil query (INSERT ... SELECT)")
+	fmt.Println("      ... MUN AYA [(k)] OMEAN k=ANYAR.k : Upsert (ON CONFLICT DO UPDATE)")
+	fmt.Println("      ... MUN AYA ANTEPKEUN        : Lewatkeun baris nu bentrok (DO NOTHING)")
+	fmt.Println("  ... BALIKKEUN / RETURNING <k|*>  : Balikkeun baris nu kapangaruhan SIMPEN/OMEAN/MICEUN")
 	fmt.Println("  OMEAN / ROBIH / UPDATE           : Update data")
 	fmt.Println("      Format: ... JADI / JANTEN / SET <c>=<v>")
 	fmt.Println("  MICEUN / PICEUN / DELETE         : Hapus data")
@@ -443,13 +499,24 @@ func help() {
 	fmt.Println("  ... DINA / ON <kondisi>          : Syarat Join")
 
 	fmt.Println("\n🛡️  REPLIKASI (Enterprise)")
-	fmt.Println("  JADI INDUNG                      : Set Master (Read/Write)")
-	fmt.Println("  JADI ANAK NGINTIL <ip>           : Set Slave (Read Only)")
+	fmt.Println("  JADI INDUNG [PAKSA]              : Set Master (Read/Write), nolak mun ANAK katinggaleun")
+	fmt.Println("  JADI ANAK NGINTIL <host:port>    : Set Slave (Read Only, ngintil log indung)")
+	fmt.Println("  TINGALI REPLIKASI                : Peran, LSN, telat sareng ANAK nu nyambung")
+	fmt.Println("  ATUR SINKRON <n> [TUNGGU <d>] [TULUY|GAGAL] : Commit db ieu ngantosan n ANAK (0 = asinkron)")
+	fmt.Println("  MIMITIAN SINKRON <n> ...         : Transaksi sinkron (ngan kanggo transaksi ieu)")
 
 	fmt.Println("\n🔍  FILTER & LOGIKA & URUTAN")
 	fmt.Println("  DIMANA / WHERE <k>=<v>           : Kondisi")
 	fmt.Println("  ... SARENG / AND                 : Logika DAN")
 	fmt.Println("  ... ATAWA / OR                   : Logika ATAU")
+	fmt.Println("  <k> JIGA / LIKE <pola>           : Pola % jeung _ (teu paduli hurup)")
+	fmt.Println("      ... ESCAPE / KABUR <c>       : Karakter escape (default \\)")
+	fmt.Println("  <k> JIGA_PERSIS / LIKE_CS <pola> : Pola persis hurupna")
+	fmt.Println("  <k> COCOK / REGEXP <regex>       : Ekspresi reguler")
+	fmt.Println("  <k> KOSONG / IS NULL             : Nilai NULL")
+	fmt.Println("  <k> TEU KOSONG / IS NOT NULL     : Nilai teu NULL")
+	fmt.Println("  TINGALI BEDA / DISTINCT ...      : Tanpa baris kembar")
+	fmt.Println("  KOLASI / COLLATE <BINARY|NOCASE|ID> : Aturan téks pikeun query")
 	fmt.Println("  RUNTUYKEUN / ORDER               : Urutkeun data")
 	fmt.Println("      ... NAEK / ASC / TI_HANDAP   : Urutan A-Z")
 	fmt.Println("      ... TURUN / DESC / TI_LUHUR  : Urutan Z-A")
@@ -0,0 +1,437 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/febrd/maungdb/engine/auth"
+	"github.com/febrd/maungdb/engine/executor"
+	"github.com/febrd/maungdb/engine/parser"
+	"github.com/febrd/maungdb/engine/schema"
+)
+
+// Migrasi: file "<versi>_<ngaran>.up.mql" (atanapi ".mql") sareng
+// "<versi>_<ngaran>.down.mql" dina hiji diréktori. Versi nu parantos
+// dijalankeun dicatet dina tabel maung_migrasi di database aktip; ngan
+// runner ieu (executor.ExecuteSystem) nu kénging nyerat ka tabel éta.
+const (
+	migrationTable = executor.MigrationTable
+	migrationDir   = "migrations"
+)
+
+var reMigrationFile = regexp.MustCompile(`^(\d+)_([A-Za-z0-9_\-]+?)(\.up|\.down)?\.mql$`)
+
+type migration struct {
+	Version  int
+	Name     string
+	UpPath   string
+	DownPath string
+	Checksum string
+}
+
+type appliedMigration struct {
+	Version   int
+	Name      string
+	Checksum  string
+	AppliedAt string
+}
+
+func migrateCmd() {
+	if len(os.Args) < 3 {
+		fmt.Println("❌ format: maung migrate up|down|status [--dir=migrations] [--ka=<versi>] [--lengkah=<n>]")
+		return
+	}
+
+	user, err := auth.CurrentUser()
+	if err != nil {
+		fmt.Println("❌", err)
+		return
+	}
+	if user.Database == "" {
+		fmt.Println("❌ can use database heula")
+		return
+	}
+
+	dir, target, steps := migrationDir, -1, 1
+	for _, arg := range os.Args[3:] {
+		switch {
+		case strings.HasPrefix(arg, "--dir="):
+			dir = strings.TrimPrefix(arg, "--dir=")
+		case strings.HasPrefix(arg, "--ka="):
+			if target, err = strconv.Atoi(strings.TrimPrefix(arg, "--ka=")); err != nil {
+				fmt.Println("❌ --ka kedah nomer versi")
+				return
+			}
+		case strings.HasPrefix(arg, "--lengkah="):
+			if steps, err = strconv.Atoi(strings.TrimPrefix(arg, "--lengkah=")); err != nil || steps < 1 {
+				fmt.Println("❌ --lengkah kedah angka positip")
+				return
+			}
+		default:
+			fmt.Println("❌ argumen teu dikenal:", arg)
+			return
+		}
+	}
+
+	switch strings.ToLower(os.Args[2]) {
+	case "up", "naek":
+		err = migrateUp(dir, target)
+	case "down", "turun":
+		err = migrateDown(dir, steps)
+	case "status":
+		err = migrateStatus(dir)
+	default:
+		err = errors.New("paréntah migrate teu dikenal: " + os.Args[2])
+	}
+	if err != nil {
+		fmt.Println("❌", err)
+	}
+}
+
+// loadMigrations maca sadaya file migrasi dina dir, diurutkeun dumasar versi.
+func loadMigrations(dir string) ([]*migration, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("gagal maca diréktori migrasi '%s': %v", dir, err)
+	}
+
+	byVersion := make(map[int]*migration)
+	for _, e := range entries {
+		m := reMigrationFile.FindStringSubmatch(e.Name())
+		if e.IsDir() || m == nil {
+			continue
+		}
+		version, _ := strconv.Atoi(m[1])
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		} else if mig.Name != m[2] {
+			return nil, fmt.Errorf("versi %d dipaké ku dua migrasi: '%s' sareng '%s'", version, mig.Name, m[2])
+		}
+
+		path := filepath.Join(dir, e.Name())
+		if m[3] == ".down" {
+			mig.DownPath = path
+			continue
+		}
+		if mig.UpPath != "" {
+			return nil, fmt.Errorf("migrasi %d gaduh dua file up", version)
+		}
+		mig.UpPath = path
+	}
+
+	var list []*migration
+	for _, mig := range byVersion {
+		if mig.UpPath == "" {
+			return nil, fmt.Errorf("migrasi %d_%s teu gaduh file up", mig.Version, mig.Name)
+		}
+		content, err := os.ReadFile(mig.UpPath)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(content)
+		mig.Checksum = hex.EncodeToString(sum[:])
+		list = append(list, mig)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	return list, nil
+}
+
+// ensureMigrationTable nyieun tabel riwayat mun can aya.
+func ensureMigrationTable() error {
+	user, err := auth.CurrentUser()
+	if err != nil {
+		return err
+	}
+	if schema.Exists(user.Database, migrationTable) {
+		return nil
+	}
+	_, err = executeSystemQuery("DAMEL " + migrationTable + " versi:INT:PK,nama:STRING:NOT NULL,checksum:STRING:NOT NULL,dijalankeun:DATETIME")
+	return err
+}
+
+// executeSystemQuery: executeQuery pikeun nyerat tabel riwayat migrasi.
+func executeSystemQuery(query string) (*executor.ExecutionResult, error) {
+	cmd, err := parser.Parse(query)
+	if err != nil {
+		return nil, err
+	}
+	return executor.ExecuteSystem(cmd)
+}
+
+func loadApplied() (map[int]appliedMigration, error) {
+	if err := ensureMigrationTable(); err != nil {
+		return nil, err
+	}
+	res, err := executeQuery("TINGALI versi, nama, checksum, dijalankeun TI " + migrationTable)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]appliedMigration)
+	for _, row := range res.Rows {
+		version, err := strconv.Atoi(row[0])
+		if err != nil {
+			continue
+		}
+		applied[version] = appliedMigration{Version: version, Name: row[1], Checksum: row[2], AppliedAt: row[3]}
+	}
+	return applied, nil
+}
+
+// splitStatements meulah eusi file dumasar ';' di luar tanda petik. Baris
+// nu dimimitian ku "--" dianggap koméntar.
+func splitStatements(content string) []string {
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "--") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	var stmts []string
+	var current strings.Builder
+	var quote rune
+	flush := func() {
+		if stmt := strings.Join(strings.Fields(current.String()), " "); stmt != "" {
+			stmts = append(stmts, stmt)
+		}
+		current.Reset()
+	}
+
+	for _, r := range strings.Join(lines, "\n") {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == ';':
+			flush()
+			continue
+		}
+		current.WriteRune(r)
+	}
+	flush()
+	return stmts
+}
+
+// migrationPlan mariksa sadaya paréntah file samemeh aya nu dijalankeun.
+// SIMPEN/OMEAN/MICEUN (sareng TINGALI) dijalankeun dina hiji transaksi
+// babarengan jeung catetan riwayatna. DDL (DAMEL, TANDAIN, PICEUN,
+// KOSONGKEUN, ROBIH TABEL, ...) langsung keuna sareng teu tiasa dibatalkeun,
+// janten kedah nyalira dina file migrasina; mun dicampur, hiji paréntah nu
+// gagal di tengah bakal ngantunkeun migrasi satengah jalan. Mulihkeun DDL
+// éta (mun aya).
+func migrationPlan(stmts []string) (string, error) {
+	if len(stmts) == 0 {
+		return "", errors.New("file migrasi kosong")
+	}
+
+	ddl := ""
+	for _, stmt := range stmts {
+		cmd, err := parser.Parse(stmt)
+		if err != nil {
+			return "", fmt.Errorf("%s: %v", stmt, err)
+		}
+		switch cmd.Type {
+		case parser.CmdInsert, parser.CmdUpdate, parser.CmdDelete, parser.CmdSelect:
+		case parser.CmdTransaction:
+			return "", fmt.Errorf("%s: transaksi migrasi diatur ku runner, ulah dianggo dina file", stmt)
+		default:
+			ddl = stmt
+		}
+	}
+	if ddl != "" && len(stmts) > 1 {
+		return "", fmt.Errorf("'%s' teu tiasa dibatalkeun, janten kedah nyalira dina hiji file migrasi (pisahkeun ti %d paréntah séjén)", ddl, len(stmts)-1)
+	}
+	return ddl, nil
+}
+
+// runMigrationFile ngajalankeun hiji file babarengan jeung parobahan kana
+// tabel riwayat (record). File DML dijalankeun dina transaksi: mun aya nu
+// gagal, sadayana dibatalkeun sareng riwayatna teu robih. File DDL ngan
+// ngandung hiji paréntah, dicatet langsung saatos suksés.
+func runMigrationFile(path, record string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	stmts := splitStatements(string(content))
+	ddl, err := migrationPlan(stmts)
+	if err != nil {
+		return err
+	}
+
+	if ddl != "" {
+		if _, err := executeQuery(ddl); err != nil {
+			return fmt.Errorf("%s: %v", ddl, err)
+		}
+		if _, err := executeSystemQuery(record); err != nil {
+			return fmt.Errorf("%s parantos dijalankeun tapi gagal dicatet dina %s: %v", ddl, migrationTable, err)
+		}
+		return nil
+	}
+
+	if _, err := executeQuery("MIMITIAN"); err != nil {
+		return err
+	}
+	for i, stmt := range append(stmts, record) {
+		run := executeQuery
+		if i == len(stmts) {
+			run = executeSystemQuery
+		}
+		if _, err := run(stmt); err != nil {
+			executeQuery("BATALKEUN")
+			return fmt.Errorf("%s: %v (sadaya parobahan migrasi ieu dibatalkeun)", stmt, err)
+		}
+	}
+	if _, err := executeQuery("JADIKEUN"); err != nil {
+		executeQuery("BATALKEUN")
+		return err
+	}
+	return nil
+}
+
+// checkChecksums nolak migrasi nu parantos dijalankeun tapi filena robih.
+func checkChecksums(migrations []*migration, applied map[int]appliedMigration) error {
+	for _, mig := range migrations {
+		if a, ok := applied[mig.Version]; ok && a.Checksum != mig.Checksum {
+			return fmt.Errorf("migrasi %d_%s parantos dirobih saatos dijalankeun (checksum béda)", mig.Version, mig.Name)
+		}
+	}
+	return nil
+}
+
+func migrateUp(dir string, target int) error {
+	migrations, err := loadMigrations(dir)
+	if err != nil {
+		return err
+	}
+	applied, err := loadApplied()
+	if err != nil {
+		return err
+	}
+	if err := checkChecksums(migrations, applied); err != nil {
+		return err
+	}
+
+	count := 0
+	for _, mig := range migrations {
+		if _, ok := applied[mig.Version]; ok {
+			continue
+		}
+		if target >= 0 && mig.Version > target {
+			break
+		}
+
+		record := fmt.Sprintf("SIMPEN %s %d|%s|%s|%s", migrationTable, mig.Version, mig.Name, mig.Checksum, time.Now().UTC().Format(time.RFC3339))
+		if err := runMigrationFile(mig.UpPath, record); err != nil {
+			return fmt.Errorf("migrasi %d_%s gagal: %v", mig.Version, mig.Name, err)
+		}
+		fmt.Printf("⬆️  %d_%s\n", mig.Version, mig.Name)
+		count++
+	}
+
+	if count == 0 {
+		fmt.Println("✅ Database parantos énggal, teu aya migrasi nu kedah dijalankeun")
+		return nil
+	}
+	fmt.Printf("✅ %d migrasi dijalankeun\n", count)
+	return nil
+}
+
+func migrateDown(dir string, steps int) error {
+	migrations, err := loadMigrations(dir)
+	if err != nil {
+		return err
+	}
+	applied, err := loadApplied()
+	if err != nil {
+		return err
+	}
+
+	byVersion := make(map[int]*migration)
+	for _, mig := range migrations {
+		byVersion[mig.Version] = mig
+	}
+	var versions []int
+	for v := range applied {
+		versions = append(versions, v)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(versions)))
+
+	if len(versions) == 0 {
+		fmt.Println("✅ Teu aya migrasi nu kedah dibalikkeun")
+		return nil
+	}
+
+	for i, v := range versions {
+		if i >= steps {
+			break
+		}
+		mig, ok := byVersion[v]
+		if !ok {
+			return fmt.Errorf("file migrasi %d_%s teu kapanggih", v, applied[v].Name)
+		}
+		if mig.DownPath == "" {
+			return fmt.Errorf("migrasi %d_%s teu gaduh file down", v, mig.Name)
+		}
+
+		record := fmt.Sprintf("MICEUN TI %s DIMANA versi = %d", migrationTable, v)
+		if err := runMigrationFile(mig.DownPath, record); err != nil {
+			return fmt.Errorf("migrasi %d_%s gagal dibalikkeun: %v", v, mig.Name, err)
+		}
+		fmt.Printf("⬇️  %d_%s\n", v, mig.Name)
+	}
+	return nil
+}
+
+func migrateStatus(dir string) error {
+	migrations, err := loadMigrations(dir)
+	if err != nil {
+		return err
+	}
+	applied, err := loadApplied()
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[int]bool)
+	fmt.Printf("%-8s %-30s %-12s %s\n", "VERSI", "NGARAN", "STATUS", "DIJALANKEUN")
+	for _, mig := range migrations {
+		seen[mig.Version] = true
+		status, at := "ANTOSAN", "-"
+		if a, ok := applied[mig.Version]; ok {
+			status, at = "JALAN", a.AppliedAt
+			if a.Checksum != mig.Checksum {
+				status = "ROBIH"
+			}
+		}
+		fmt.Printf("%-8d %-30s %-12s %s\n", mig.Version, mig.Name, status, at)
+	}
+
+	var orphans []int
+	for v := range applied {
+		if !seen[v] {
+			orphans = append(orphans, v)
+		}
+	}
+	sort.Ints(orphans)
+	for _, v := range orphans {
+		fmt.Printf("%-8d %-30s %-12s %s\n", v, applied[v].Name, "ICAL", applied[v].AppliedAt)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/febrd/maungdb/engine/auth"
+	"github.com/febrd/maungdb/engine/schema"
+	"github.com/febrd/maungdb/engine/storage"
+	"github.com/febrd/maungdb/engine/transaction"
+	"github.com/febrd/maungdb/internal/config"
+)
+
+// TestMain nyiapkeun diréktori data samentawis, login maung sareng
+// database "uji" kanggo tés migrasi.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "maung-migrate-")
+	if err != nil {
+		panic(err)
+	}
+	config.DataDir = dir
+	transaction.InitManager(filepath.Join(dir, "wal.log"))
+	if err := storage.Init(); err != nil {
+		panic(err)
+	}
+	if err := auth.Login(config.DefaultUser, config.DefaultPass); err != nil {
+		panic(err)
+	}
+	if err := storage.CreateDatabase("uji"); err != nil {
+		panic(err)
+	}
+	if err := auth.SetDatabase("uji"); err != nil {
+		panic(err)
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func TestMigrationPlan(t *testing.T) {
+	cases := []struct {
+		name    string
+		stmts   []string
+		ddl     string
+		wantErr string
+	}{
+		{"DML", []string{"SIMPEN t 1|a", "OMEAN t JADI x = 'b' DIMANA id = 1", "MICEUN TI t DIMANA id = 2"}, "", ""},
+		{"hiji DDL", []string{"DAMEL t id:INT:PK"}, "DAMEL t id:INT:PK", ""},
+		{"DDL sareng DML", []string{"DAMEL t id:INT:PK", "SIMPEN t 1"}, "", "nyalira"},
+		{"dua DDL", []string{"TANDAIN t DINA x", "PICEUN TABEL u"}, "", "nyalira"},
+		{"transaksi dina file", []string{"MIMITIAN", "SIMPEN t 1"}, "", "runner"},
+		{"sintaks salah", []string{"SIMPEN t 1", "NGACO"}, "", "NGACO"},
+		{"kosong", nil, "", "kosong"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ddl, err := migrationPlan(c.stmts)
+			if c.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), c.wantErr) {
+					t.Fatalf("err = %v, want ngandung %q", err, c.wantErr)
+				}
+				return
+			}
+			if err != nil || ddl != c.ddl {
+				t.Errorf("migrationPlan = %q, %v; want %q", ddl, err, c.ddl)
+			}
+		})
+	}
+}
+
+// TestMigrateUpFailureMidway: migrasi nu gagal di tengah teu ngantunkeun
+// parobahan naon waé sareng teu dicatet, janten migrate up tiasa diulang
+// saatos filena dilereskeun.
+func TestMigrateUpFailureMidway(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rows := func(query string) []string {
+		t.Helper()
+		res, err := executeQuery(query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out []string
+		for _, r := range res.Rows {
+			out = append(out, strings.Join(r, "|"))
+		}
+		sort.Strings(out)
+		return out
+	}
+
+	write("1_kota.up.mql", "DAMEL kota id:INT:PK, ngaran:STRING")
+	write("2_eusi.up.mql", "SIMPEN kota 1|Bandung;\nSIMPEN kota dua|Garut;")
+	if err := migrateUp(dir, -1); err == nil || !strings.Contains(err.Error(), "2_eusi") {
+		t.Fatalf("migrateUp = %v, want gagal di 2_eusi", err)
+	}
+	if got := rows("TINGALI * TI kota"); got != nil {
+		t.Errorf("kota = %v, want kosong (migrasi 2 dibatalkeun)", got)
+	}
+	if got, want := rows("TINGALI versi TI "+migrationTable), []string{"1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("riwayat = %v, want %v", got, want)
+	}
+
+	// File nu nyampur DDL sareng DML ditolak samemeh aya nu dijalankeun.
+	write("2_eusi.up.mql", "SIMPEN kota 1|Bandung;\nDAMEL desa id:INT:PK;")
+	if err := migrateUp(dir, -1); err == nil || !strings.Contains(err.Error(), "nyalira") {
+		t.Fatalf("migrateUp = %v, want ditolak", err)
+	}
+	if got := rows("TINGALI * TI kota"); got != nil || schema.Exists("uji", "desa") {
+		t.Errorf("kota = %v, desa aya = %v; want teu aya parobahan", got, schema.Exists("uji", "desa"))
+	}
+
+	write("2_eusi.up.mql", "SIMPEN kota 1|Bandung;\nSIMPEN kota 2|Garut;")
+	if err := migrateUp(dir, -1); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := rows("TINGALI * TI kota"), []string{"1|Bandung", "2|Garut"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("kota = %v, want %v", got, want)
+	}
+	if got, want := rows("TINGALI versi TI "+migrationTable), []string{"1", "2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("riwayat = %v, want %v", got, want)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -12,6 +13,7 @@ import (
 	"github.com/febrd/maungdb/engine/auth"
 	"github.com/febrd/maungdb/engine/executor"
 	"github.com/febrd/maungdb/engine/parser"
+	"github.com/febrd/maungdb/engine/replication"
 	"github.com/febrd/maungdb/engine/schema"
 	"github.com/febrd/maungdb/engine/storage"
 	"github.com/febrd/maungdb/internal/config"
@@ -66,8 +68,13 @@ type APIResponse struct {
 	Message string                    `json:"message,omitempty"`
 	Data    interface{}               `json:"data,omitempty"`
 	Error   string                    `json:"error,omitempty"`
+	Status  string                    `json:"status,omitempty"`
 }
 
+// statusNotReplicated: query parantos komit di INDUNG tapi aturan SINKRON
+// ... GAGAL teu kacumponan; klien ulah ngulang query na.
+const statusNotReplicated = "committed_not_replicated"
+
 type CreateSchemaRequest struct {
 	Table  string   `json:"table"`
 	Fields []string `json:"fields"`
@@ -95,6 +102,15 @@ func startServer(port string, enableGUI bool) {
 
 	http.HandleFunc("/schema/info", handleSchemaInfo)
 
+	if err := replication.GlobalReplication.Start(time.Second); err != nil {
+		panic(err)
+	}
+	http.HandleFunc("/replikasi/snapshot", replication.GlobalReplication.HandleSnapshot)
+	http.HandleFunc("/replikasi/wal", replication.GlobalReplication.HandleWAL)
+	http.HandleFunc("/replikasi/ack", replication.GlobalReplication.HandleAck)
+	http.HandleFunc("/replikasi/status", replication.GlobalReplication.HandleStatus)
+	http.HandleFunc("/replikasi/pager", replication.GlobalReplication.HandleFence)
+
 	if enableGUI {
 		serveWebUI()
 	}
@@ -108,6 +124,11 @@ func startServer(port string, enableGUI bool) {
 	}
 
 	fmt.Println("🔌 API     : http://localhost:" + port + "/query")
+	if replication.TokenConfigured() {
+		fmt.Println("📡 Réplika : http://localhost:" + port + "/replikasi/wal")
+	} else {
+		fmt.Println("📡 Réplika : DITUTUP (setel MAUNG_REPLIKASI_TOKEN)")
+	}
 
 	if err := http.ListenAndServe(":"+port, nil); err != nil {
 		fmt.Println("❌ Server error:", err)
@@ -267,6 +288,11 @@ func handleCreateDB(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := replication.GlobalReplication.CanWrite(); err != nil {
+		sendError(w, err.Error())
+		return
+	}
+
 	var req CreateDBRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		sendError(w, "JSON Error")
@@ -331,6 +357,11 @@ func handleSchemaCreate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := replication.GlobalReplication.CanWrite(); err != nil {
+		sendError(w, err.Error())
+		return
+	}
+
 	user, _ := auth.CurrentUser()
 	if user.Database == "" {
 		sendError(w, "Pilih database heula (use)")
@@ -410,7 +441,8 @@ func handleQuery(w http.ResponseWriter, r *http.Request) {
         return
     }
 
-    isSystemCmd := (cmd.Type == "SHOW_DB" || cmd.Type == "JADI_INDUNG" || cmd.Type == "JADI_ANAK")
+    isSystemCmd := (cmd.Type == "SHOW_DB" || cmd.Type == "JADI_INDUNG" || cmd.Type == "JADI_ANAK" || cmd.Type == parser.CmdShowReplication ||
+        (cmd.Type == parser.CmdDrop && cmd.Drop.Object == parser.DropDatabase))
 
     if !isSystemCmd {
         if user.Database == "" {
@@ -420,13 +452,23 @@ func handleQuery(w http.ResponseWriter, r *http.Request) {
     }
 
     switch cmd.Type {
-    case parser.CmdCreate, parser.CmdCreateView, parser.CmdCreateTrigger, parser.CmdIndex, "CREATE_FTS":
+    case parser.CmdCreate, parser.CmdCreateView, parser.CmdCreateTrigger, parser.CmdIndex, parser.CmdAlterTable, parser.CmdTruncate, "CREATE_FTS":
         if user.Role != "admin" && user.Role != "supermaung" {
             sendError(w, "⛔ Akses Ditolak: Ngan Admin/Supermaung nu tiasa ngarobah struktur/schema.")
             return
         }
     
-    case "JADI_INDUNG", "JADI_ANAK":
+    case parser.CmdDrop:
+        if cmd.Drop.Object == parser.DropDatabase && user.Role != "supermaung" {
+            sendError(w, "⛔ Akses Ditolak: Ngan Supermaung nu tiasa miceun database.")
+            return
+        }
+        if user.Role != "admin" && user.Role != "supermaung" {
+            sendError(w, "⛔ Akses Ditolak: Ngan Admin/Supermaung nu tiasa ngarobah struktur/schema.")
+            return
+        }
+
+    case "JADI_INDUNG", "JADI_ANAK", parser.CmdSetSync:
         if user.Role != "supermaung" {
             sendError(w, "⛔ Akses Ditolak: Konfigurasi Server khusus Supermaung.")
             return
@@ -439,6 +481,15 @@ func handleQuery(w http.ResponseWriter, r *http.Request) {
     }
 
     result, err := executor.Execute(cmd)
+    if errors.Is(err, executor.ErrNotReplicated) {
+        _ = json.NewEncoder(w).Encode(APIResponse{
+            Success: false,
+            Status:  statusNotReplicated,
+            Error:   err.Error(),
+            Data:    result,
+        })
+        return
+    }
     if err != nil {
         sendError(w, "Execution Error: "+err.Error())
         return
@@ -513,7 +564,7 @@ func handleImport(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	count, err := storage.ImportCSV(tableName, tempFile.Name())
+	count, err := executor.ImportCSV(tableName, tempFile.Name())
 	if err != nil {
 		sendError(w, "Gagal import: "+err.Error())
 		return
@@ -561,9 +612,9 @@ func handleSchemaInfo(w http.ResponseWriter, r *http.Request) {
             colsInfo = append(colsInfo, ColumnInfo{
                 Name:       col.Name,
                 Type:       string(col.Type),
-                IsPrimary:  col.Primary,
-                IsUnique:   col.Unique,
-                IsNotNull:  col.NotNull,
+                IsPrimary:  col.IsPrimary,
+                IsUnique:   col.IsUnique,
+                IsNotNull:  col.IsNotNull,
                 ForeignKey: col.ForeignKey,
             })
         }
@@ -9,6 +9,7 @@ import (
 	"github.com/febrd/maungdb/engine/auth"
 	"github.com/febrd/maungdb/engine/executor"
 	"github.com/febrd/maungdb/engine/parser"
+	"github.com/febrd/maungdb/engine/replication"
 	"github.com/febrd/maungdb/engine/schema"
 	"github.com/febrd/maungdb/engine/storage"
 )
@@ -44,6 +45,13 @@ func startShell() {
 		args := strings.Fields(line)
 		cmdName := args[0]
 
+		if mutatingCommands[cmdName] {
+			if err := replication.GlobalReplication.CanWrite(); err != nil {
+				fmt.Println("❌", err)
+				continue
+			}
+		}
+
 		switch cmdName {
 
 		case "exit", "quit":
@@ -300,6 +308,7 @@ func renderTable(result *executor.ExecutionResult) {
 
 	for _, row := range result.Rows {
 		for i, val := range row {
+			val = schema.DisplayValue(val)
 			if i < len(widths) {
 				if len(val) > widths[i] {
 					widths[i] = len(val)
@@ -327,6 +336,7 @@ func renderTable(result *executor.ExecutionResult) {
 	for _, row := range result.Rows {
 		fmt.Print("|")
 		for i, val := range row {
+			val = schema.DisplayValue(val)
 			if i < len(widths) {
 				fmt.Printf(" %-*s |", widths[i], val)
 			}
@@ -197,6 +197,22 @@ func ListUsers() ([]string, error) {
 	return readAllUsers()
 }
 
+// AllUsers mulihkeun sadaya pangguna tanpa hash kecap aksés.
+func AllUsers() ([]*User, error) {
+	lines, err := readAllUsers()
+	if err != nil {
+		return nil, err
+	}
+
+	var users []*User
+	for _, line := range lines {
+		if u, _, err := parseUser(line); err == nil {
+			users = append(users, u)
+		}
+	}
+	return users, nil
+}
+
 func appendToUserFile(line string) error {
 	f, err := os.OpenFile(userFilePath(), os.O_APPEND|os.O_WRONLY, 0644)
 	if err != nil {
@@ -59,3 +59,33 @@ func RequireDBAccess() error {
 	}
 	return nil
 }
+
+// GrantEntry: hiji baris hak aksés dina grants.maung.
+type GrantEntry struct {
+	Username string
+	Role     string
+	Database string
+}
+
+func ListGrants() ([]GrantEntry, error) {
+	path := filepath.Join(config.DataDir, config.SystemDir, config.GrantsFile)
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var grants []GrantEntry
+	sc := bufio.NewScanner(file)
+	for sc.Scan() {
+		p := strings.Split(sc.Text(), "|")
+		if len(p) != 3 {
+			continue
+		}
+		grants = append(grants, GrantEntry{Username: p[0], Role: p[1], Database: p[2]})
+	}
+	return grants, sc.Err()
+}
@@ -2,9 +2,10 @@ package executor
 
 import (
 	"fmt"
-	"math"
-	"strconv"
+	"math/big"
 	"strings"
+
+	"github.com/febrd/maungdb/engine/schema"
 )
 
 type AggregateFunc string
@@ -22,6 +23,10 @@ type ParsedColumn struct {
 	IsAggregate  bool
 	FuncType     AggregateFunc
 	TargetCol    string 
+
+	ColType   string // tipe kolom target, pikeun agregat nu sadar tipe
+	Scale     int    // skala DECIMAL
+	Collation string // kolasi téks pikeun PANGGEDENA/PANGLEUTIKNA
 }
 
 func ParseColumnSelection(rawCol string) ParsedColumn {
@@ -64,55 +69,91 @@ func ParseColumnSelection(rawCol string) ParsedColumn {
 	}
 }
 
+// CalculateAggregate ngitung TOTAL/RATA nganggo big.Rat sangkan teu aya
+// galat pembulatan float (penting pikeun DECIMAL / duit). Nilai NULL
+// dilewat; mun teu aya nilai pisan, hasilna NULL (iwal JUMLAH).
 func CalculateAggregate(rows []map[string]string, parsedCol ParsedColumn) (string, error) {
-	if len(rows) == 0 {
-		return "0", nil
+	if parsedCol.FuncType == FuncCount {
+		if parsedCol.TargetCol == "*" {
+			return fmt.Sprintf("%d", len(rows)), nil
+		}
+		count := 0
+		for _, row := range rows {
+			if val, ok := row[parsedCol.TargetCol]; !ok || !schema.IsNullFor(parsedCol.ColType, val) {
+				count++
+			}
+		}
+		return fmt.Sprintf("%d", count), nil
 	}
 
-	if parsedCol.FuncType == FuncCount {
-		return fmt.Sprintf("%d", len(rows)), nil
+	format := func(r *big.Rat) string {
+		if parsedCol.ColType == "DECIMAL" {
+			return r.FloatString(parsedCol.Scale)
+		}
+		return r.FloatString(2)
 	}
 
-	var sum float64
-	var count float64
-	var maxVal = -math.MaxFloat64
-	var minVal = math.MaxFloat64
-	
-	for _, row := range rows {
-		valStr, ok := row[parsedCol.TargetCol]
-		if !ok {
-			continue 
+	compareType := parsedCol.ColType
+	isNumeric := compareType == "" || compareType == "INT" || compareType == "FLOAT" || compareType == "DECIMAL"
+	if isNumeric {
+		compareType = "DECIMAL"
+	}
+
+	compare := func(a, b string) (int, bool) {
+		if schema.IsTextType(compareType) {
+			return schema.CompareText(parsedCol.Collation, a, b), true
 		}
+		return schema.CompareValues(compareType, a, b)
+	}
+
+	sum := new(big.Rat)
+	count := 0
+	best := ""
 
-		val, err := strconv.ParseFloat(valStr, 64)
-		if err != nil {
-			continue 
+	for _, row := range rows {
+		valStr, ok := row[parsedCol.TargetCol]
+		if !ok || valStr == "" || schema.IsNull(valStr) {
+			continue
 		}
 
 		switch parsedCol.FuncType {
 		case FuncSum, FuncAvg:
-			sum += val
+			val, ok := schema.ParseDecimal(valStr)
+			if !ok {
+				continue
+			}
+			sum.Add(sum, val)
 			count++
-		case FuncMax:
-			if val > maxVal { maxVal = val }
-		case FuncMin:
-			if val < minVal { minVal = val }
+		case FuncMax, FuncMin:
+			if _, ok := compare(valStr, valStr); !ok {
+				continue
+			}
+			if best == "" {
+				best = valStr
+				continue
+			}
+			cmp, _ := compare(valStr, best)
+			if (parsedCol.FuncType == FuncMax && cmp > 0) || (parsedCol.FuncType == FuncMin && cmp < 0) {
+				best = valStr
+			}
 		}
 	}
 
 	switch parsedCol.FuncType {
 	case FuncSum:
-		return fmt.Sprintf("%.2f", sum), nil
+		if count == 0 { return schema.NullValue, nil }
+		return format(sum), nil
 	case FuncAvg:
-		if count == 0 { return "0", nil }
-		return fmt.Sprintf("%.2f", sum/count), nil
-	case FuncMax:
-		if maxVal == -math.MaxFloat64 { return "0", nil }
-		return fmt.Sprintf("%.2f", maxVal), nil
-	case FuncMin:
-		if minVal == math.MaxFloat64 { return "0", nil }
-		return fmt.Sprintf("%.2f", minVal), nil
+		if count == 0 { return schema.NullValue, nil }
+		return format(new(big.Rat).Quo(sum, big.NewRat(int64(count), 1))), nil
+	case FuncMax, FuncMin:
+		if best == "" { return schema.NullValue, nil }
+		if isNumeric {
+			val, _ := schema.ParseDecimal(best)
+			return format(val), nil
+		}
+		return best, nil
 	}
 
 	return "Error", nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,808 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/febrd/maungdb/engine/auth"
+	"github.com/febrd/maungdb/engine/fts"
+	"github.com/febrd/maungdb/engine/indexing"
+	"github.com/febrd/maungdb/engine/parser"
+	"github.com/febrd/maungdb/engine/schema"
+	"github.com/febrd/maungdb/engine/storage"
+	"github.com/febrd/maungdb/engine/transaction"
+	"github.com/febrd/maungdb/engine/trigger"
+	"github.com/febrd/maungdb/engine/view"
+)
+
+var reIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// execAlterTable ngajalankeun ROBIH TABEL. Sadaya baris divalidasi heula
+// ngagunakeun definisi anyar; file .mg jeung .schema ngan diganti mun
+// validasi lulus, janten tabel tetep utuh mun aya nu gagal.
+func execAlterTable(cmd *parser.Command) (*ExecutionResult, error) {
+	user, _ := auth.CurrentUser()
+	if user.Role != "admin" && user.Role != "supermaung" {
+		return nil, errors.New("ngan admin nu tiasa ROBIH TABEL")
+	}
+	if transaction.GetManager().IsActive(user.Username) {
+		return nil, errors.New("JADIKEUN atanapi BATALKEUN transaksi heula samemeh ROBIH TABEL")
+	}
+	if view.IsView(user.Database, cmd.Table) {
+		return nil, fmt.Errorf("'%s' mangrupikeun KACA, sanés tabel", cmd.Table)
+	}
+
+	s, err := schema.Load(user.Database, cmd.Table)
+	if err != nil {
+		return nil, fmt.Errorf("tabel '%s' teu kapanggih: %v", cmd.Table, err)
+	}
+
+	alt := cmd.Alter
+	if alt.Action == parser.AlterRenameTable {
+		return alterRenameTable(user.Database, s, cmd.Table, alt.NewName)
+	}
+
+	rows, err := readTableRows(cmd.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	newDef := &schema.Definition{
+		Columns: append([]schema.Column{}, s.Columns...),
+		Perms:   s.Perms,
+	}
+
+	colIdx := -1
+	if alt.Column != "" {
+		colIdx = s.GetColumnIndex(alt.Column)
+		if colIdx == -1 {
+			return nil, fmt.Errorf("kolom '%s' teu aya di tabel '%s'", alt.Column, cmd.Table)
+		}
+	}
+
+	var message string
+	droppedCol, renamedFrom, renamedTo := "", "", ""
+	resetSequence := int64(-1)
+
+	switch alt.Action {
+	case parser.AlterAddColumn:
+		cols := ParseColumnDefinitions(alt.Definition)
+		if len(cols) != 1 {
+			return nil, errors.New("definisi kolom salah (conto: umur:INT)")
+		}
+		if err := validateColumnDefinitions(cols); err != nil {
+			return nil, err
+		}
+		col := cols[0]
+		if s.GetColumnIndex(col.Name) != -1 {
+			return nil, fmt.Errorf("kolom '%s' parantos aya", col.Name)
+		}
+		if col.IsPrimary && primaryKeyIndex(s) != -1 {
+			return nil, errors.New("tabel parantos gaduh PRIMARY KEY")
+		}
+
+		fill := schema.NullValue
+		switch {
+		case alt.HasDefault:
+			val, err := defaultValue(col, alt.Default)
+			if err != nil {
+				return nil, err
+			}
+			fill = val
+		case col.HasDefault:
+			fill = schema.InputValue(col, col.Default)
+		}
+
+		newDef.Columns = append(newDef.Columns, col)
+		for i := range rows {
+			if col.AutoIncrement {
+				fill = strconv.Itoa(i + 1)
+			}
+			rows[i] = append(rows[i], fill)
+		}
+		if col.AutoIncrement {
+			resetSequence = int64(len(rows))
+		}
+		message = fmt.Sprintf("✅ Kolom '%s' ditambihkeun ka tabel '%s'", col.Name, cmd.Table)
+
+	case parser.AlterDropColumn:
+		if len(s.Columns) == 1 {
+			return nil, errors.New("teu tiasa miceun hiji-hijina kolom. Paké PICEUN TABEL")
+		}
+		if colIdx == 0 {
+			return nil, fmt.Errorf("kolom '%s' dipaké ID baris, teu tiasa dipiceun", alt.Column)
+		}
+		if s.Columns[colIdx].IsPrimary {
+			return nil, fmt.Errorf("kolom '%s' mangrupikeun PRIMARY KEY. Piceun heula konstrainna", alt.Column)
+		}
+		if deps := foreignKeyDependents(user.Database, cmd.Table, alt.Column); len(deps) > 0 {
+			return nil, fmt.Errorf("kolom '%s' dirujuk ku FK: %s", alt.Column, strings.Join(deps, ", "))
+		}
+		if deps := viewsUsingColumn(user.Database, cmd.Table, alt.Column); len(deps) > 0 {
+			return nil, fmt.Errorf("kolom '%s' dipaké ku KACA: %s", alt.Column, strings.Join(deps, ", "))
+		}
+		if deps := triggersUsingColumn(user.Database, cmd.Table, alt.Column); len(deps) > 0 {
+			return nil, fmt.Errorf("kolom '%s' dipaké ku JARAMBAH: %s", alt.Column, strings.Join(deps, ", "))
+		}
+
+		newDef.Columns = append(newDef.Columns[:colIdx:colIdx], newDef.Columns[colIdx+1:]...)
+		for i := range rows {
+			if colIdx < len(rows[i]) {
+				rows[i] = append(rows[i][:colIdx:colIdx], rows[i][colIdx+1:]...)
+			}
+		}
+		droppedCol = alt.Column
+		message = fmt.Sprintf("✅ Kolom '%s' dipiceun tina tabel '%s'", alt.Column, cmd.Table)
+
+	case parser.AlterRenameColumn:
+		if !reIdentifier.MatchString(alt.NewName) {
+			return nil, fmt.Errorf("ngaran kolom '%s' teu valid", alt.NewName)
+		}
+		if s.GetColumnIndex(alt.NewName) != -1 {
+			return nil, fmt.Errorf("kolom '%s' parantos aya", alt.NewName)
+		}
+		newDef.Columns[colIdx].Name = alt.NewName
+		renamedFrom, renamedTo = alt.Column, alt.NewName
+		message = fmt.Sprintf("✅ Kolom '%s' diganti ngaran jadi '%s'", alt.Column, alt.NewName)
+
+	case parser.AlterRetypeColumn:
+		baseType, args := parseTypeAndArgsExecutor(strings.ToUpper(alt.Definition))
+		if !schema.IsValidType(baseType) {
+			return nil, errors.New("tipe data teu didukung: " + baseType)
+		}
+		newDef.Columns[colIdx].Type = baseType
+		newDef.Columns[colIdx].Args = args
+		if err := validateColumnDefinitions(newDef.Columns[colIdx : colIdx+1]); err != nil {
+			return nil, err
+		}
+		// Nilai lami disimpen dina bentuk baku tipe anyar (mis. 10 → 10.00),
+		// sami sareng baris nu disimpen saatosna.
+		for i := range rows {
+			if colIdx < len(rows[i]) {
+				rows[i][colIdx] = schema.NormalizeValue(newDef.Columns[colIdx], rows[i][colIdx])
+			}
+		}
+		message = fmt.Sprintf("✅ Tipe kolom '%s' diganti jadi %s", alt.Column, strings.ToUpper(alt.Definition))
+
+	case parser.AlterAddConstraint, parser.AlterDropConstraint:
+		add := alt.Action == parser.AlterAddConstraint
+		if add && isPrimaryConstraint(alt.Constraint) {
+			if pk := primaryKeyIndex(s); pk != -1 && pk != colIdx {
+				return nil, fmt.Errorf("tabel parantos gaduh PRIMARY KEY di kolom '%s'", s.Columns[pk].Name)
+			}
+		}
+		if err := applyConstraint(&newDef.Columns[colIdx], alt.Constraint, add); err != nil {
+			return nil, err
+		}
+		verb := "ditambihkeun ka"
+		if !add {
+			verb = "dipiceun tina"
+		}
+		message = fmt.Sprintf("✅ Konstrain %s %s kolom '%s'", strings.ToUpper(alt.Constraint), verb, alt.Column)
+
+	default:
+		return nil, fmt.Errorf("aksi ROBIH TABEL teu dikenal: %s", alt.Action)
+	}
+
+	if err := validateColumnDefinitions(newDef.Columns); err != nil {
+		return nil, err
+	}
+	if err := validateTableRows(user.Database, cmd.Table, newDef, rows); err != nil {
+		return nil, fmt.Errorf("ROBIH TABEL dibatalkeun: %v", err)
+	}
+
+	if err := rewriteTable(user.Database, cmd.Table, s, newDef, rows); err != nil {
+		return nil, fmt.Errorf("gagal nulis tabel: %v", err)
+	}
+
+	if resetSequence >= 0 {
+		if err := storage.SetSequence(user.Database, cmd.Table, resetSequence); err != nil {
+			return nil, fmt.Errorf("tabel dirobih, tapi gagal nyetél sequence: %v", err)
+		}
+	}
+	if droppedCol != "" {
+		indexing.GlobalIndexManager.DropIndex(cmd.Table, droppedCol)
+		fts.GlobalFTS.DropIndex(cmd.Table, droppedCol)
+	}
+	if renamedFrom != "" {
+		if err := renameColumnReferences(user.Database, cmd.Table, renamedFrom, renamedTo, s.GetFieldNames()); err != nil {
+			return nil, fmt.Errorf("kolom diganti, tapi gagal ngomean rujukan: %v", err)
+		}
+	}
+	if err := rebuildTableIndexes(cmd.Table, newDef); err != nil {
+		return nil, fmt.Errorf("tabel dirobih, tapi gagal ngawangun deui index: %v", err)
+	}
+
+	return &ExecutionResult{Message: message}, nil
+}
+
+func alterRenameTable(db string, s *schema.Definition, oldName, newName string) (*ExecutionResult, error) {
+	if !reIdentifier.MatchString(newName) {
+		return nil, fmt.Errorf("ngaran tabel '%s' teu valid", newName)
+	}
+	if schema.Exists(db, newName) || view.IsView(db, newName) {
+		return nil, fmt.Errorf("'%s' parantos aya", newName)
+	}
+
+	fields := s.GetFieldNames()
+	idxCols := indexing.GlobalIndexManager.IndexedColumns(oldName, fields)
+	ftsCols := fts.GlobalFTS.IndexedColumns(oldName, fields)
+
+	if err := storage.RenameTable(db, oldName, newName); err != nil {
+		return nil, fmt.Errorf("gagal ganti ngaran file data: %v", err)
+	}
+	if err := schema.Rename(db, oldName, newName); err != nil {
+		storage.RenameTable(db, newName, oldName)
+		return nil, fmt.Errorf("gagal ganti ngaran schema: %v", err)
+	}
+
+	for _, c := range idxCols {
+		indexing.GlobalIndexManager.RenameIndex(oldName, c, newName, c)
+	}
+	for _, c := range ftsCols {
+		fts.GlobalFTS.RenameIndex(oldName, c, newName, c)
+	}
+
+	if err := renameTableReferences(db, oldName, newName); err != nil {
+		return nil, fmt.Errorf("tabel diganti ngaran, tapi gagal ngomean rujukan: %v", err)
+	}
+
+	return &ExecutionResult{
+		Message: fmt.Sprintf("✅ Tabel '%s' diganti ngaran jadi '%s'", oldName, newName),
+	}, nil
+}
+
+func readTableRows(table string) ([][]string, error) {
+	raw, err := storage.ReadAll(table)
+	if err != nil {
+		return nil, err
+	}
+	var rows [][]string
+	for _, r := range raw {
+		if strings.TrimSpace(r) != "" {
+			rows = append(rows, strings.Split(r, "|"))
+		}
+	}
+	return rows, nil
+}
+
+func rewriteTable(db, table string, oldDef, newDef *schema.Definition, rows [][]string) error {
+	lines := make([]string, len(rows))
+	for i, row := range rows {
+		lines[i] = strings.Join(row, "|")
+	}
+
+	staged, err := storage.StageRows(db, table, lines)
+	if err != nil {
+		return err
+	}
+	if err := schema.Save(db, table, newDef); err != nil {
+		storage.DiscardStaged(staged)
+		return err
+	}
+	if err := storage.CommitStaged(db, table, staged); err != nil {
+		schema.Save(db, table, oldDef)
+		storage.DiscardStaged(staged)
+		return err
+	}
+	return nil
+}
+
+// validateTableRows mariksa sakabéh baris ngalawan definisi anyar:
+// tipe data, NOT NULL, PK/UNIQUE (di jero tabel) jeung FK.
+func validateTableRows(db, table string, d *schema.Definition, rows [][]string) error {
+	seen := make(map[int]map[string]bool)
+	parents := make(map[int]*foreignValues)
+
+	for i, col := range d.Columns {
+		if col.IsPrimary || col.IsUnique {
+			seen[i] = make(map[string]bool)
+		}
+		if col.ForeignKey != "" {
+			values, err := loadForeignValues(db, table, col, d)
+			if err != nil {
+				return err
+			}
+			parents[i] = values
+		}
+	}
+
+	for n, row := range rows {
+		if err := d.ValidateRow(strings.Join(row, "|")); err != nil {
+			return fmt.Errorf("baris ka-%d: %v", n+1, err)
+		}
+
+		for i, col := range d.Columns {
+			val := strings.TrimSpace(row[i])
+			isEmpty := schema.IsNullFor(col.Type, val)
+
+			if col.IsNotNull && isEmpty {
+				return fmt.Errorf("baris ka-%d: kolom '%s' teu kenging kosong (NOT NULL)", n+1, col.Name)
+			}
+			if set, ok := seen[i]; ok && !isEmpty {
+				key := schema.CollationKey(col.Collation, val)
+				if set[key] {
+					return fmt.Errorf("baris ka-%d: data '%s' duplikat di kolom '%s'", n+1, val, col.Name)
+				}
+				set[key] = true
+			}
+			if values, ok := parents[i]; ok && !isEmpty && !values.has(val) {
+				return fmt.Errorf("baris ka-%d: data '%s' teu kapanggih di tabel induk '%s'", n+1, val, col.ForeignKey)
+			}
+		}
+
+		if err := validateChecks(d, row); err != nil {
+			return fmt.Errorf("baris ka-%d: %v", n+1, err)
+		}
+	}
+	return nil
+}
+
+// foreignValues: nilai kolom induk FK, dikonci ku fkKey.
+type foreignValues struct {
+	col  schema.Column
+	keys map[string]bool
+}
+
+func (f *foreignValues) has(val string) bool { return f.keys[fkKey(f.col, val)] }
+
+func loadForeignValues(db, table string, col schema.Column, d *schema.Definition) (*foreignValues, error) {
+	parts := strings.Split(col.ForeignKey, ".")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("definisi FK salah di kolom %s (format kedah: tabel.kolom)", col.Name)
+	}
+	parentTable := strings.ToLower(strings.TrimSpace(parts[0]))
+	parentCol := strings.TrimSpace(parts[1])
+
+	parentDef := d
+	if !strings.EqualFold(parentTable, table) {
+		loaded, err := schema.Load(db, parentTable)
+		if err != nil {
+			return nil, fmt.Errorf("tabel induk '%s' teu kapanggih", parentTable)
+		}
+		parentDef = loaded
+	}
+
+	idx := -1
+	for i, c := range parentDef.Columns {
+		if strings.EqualFold(c.Name, parentCol) {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		return nil, fmt.Errorf("kolom '%s' teu aya di tabel induk '%s'", parentCol, parentTable)
+	}
+
+	rows, err := readTableRows(parentTable)
+	if err != nil {
+		return nil, err
+	}
+	values := &foreignValues{col: parentDef.Columns[idx], keys: make(map[string]bool, len(rows))}
+	for _, row := range rows {
+		if idx < len(row) {
+			values.keys[fkKey(values.col, row[idx])] = true
+		}
+	}
+	return values, nil
+}
+
+func isPrimaryConstraint(c string) bool {
+	c = strings.ToUpper(strings.TrimSpace(c))
+	return c == "PK" || c == "PRIMARY" || c == "PRIMARY KEY"
+}
+
+func applyConstraint(col *schema.Column, constraint string, add bool) error {
+	if add {
+		if !schema.ApplyFlag(col, constraint) {
+			return fmt.Errorf("konstrain teu dikenal: %s", constraint)
+		}
+		cols := []schema.Column{*col}
+		if err := validateColumnDefinitions(cols); err != nil {
+			return err
+		}
+		*col = cols[0]
+		return nil
+	}
+
+	c := strings.ToUpper(strings.TrimSpace(constraint))
+	if i := strings.Index(c, "("); i != -1 {
+		c = strings.TrimSpace(c[:i])
+	}
+	if strings.HasPrefix(c, "ON DELETE") || strings.HasPrefix(c, "ON UPDATE") {
+		c = c[:len("ON DELETE")]
+	}
+
+	switch c {
+	case "PK", "PRIMARY", "PRIMARY KEY":
+		col.IsPrimary = false
+		col.IsUnique = false
+		col.IsNotNull = false
+	case "UNIQUE", "NOT NULL", "NOTNULL":
+		if col.IsPrimary {
+			return fmt.Errorf("kolom '%s' mangrupikeun PK, piceun PK heula", col.Name)
+		}
+		if c == "UNIQUE" {
+			col.IsUnique = false
+		} else {
+			col.IsNotNull = false
+		}
+	case "FK":
+		col.ForeignKey = ""
+		col.OnDelete, col.OnUpdate = "", ""
+	case "ON DELETE":
+		col.OnDelete = ""
+	case "ON UPDATE":
+		col.OnUpdate = ""
+	case "AUTO", "AUTO_INCREMENT":
+		col.AutoIncrement = false
+	case "DEFAULT":
+		col.Default, col.HasDefault = "", false
+	case "CHECK":
+		col.Check = ""
+	case "COLLATE", "KOLASI":
+		col.Collation = ""
+	default:
+		return fmt.Errorf("konstrain teu dikenal: %s", constraint)
+	}
+	return nil
+}
+
+func primaryKeyIndex(s *schema.Definition) int {
+	for i, c := range s.Columns {
+		if c.IsPrimary {
+			return i
+		}
+	}
+	return -1
+}
+
+// foreignKeyDependents: daptar "tabel.kolom" nu FK-na nunjuk ka table.col
+// (mun col kosong, sadaya kolom table).
+func foreignKeyDependents(db, table, col string) []string {
+	tables, err := storage.ListTables(db)
+	if err != nil {
+		return nil
+	}
+
+	var deps []string
+	for _, t := range tables {
+		s, err := schema.Load(db, t)
+		if err != nil {
+			continue
+		}
+		for _, c := range s.Columns {
+			parts := strings.Split(c.ForeignKey, ".")
+			if len(parts) != 2 || !strings.EqualFold(strings.TrimSpace(parts[0]), table) {
+				continue
+			}
+			if t == table && col == "" {
+				continue
+			}
+			if col == "" || strings.EqualFold(strings.TrimSpace(parts[1]), col) {
+				deps = append(deps, t+"."+c.Name)
+			}
+		}
+	}
+	return deps
+}
+
+func queryReferencesTable(query, table string) bool {
+	cmd, err := parser.Parse(query)
+	if err != nil || cmd == nil {
+		return false
+	}
+	if cmd.Table == table || cmd.TriggerDef.Table == table {
+		return true
+	}
+	for _, j := range cmd.Joins {
+		if j.Table == table {
+			return true
+		}
+	}
+	return false
+}
+
+func viewsReferencingTable(db, table string) []string {
+	views, err := view.ListViews(db)
+	if err != nil {
+		return nil
+	}
+	var deps []string
+	for _, v := range views {
+		q, err := view.LoadView(db, v)
+		if err == nil && queryReferencesTable(q, table) {
+			deps = append(deps, v)
+		}
+	}
+	return deps
+}
+
+// queryIdent: hiji identifier dina téks query (di luar tanda petik), mis.
+// "kolom" atanapi "tabel.kolom". start/end nunjuk kana bagian name.
+type queryIdent struct {
+	qualifier  string
+	name       string
+	start, end int
+}
+
+// scanIdents mulihkeun sadaya identifier dina query. Téks dina tanda petik,
+// angka, sareng ngaran fungsi (dituturkeun ku "(") dilangkungan.
+func scanIdents(query string) []queryIdent {
+	var idents []queryIdent
+	isWord := func(c byte) bool {
+		return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+	}
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(query[i+1:], c)
+			if end == -1 {
+				return idents
+			}
+			i += end + 2
+			continue
+		case !isWord(c):
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(query) && (isWord(query[i]) || query[i] == '.') {
+			i++
+		}
+		if c >= '0' && c <= '9' {
+			continue
+		}
+		rest := strings.TrimLeft(query[i:], " \t")
+		if strings.HasPrefix(rest, "(") {
+			continue
+		}
+		token := query[start:i]
+		id := queryIdent{name: token, start: start, end: i}
+		if dot := strings.LastIndexByte(token, '.'); dot != -1 {
+			id.qualifier, id.name, id.start = token[:dot], token[dot+1:], start+dot+1
+		}
+		idents = append(idents, id)
+	}
+	return idents
+}
+
+// queryTables: tabel nu dibaca/diserat ku query (tabel utama sareng GABUNG).
+func queryTables(query string) []string {
+	cmd, err := parser.Parse(query)
+	if err != nil || cmd == nil || cmd.Table == "" {
+		return nil
+	}
+	tables := []string{cmd.Table}
+	for _, j := range cmd.Joins {
+		tables = append(tables, j.Table)
+	}
+	return tables
+}
+
+// columnRefs: identifier dina query nu nunjuk ka table.col — "table.col",
+// "<qualifier>.col" (mis. ANYAR dina jarambah tabelna), atanapi "col" polos
+// mun query ngan ukur maca table.
+func columnRefs(query, table, col string, qualifiers ...string) []queryIdent {
+	tables := queryTables(query)
+	bare := len(tables) > 0
+	for _, t := range tables {
+		bare = bare && t == table
+	}
+
+	var refs []queryIdent
+	for _, id := range scanIdents(query) {
+		if id.name != col {
+			continue
+		}
+		switch {
+		case id.qualifier == "":
+			if !bare {
+				continue
+			}
+		case id.qualifier == table:
+		default:
+			known := false
+			for _, q := range qualifiers {
+				known = known || strings.EqualFold(id.qualifier, q)
+			}
+			if !known {
+				continue
+			}
+		}
+		refs = append(refs, id)
+	}
+	return refs
+}
+
+func viewsUsingColumn(db, table, col string) []string {
+	var deps []string
+	for _, v := range viewsReferencingTable(db, table) {
+		q, _ := view.LoadView(db, v)
+		if len(columnRefs(q, table, col)) > 0 {
+			deps = append(deps, v)
+		}
+	}
+	return deps
+}
+
+// triggerColumnRefs: rujukan ka table.col dina aksi jarambah t, kalebet
+// ANYAR.col / HEUBEUL.col mun jarambahna dina table.
+func triggerColumnRefs(t trigger.TriggerAction, table, col string) []queryIdent {
+	if t.Table == table {
+		return columnRefs(t.ActionQL, table, col, rowRefNames...)
+	}
+	return columnRefs(t.ActionQL, table, col)
+}
+
+func triggersUsingColumn(db, table, col string) []string {
+	triggers, err := trigger.GlobalTriggerManager.ListTriggers(db)
+	if err != nil {
+		return nil
+	}
+	var deps []string
+	for _, t := range triggers {
+		if len(triggerColumnRefs(t, table, col)) > 0 {
+			deps = append(deps, t.Name)
+		}
+	}
+	return deps
+}
+
+// replaceRefs ngaganti ngaran unggal rujukan refs ku newCol.
+func replaceRefs(query string, refs []queryIdent, newCol string) string {
+	for i := len(refs) - 1; i >= 0; i-- {
+		query = query[:refs[i].start] + newCol + query[refs[i].end:]
+	}
+	return query
+}
+
+func renameTableInQuery(query, oldTable, newTable string) string {
+	re := regexp.MustCompile(`(^|[^.\w])` + regexp.QuoteMeta(oldTable) + `\b`)
+	return re.ReplaceAllString(query, "${1}"+newTable)
+}
+
+func renameColumnReferences(db, table, oldCol, newCol string, oldFields []string) error {
+	if indexing.GlobalIndexManager.IndexedColumns(table, []string{oldCol}) != nil {
+		if err := indexing.GlobalIndexManager.RenameIndex(table, oldCol, table, newCol); err != nil {
+			return err
+		}
+	}
+	if fts.GlobalFTS.IndexedColumns(table, []string{oldCol}) != nil {
+		if err := fts.GlobalFTS.RenameIndex(table, oldCol, table, newCol); err != nil {
+			return err
+		}
+	}
+
+	if err := rewriteForeignKeys(db, func(fk string) string {
+		parts := strings.Split(fk, ".")
+		if len(parts) == 2 && strings.EqualFold(parts[0], table) && strings.EqualFold(parts[1], oldCol) {
+			return parts[0] + "." + newCol
+		}
+		return fk
+	}); err != nil {
+		return err
+	}
+
+	for _, v := range viewsReferencingTable(db, table) {
+		q, err := view.LoadView(db, v)
+		if err != nil {
+			return err
+		}
+		if err := view.SaveView(db, v, replaceRefs(q, columnRefs(q, table, oldCol), newCol)); err != nil {
+			return err
+		}
+	}
+
+	return rewriteTriggers(db, func(t trigger.TriggerAction) (trigger.TriggerAction, bool) {
+		if t.Table != table && !queryReferencesTable(t.ActionQL, table) {
+			return t, false
+		}
+		t.ActionQL = replaceRefs(t.ActionQL, triggerColumnRefs(t, table, oldCol), newCol)
+		return t, true
+	})
+}
+
+func renameTableReferences(db, oldTable, newTable string) error {
+	if err := rewriteForeignKeys(db, func(fk string) string {
+		parts := strings.Split(fk, ".")
+		if len(parts) == 2 && strings.EqualFold(parts[0], oldTable) {
+			return newTable + "." + parts[1]
+		}
+		return fk
+	}); err != nil {
+		return err
+	}
+
+	for _, v := range viewsReferencingTable(db, oldTable) {
+		q, err := view.LoadView(db, v)
+		if err != nil {
+			return err
+		}
+		if err := view.SaveView(db, v, renameTableInQuery(q, oldTable, newTable)); err != nil {
+			return err
+		}
+	}
+
+	return rewriteTriggers(db, func(t trigger.TriggerAction) (trigger.TriggerAction, bool) {
+		if t.Table != oldTable && !queryReferencesTable(t.ActionQL, oldTable) {
+			return t, false
+		}
+		if t.Table == oldTable {
+			t.Table = newTable
+		}
+		t.ActionQL = renameTableInQuery(t.ActionQL, oldTable, newTable)
+		return t, true
+	})
+}
+
+func rewriteForeignKeys(db string, fn func(fk string) string) error {
+	tables, err := storage.ListTables(db)
+	if err != nil {
+		return err
+	}
+	for _, t := range tables {
+		s, err := schema.Load(db, t)
+		if err != nil {
+			continue
+		}
+		changed := false
+		for i, c := range s.Columns {
+			if c.ForeignKey == "" {
+				continue
+			}
+			if fk := fn(c.ForeignKey); fk != c.ForeignKey {
+				s.Columns[i].ForeignKey = fk
+				changed = true
+			}
+		}
+		if changed {
+			if err := schema.Save(db, t, s); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func rewriteTriggers(db string, fn func(trigger.TriggerAction) (trigger.TriggerAction, bool)) error {
+	triggers, err := trigger.GlobalTriggerManager.ListTriggers(db)
+	if err != nil {
+		return err
+	}
+	for _, t := range triggers {
+		updated, changed := fn(t)
+		if !changed {
+			continue
+		}
+		if err := trigger.GlobalTriggerManager.DeleteTrigger(db, t); err != nil {
+			return err
+		}
+		if err := trigger.GlobalTriggerManager.SaveTrigger(db, updated); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rebuildTableIndexes ngawangun deui sadaya index .idx / .fts tabel,
+// sabab posisi kolom bisa robah sanggeus ROBIH TABEL.
+func rebuildTableIndexes(table string, d *schema.Definition) error {
+	fields := d.GetFieldNames()
+	for _, c := range indexing.GlobalIndexManager.IndexedColumns(table, fields) {
+		if err := indexing.GlobalIndexManager.BuildIndex(table, c, fields); err != nil {
+			return err
+		}
+	}
+	for _, c := range fts.GlobalFTS.IndexedColumns(table, fields) {
+		if err := fts.GlobalFTS.BuildIndex(table, c, fields, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package executor
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/febrd/maungdb/engine/trigger"
+	"github.com/febrd/maungdb/engine/view"
+)
+
+func TestRenameColumnInQuery(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		qualifiers []string
+		want       string
+	}{
+		{"kolom polos", "TINGALI jumlah TI barang", nil, "TINGALI qty TI barang"},
+		{"kualifikasi tabel", "TINGALI barang.jumlah TI barang DIMANA barang.jumlah > 0", nil, "TINGALI barang.qty TI barang DIMANA barang.qty > 0"},
+		{"dina petik", "TINGALI * TI barang DIMANA catetan = 'jumlah'", nil, "TINGALI * TI barang DIMANA catetan = 'jumlah'"},
+		{"kolom tabel séjén", "TINGALI * TI barang GABUNG stok DINA barang.id = stok.jumlah", nil, "TINGALI * TI barang GABUNG stok DINA barang.id = stok.jumlah"},
+		{"polos dina gabungan", "TINGALI jumlah TI barang GABUNG stok DINA barang.id = stok.id", nil, "TINGALI jumlah TI barang GABUNG stok DINA barang.id = stok.id"},
+		{"ngaran fungsi", "TINGALI JUMLAH(jumlah) TI barang", nil, "TINGALI JUMLAH(qty) TI barang"},
+		{"ANYAR dina jarambah", "SIMPEN log (catetan) NILAI ('jumlah ANYAR.jumlah', ANYAR.jumlah)", rowRefNames, "SIMPEN log (catetan) NILAI ('jumlah ANYAR.jumlah', ANYAR.qty)"},
+		{"ANYAR tanpa jarambah", "SIMPEN log (catetan) NILAI (ANYAR.jumlah)", nil, "SIMPEN log (catetan) NILAI (ANYAR.jumlah)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := replaceRefs(tt.query, columnRefs(tt.query, "barang", "jumlah", tt.qualifiers...), "qty")
+			if got != tt.want {
+				t.Errorf("\n got: %s\nwant: %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlterDropColumnGuards(t *testing.T) {
+	run(t,
+		"DAMEL gudang kode:INT, id:INT:PK, ngaran:STRING, jumlah:INT, catetan:STRING",
+		"DAMEL gudang_log id:INT:PK, catetan:STRING",
+		"DAMEL KACA gudang_ngaran TINA TINGALI ngaran TI gudang",
+		"DAMEL JARAMBAH gudang_cek WAKTU SAMEMEH SIMPEN PADA gudang LAKUKAN TOLAK 'jumlah négatif' LAMUN ANYAR.jumlah < 0",
+	)
+
+	tests := []struct {
+		column  string
+		wantErr string
+	}{
+		{"kode", "ID baris"},
+		{"id", "PRIMARY KEY"},
+		{"ngaran", "KACA: gudang_ngaran"},
+		{"jumlah", "JARAMBAH: gudang_cek"},
+		{"catetan", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.column, func(t *testing.T) {
+			_, err := exec("ROBIH TABEL gudang PICEUN KOLOM " + tt.column)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("teu disangka gagal: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("error = %v, kedahna ngandung %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAlterRenameColumnRewritesReferences(t *testing.T) {
+	run(t,
+		"DAMEL rak id:INT:PK, jumlah:INT",
+		"DAMEL rak_log id:INT:PK, jumlah:INT, catetan:STRING",
+		"DAMEL KACA rak_kaca TINA TINGALI jumlah TI rak DIMANA jumlah > 0",
+		"DAMEL KACA rak_log_kaca TINA TINGALI jumlah TI rak_log",
+		"DAMEL JARAMBAH rak_audit WAKTU SANGGEUS SIMPEN PADA rak LAKUKAN SIMPEN rak_log (jumlah, catetan) NILAI (ANYAR.jumlah, 'jumlah anyar')",
+		"ROBIH TABEL rak GANTI NGARAN KOLOM jumlah JADI qty",
+	)
+
+	views := map[string]string{
+		"rak_kaca":     "TINGALI qty TI rak DIMANA qty > 0",
+		"rak_log_kaca": "TINGALI jumlah TI rak_log",
+	}
+	for name, want := range views {
+		got, err := view.LoadView("uji", name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("kaca %s = %q, kedahna %q", name, got, want)
+		}
+	}
+
+	triggers, _ := trigger.GlobalTriggerManager.ListTriggers("uji")
+	found := false
+	for _, tr := range triggers {
+		if tr.Name != "rak_audit" {
+			continue
+		}
+		found = true
+		want := "SIMPEN rak_log (jumlah, catetan) NILAI (ANYAR.qty, 'jumlah anyar')"
+		if tr.ActionQL != want {
+			t.Errorf("jarambah = %q, kedahna %q", tr.ActionQL, want)
+		}
+	}
+	if !found {
+		t.Error("jarambah rak_audit teu kapendak")
+	}
+}
+
+// TestAlterRetypeNormalizesRows: baris lami dinormalisasi kana tipe anyar,
+// janten sami sareng baris nu disimpen saatosna.
+func TestAlterRetypeNormalizesRows(t *testing.T) {
+	run(t,
+		"DAMEL harga id:INT:PK, nilai:INT",
+		"SIMPEN harga NILAI (1, 10), (2, 7)",
+		"ROBIH TABEL harga GANTI TIPE nilai JADI DECIMAL(10,2)",
+		"SIMPEN harga NILAI (3, 10)",
+	)
+
+	cases := []struct {
+		query string
+		want  []string
+	}{
+		{"TINGALI * TI harga", []string{"1|10.00", "2|7.00", "3|10.00"}},
+		{"TINGALI id TI harga DIMANA nilai = '10.00'", []string{"1", "3"}},
+		{"TINGALI BEDA nilai TI harga", []string{"10.00", "7.00"}},
+	}
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			if got := rowsOf(run(t, c.query)); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("hasil = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
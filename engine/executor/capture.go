@@ -0,0 +1,26 @@
+package executor
+
+import (
+	"strings"
+
+	"github.com/febrd/maungdb/engine/auth"
+	"github.com/febrd/maungdb/engine/parser"
+	"github.com/febrd/maungdb/engine/transaction"
+)
+
+// changesData: paréntah nu parantos ngarobih file dina DataDir, janten kedah
+// dicatet kana log réplikasi. SIMPEN/OMEAN/MICEUN di jero transaksi nu masih
+// muka can keuna kana file; éta dicatet nalika JADIKEUN.
+func changesData(cmd *parser.Command) bool {
+	switch cmd.Type {
+	case parser.CmdSetSync:
+		return true
+	case parser.CmdTransaction:
+		arg := strings.ToUpper(cmd.Arg1)
+		return arg == "JADIKEUN" || arg == "COMMIT"
+	case parser.CmdInsert, parser.CmdUpdate, parser.CmdDelete:
+		user, err := auth.CurrentUser()
+		return err != nil || !transaction.GetManager().IsActive(user.Username)
+	}
+	return isSyncedWrite(cmd.Type)
+}
@@ -0,0 +1,45 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/febrd/maungdb/engine/parser"
+)
+
+func TestChangesData(t *testing.T) {
+	cases := []struct {
+		query string
+		want  bool
+	}{
+		{"TINGALI * TI sinkron_uji", false},
+		{"TINGALI REPLIKASI", false},
+		{"PARIKSA FK", false},
+		{`KOREHAN sinkron_uji DINA isi MILARI "x"`, false},
+		{"TOLAK 'eureun'", false},
+		{"SIMPEN sinkron_uji 1|a", true},
+		{"MICEUN TI sinkron_uji DIMANA id = 1", true},
+		{"DAMEL sinkron_uji2 id:INT:PK", true},
+		{"ATUR SINKRON 1", true},
+		{"JADIKEUN", true},
+		{"BATALKEUN", false},
+	}
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			cmd, err := parser.Parse(c.query)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			if got := changesData(cmd); got != c.want {
+				t.Errorf("changesData = %v, want %v", got, c.want)
+			}
+		})
+	}
+
+	// Tulisan di jero transaksi dicatet nalika JADIKEUN, sanés ayeuna.
+	run(t, "DAMEL sinkron_uji id:INT:PK, isi:STRING", "MIMITIAN")
+	defer exec("BATALKEUN")
+	cmd, _ := parser.Parse("SIMPEN sinkron_uji 1|a")
+	if changesData(cmd) {
+		t.Error("SIMPEN dina transaksi teu kedah nyeken DataDir")
+	}
+}
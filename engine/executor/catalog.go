@@ -0,0 +1,324 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/febrd/maungdb/engine/auth"
+	"github.com/febrd/maungdb/engine/fts"
+	"github.com/febrd/maungdb/engine/parser"
+	"github.com/febrd/maungdb/engine/schema"
+	"github.com/febrd/maungdb/engine/storage"
+	"github.com/febrd/maungdb/engine/trigger"
+	"github.com/febrd/maungdb/engine/view"
+)
+
+// Katalog: tabel virtual nu ngan bisa dibaca (maung_katalog.<ngaran>,
+// alias information_schema.<ngaran>). Eusina diwangun unggal query tina
+// file .schema, .view, .idx, .fts, jarambah sareng pangguna, ngan pikeun
+// database nu kénging diaksés ku pangguna.
+const CatalogSchema = "maung_katalog"
+
+type catalogTable struct {
+	columns []string
+	rows    func(user *auth.User) ([][]string, error)
+}
+
+var catalogTables = map[string]catalogTable{
+	"database": {[]string{"database", "aktif"}, catalogDatabases},
+	"tabel":    {[]string{"database", "tabel", "jenis", "jumlah_kolom", "jumlah_baris"}, catalogTablesRows},
+	"kolom": {[]string{"database", "tabel", "kolom", "posisi", "tipe", "pk", "unik", "not_null",
+		"fk", "on_delete", "on_update", "auto", "default", "cek", "kolasi"}, catalogColumns},
+	"indeks":   {[]string{"database", "tabel", "kolom", "jenis", "analyzer"}, catalogIndexes},
+	"kaca":     {[]string{"database", "kaca", "query"}, catalogViews},
+	"jarambah": {[]string{"database", "jarambah", "tabel", "waktu", "kajadian", "aksi", "dijieun"}, catalogTriggers},
+	"pangguna": {[]string{"pangguna", "peran", "database"}, catalogUsers},
+	"hak":      {[]string{"pangguna", "peran", "database"}, catalogGrants},
+}
+
+// catalogAliases: ngaran gaya information_schema.
+var catalogAliases = map[string]string{
+	"schemata": "database",
+	"tables":   "tabel",
+	"columns":  "kolom",
+	"indexes":  "indeks",
+	"views":    "kaca",
+	"triggers": "jarambah",
+	"users":    "pangguna",
+	"grants":   "hak",
+}
+
+// catalogName mulihkeun ngaran tabel katalog (conto: "kolom") pikeun
+// "maung_katalog.kolom" atanapi "information_schema.columns".
+func catalogName(table string) (string, bool) {
+	prefix, name, ok := strings.Cut(strings.ToLower(table), ".")
+	if !ok || (prefix != CatalogSchema && prefix != "information_schema") {
+		return "", false
+	}
+	if alias, ok := catalogAliases[name]; ok {
+		name = alias
+	}
+	_, ok = catalogTables[name]
+	return name, ok
+}
+
+func isCatalogTable(table string) bool {
+	_, ok := catalogName(table)
+	return ok
+}
+
+// MigrationTable: riwayat maung migrate. Tiasa dibaca sapertos tabel biasa,
+// tapi ngan runner migrasi (ExecuteSystem) nu kénging nyerat.
+const MigrationTable = "maung_migrasi"
+
+// systemTableTarget mulihkeun tabel sistem nu bakal dirobah ku cmd (kaasup
+// jarambah dina tabel éta sareng ganti ngaran ka ngaran éta), atanapi "".
+func systemTableTarget(cmd *parser.Command) string {
+	targets := []string{cmd.Table}
+	switch cmd.Type {
+	case parser.CmdCreateTrigger:
+		targets = []string{cmd.TriggerDef.Table}
+	case parser.CmdDrop:
+		if cmd.Drop.Object != parser.DropTable {
+			return ""
+		}
+	case parser.CmdAlterTable:
+		targets = append(targets, cmd.Alter.NewName)
+	}
+	for _, t := range targets {
+		if strings.EqualFold(t, MigrationTable) {
+			return MigrationTable
+		}
+	}
+	return ""
+}
+
+// catalogSource ngawangun sumber baris pikeun TINGALI ti tabel katalog.
+func catalogSource(user *auth.User, table string) (*sliceIter, *schema.Definition, error) {
+	name, ok := catalogName(table)
+	if !ok {
+		return nil, nil, fmt.Errorf("tabel katalog '%s' teu aya", table)
+	}
+	ct := catalogTables[name]
+
+	rows, err := ct.rows(user)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gagal maca katalog: %v", err)
+	}
+
+	var cols []schema.Column
+	for _, c := range ct.columns {
+		cols = append(cols, schema.Column{Name: c, Type: "STRING"})
+	}
+	return &sliceIter{header: ct.columns, rows: rows}, &schema.Definition{Columns: cols}, nil
+}
+
+// visibleDatabases: supermaung ningali sadayana, sésana ngan database nu
+// dipasihkeun (sapertos TINGALI PANGKAL).
+func visibleDatabases(user *auth.User) ([]string, error) {
+	all, err := storage.ListDatabases()
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == "supermaung" {
+		return all, nil
+	}
+
+	var dbs []string
+	for _, db := range all {
+		if db == user.Database || isDBAllowed(user, db) {
+			dbs = append(dbs, db)
+		}
+	}
+	return dbs, nil
+}
+
+// eachTable nyauran fn pikeun unggal tabel (sanés kaca) nu katingali;
+// error ti fn ngeureunkeun léngkahna.
+func eachTable(user *auth.User, fn func(db, table string, s *schema.Definition) error) error {
+	dbs, err := visibleDatabases(user)
+	if err != nil {
+		return err
+	}
+	for _, db := range dbs {
+		tables, err := storage.ListTables(db)
+		if err != nil {
+			continue
+		}
+		sort.Strings(tables)
+		for _, t := range tables {
+			if s, err := schema.Load(db, t); err == nil {
+				if err := fn(db, t, s); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func nullable(val string) string {
+	if val == "" {
+		return schema.NullValue
+	}
+	return val
+}
+
+func catalogDatabases(user *auth.User) ([][]string, error) {
+	dbs, err := visibleDatabases(user)
+	if err != nil {
+		return nil, err
+	}
+	var rows [][]string
+	for _, db := range dbs {
+		rows = append(rows, []string{db, strconv.FormatBool(db == user.Database)})
+	}
+	return rows, nil
+}
+
+func catalogTablesRows(user *auth.User) ([][]string, error) {
+	var rows [][]string
+	err := eachTable(user, func(db, table string, s *schema.Definition) error {
+		count, err := storage.RowCount(db, table)
+		if err != nil {
+			return fmt.Errorf("gagal ngitung baris %s.%s: %v", db, table, err)
+		}
+		rows = append(rows, []string{db, table, "TABEL", strconv.Itoa(len(s.Columns)), strconv.Itoa(count)})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	views, err := catalogViews(user)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range views {
+		rows = append(rows, []string{v[0], v[1], "KACA", schema.NullValue, schema.NullValue})
+	}
+	return rows, nil
+}
+
+func catalogColumns(user *auth.User) ([][]string, error) {
+	var rows [][]string
+	err := eachTable(user, func(db, table string, s *schema.Definition) error {
+		for i, c := range s.Columns {
+			colType := c.Type
+			if len(c.Args) > 0 {
+				colType += "(" + strings.Join(c.Args, ",") + ")"
+			}
+			def := schema.NullValue
+			if c.HasDefault {
+				def = c.Default
+			}
+			collation := c.Collation
+			if collation == "" {
+				collation = schema.CollateBinary
+			}
+			rows = append(rows, []string{
+				db, table, c.Name, strconv.Itoa(i + 1), colType,
+				strconv.FormatBool(c.IsPrimary), strconv.FormatBool(c.IsUnique), strconv.FormatBool(c.IsNotNull),
+				nullable(c.ForeignKey), nullable(c.OnDelete), nullable(c.OnUpdate),
+				strconv.FormatBool(c.AutoIncrement), def, nullable(c.Check), collation,
+			})
+		}
+		return nil
+	})
+	return rows, err
+}
+
+func catalogIndexes(user *auth.User) ([][]string, error) {
+	var rows [][]string
+	err := eachTable(user, func(db, table string, s *schema.Definition) error {
+		for _, c := range s.Columns {
+			base := filepath.Join(storage.DatabasePath(db), table+"_"+c.Name)
+			if _, err := os.Stat(base + ".idx"); err == nil {
+				rows = append(rows, []string{db, table, c.Name, "TANDAIN", schema.NullValue})
+			}
+			if analyzer, err := fts.AnalyzerOf(base + ".fts"); err == nil {
+				rows = append(rows, []string{db, table, c.Name, "INDEKS_TEKS", analyzer})
+			}
+		}
+		return nil
+	})
+	return rows, err
+}
+
+func catalogViews(user *auth.User) ([][]string, error) {
+	dbs, err := visibleDatabases(user)
+	if err != nil {
+		return nil, err
+	}
+	var rows [][]string
+	for _, db := range dbs {
+		names, err := view.ListViews(db)
+		if err != nil {
+			continue
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			query, _ := view.LoadView(db, name)
+			rows = append(rows, []string{db, name, query})
+		}
+	}
+	return rows, nil
+}
+
+func catalogTriggers(user *auth.User) ([][]string, error) {
+	dbs, err := visibleDatabases(user)
+	if err != nil {
+		return nil, err
+	}
+	var rows [][]string
+	for _, db := range dbs {
+		triggers, err := trigger.GlobalTriggerManager.ListTriggers(db)
+		if err != nil {
+			continue
+		}
+		for _, t := range triggers {
+			rows = append(rows, []string{db, t.Name, t.Table, t.When(), t.Event, t.ActionQL, nullable(t.CreatedAt)})
+		}
+	}
+	return rows, nil
+}
+
+// catalogUsers: supermaung ningali sadaya pangguna, sésana ngan dirina.
+// Hash kecap aksés teu pernah dipidangkeun.
+func catalogUsers(user *auth.User) ([][]string, error) {
+	users, err := auth.AllUsers()
+	if err != nil {
+		return nil, err
+	}
+	var rows [][]string
+	for _, u := range users {
+		if user.Role != "supermaung" && u.Username != user.Username {
+			continue
+		}
+		dbs := schema.NullValue
+		if len(u.Databases) > 0 {
+			dbs = strings.Join(u.Databases, ",")
+		}
+		rows = append(rows, []string{u.Username, u.Role, dbs})
+	}
+	return rows, nil
+}
+
+func catalogGrants(user *auth.User) ([][]string, error) {
+	grants, err := auth.ListGrants()
+	if err != nil {
+		return nil, err
+	}
+	var rows [][]string
+	for _, g := range grants {
+		if user.Role != "supermaung" && g.Username != user.Username {
+			continue
+		}
+		rows = append(rows, []string{g.Username, g.Role, g.Database})
+	}
+	return rows, nil
+}
@@ -0,0 +1,144 @@
+package executor
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/febrd/maungdb/engine/parser"
+	"github.com/febrd/maungdb/engine/storage"
+)
+
+func TestMigrationTableReadOnly(t *testing.T) {
+	system := func(q string) {
+		t.Helper()
+		cmd, err := parser.Parse(q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ExecuteSystem(cmd); err != nil {
+			t.Fatalf("%s: %v", q, err)
+		}
+	}
+	system("DAMEL " + MigrationTable + " versi:INT:PK,nama:STRING")
+	system("SIMPEN " + MigrationTable + " 1|awal")
+	run(t, "DAMEL catetan id:INT:PK, isi:STRING")
+
+	denied := []string{
+		"SIMPEN " + MigrationTable + " 2|palsu",
+		"OMEAN " + MigrationTable + " JADI nama = 'x' DIMANA versi = 1",
+		"MICEUN TI " + MigrationTable + " DIMANA versi = 1",
+		"KOSONGKEUN " + MigrationTable,
+		"PICEUN TABEL " + MigrationTable,
+		"ROBIH TABEL " + MigrationTable + " TAMBAH KOLOM x:INT",
+		"ROBIH TABEL catetan GANTI NGARAN JADI " + MigrationTable,
+		"DAMEL JARAMBAH jm WAKTU SANGGEUS SIMPEN PADA " + MigrationTable + " LAKUKAN TOLAK 'x'",
+	}
+	for _, q := range denied {
+		t.Run(q, func(t *testing.T) {
+			_, err := exec(q)
+			if err == nil || !strings.Contains(err.Error(), "maung migrate") {
+				t.Errorf("teu ditolak: %v", err)
+			}
+		})
+	}
+
+	// Jarambah dina tabel séjén ogé teu tiasa nyerat riwayat.
+	run(t, "DAMEL JARAMBAH licik WAKTU SANGGEUS SIMPEN PADA catetan LAKUKAN SIMPEN "+MigrationTable+" 9|licik")
+	if _, err := exec("SIMPEN catetan (id, isi) NILAI (1, 'a')"); err == nil {
+		t.Error("jarambah nyerat ka tabel riwayat migrasi")
+	}
+
+	got := rowsOf(run(t, "TINGALI * TI "+MigrationTable))
+	if want := []string{"1|awal"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("riwayat = %v, want %v", got, want)
+	}
+}
+
+func TestCatalogName(t *testing.T) {
+	cases := []struct {
+		table, want string
+		ok          bool
+	}{
+		{"maung_katalog.kolom", "kolom", true},
+		{"MAUNG_KATALOG.Tabel", "tabel", true},
+		{"information_schema.columns", "kolom", true},
+		{"information_schema.tabel", "tabel", true},
+		{"maung_katalog.euweuh", "euweuh", false},
+		{"kolom", "", false},
+		{"uji.kolom", "", false},
+	}
+	for _, c := range cases {
+		name, ok := catalogName(c.table)
+		if name != c.want || ok != c.ok {
+			t.Errorf("catalogName(%q) = %q, %v; want %q, %v", c.table, name, ok, c.want, c.ok)
+		}
+	}
+}
+
+// TestCatalogQueries: tabel katalog tiasa di-TINGALI
+// (DIMANA, RUNTUYKEUN) sapertos tabel biasa, tapi teu tiasa diserat.
+func TestCatalogQueries(t *testing.T) {
+	run(t,
+		"DAMEL katalog_uji id:INT:PK, ngaran:STRING:UNIQUE:COLLATE(NOCASE), umur:INT:DEFAULT(17)",
+		"SIMPEN katalog_uji NILAI (1, 'Asep', 20), (2, 'Ujang', 30)",
+		"TANDAIN katalog_uji DINA umur",
+		"DAMEL KACA katalog_kaca TINA TINGALI ngaran TI katalog_uji",
+		"DAMEL JARAMBAH katalog_cek WAKTU SAMEMEH SIMPEN PADA katalog_uji LAKUKAN TOLAK 'ngora teuing' LAMUN ANYAR.umur < 10",
+	)
+
+	cases := []struct {
+		query string
+		want  []string
+	}{
+		{"TINGALI tabel, jenis, jumlah_kolom, jumlah_baris TI maung_katalog.tabel DIMANA tabel JIGA 'katalog_%' RUNTUYKEUN tabel",
+			[]string{"katalog_kaca|KACA|\\N|\\N", "katalog_uji|TABEL|3|2"}},
+		{"TINGALI kolom, posisi, tipe, pk, unik, not_null, default, kolasi TI information_schema.columns DIMANA tabel = 'katalog_uji' RUNTUYKEUN posisi",
+			[]string{"id|1|INT|true|true|true|\\N|BINARY", "ngaran|2|STRING|false|true|false|\\N|NOCASE", "umur|3|INT|false|false|false|17|BINARY"}},
+		{"TINGALI kolom, jenis TI maung_katalog.indeks DIMANA tabel = 'katalog_uji'",
+			[]string{"umur|TANDAIN"}},
+		{"TINGALI query TI maung_katalog.kaca DIMANA kaca = 'katalog_kaca'",
+			[]string{"TINGALI ngaran TI katalog_uji"}},
+		{"TINGALI jarambah, tabel, waktu, kajadian TI maung_katalog.jarambah DIMANA tabel = 'katalog_uji'",
+			[]string{"katalog_cek|katalog_uji|BEFORE|INSERT"}},
+		{"TINGALI database, aktif TI maung_katalog.database DIMANA database = 'uji'",
+			[]string{"uji|true"}},
+	}
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			if got := rowsOf(run(t, c.query)); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("hasil = %q, want %q", got, c.want)
+			}
+		})
+	}
+
+	for _, q := range []string{
+		"SIMPEN maung_katalog.tabel 1|2",
+		"MICEUN TI information_schema.tables DIMANA tabel = 'katalog_uji'",
+		"KOSONGKEUN maung_katalog.kolom",
+	} {
+		if _, err := exec(q); err == nil || !strings.Contains(err.Error(), "ngan kénging dibaca") {
+			t.Errorf("%s: err = %v", q, err)
+		}
+	}
+}
+
+// TestCatalogMaungExtension: tabel nu filena .maung kaasup kana katalog
+// kalayan jumlah baris nu leres.
+func TestCatalogMaungExtension(t *testing.T) {
+	run(t,
+		"DAMEL katalog_maung id:INT:PK",
+		"SIMPEN katalog_maung NILAI (1), (2), (3)",
+	)
+	dbPath := storage.DatabasePath("uji")
+	if err := os.Rename(filepath.Join(dbPath, "katalog_maung.mg"), filepath.Join(dbPath, "katalog_maung.maung")); err != nil {
+		t.Fatal(err)
+	}
+
+	got := rowsOf(run(t, "TINGALI jumlah_baris TI maung_katalog.tabel DIMANA tabel = 'katalog_maung'"))
+	if want := []string{"3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("jumlah_baris = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,52 @@
+package executor
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCollation(t *testing.T) {
+	run(t,
+		"DAMEL kolasi_uji id:INT:PK, ngaran:STRING:COLLATE(NOCASE), kota:STRING",
+		"SIMPEN kolasi_uji 1|Asep|Bandung",
+		"SIMPEN kolasi_uji 2|asep|bandung",
+		"SIMPEN kolasi_uji 3|José|Garut",
+		"SIMPEN kolasi_uji 4|jose|garut",
+	)
+	cases := []struct {
+		query string
+		want  []string
+	}{
+		{"TINGALI id TI kolasi_uji DIMANA ngaran = 'ASEP'", []string{"1", "2"}},
+		{"TINGALI id TI kolasi_uji DIMANA kota = 'bandung'", []string{"2"}},
+		{"TINGALI id TI kolasi_uji DIMANA kota = 'bandung' KOLASI NOCASE", []string{"1", "2"}},
+		{"TINGALI id TI kolasi_uji DIMANA ngaran = 'JOSE'", []string{"4"}},
+		{"TINGALI id TI kolasi_uji DIMANA ngaran = 'JOSE' KOLASI ID", []string{"3", "4"}},
+		{"TINGALI BEDA kota TI kolasi_uji", []string{"Bandung", "Garut", "bandung", "garut"}},
+		{"TINGALI BEDA ngaran TI kolasi_uji", []string{"Asep", "José", "jose"}},
+		{"TINGALI BEDA kota TI kolasi_uji KOLASI NOCASE", []string{"Bandung", "Garut"}},
+		{"TINGALI id TI kolasi_uji RUNTUYKEUN kota KOLASI ID", []string{"1", "2", "3", "4"}},
+	}
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			got := rowsOf(run(t, c.query))
+			if strings.Contains(c.query, "BEDA") {
+				// Urutan BEDA teu dijamin; bandingkeun eusina wungkul.
+				got, c.want = sorted(got), sorted(c.want)
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("hasil = %v, want %v", got, c.want)
+			}
+		})
+	}
+
+	if _, err := exec("TINGALI * TI kolasi_uji KOLASI KLINGON"); err == nil {
+		t.Error("kolasi teu dikenal kedah ditolak")
+	}
+	// UNIQUE nuturkeun kolasi kolom.
+	run(t, "DAMEL kolasi_unik id:INT:PK, surel:STRING:UNIQUE:COLLATE(NOCASE)", "SIMPEN kolasi_unik 1|a@b.id")
+	if _, err := exec("SIMPEN kolasi_unik 2|A@B.ID"); err == nil {
+		t.Error("UNIQUE NOCASE narima nilai nu ngan béda hurup gedé")
+	}
+}
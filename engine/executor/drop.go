@@ -0,0 +1,231 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/febrd/maungdb/engine/auth"
+	"github.com/febrd/maungdb/engine/fts"
+	"github.com/febrd/maungdb/engine/indexing"
+	"github.com/febrd/maungdb/engine/parser"
+	"github.com/febrd/maungdb/engine/schema"
+	"github.com/febrd/maungdb/engine/storage"
+	"github.com/febrd/maungdb/engine/transaction"
+	"github.com/febrd/maungdb/engine/trigger"
+	"github.com/febrd/maungdb/engine/view"
+)
+
+func execDrop(cmd *parser.Command) (*ExecutionResult, error) {
+	user, _ := auth.CurrentUser()
+	def := cmd.Drop
+
+	if def.Object == parser.DropDatabase {
+		if user.Role != "supermaung" {
+			return nil, errors.New("ngan supermaung nu tiasa PICEUN PANGKAL")
+		}
+	} else if user.Role != "admin" && user.Role != "supermaung" {
+		return nil, errors.New("ngan admin nu tiasa miceun objek database")
+	}
+	if transaction.GetManager().IsActive(user.Username) {
+		return nil, errors.New("JADIKEUN atanapi BATALKEUN transaksi heula samemeh PICEUN")
+	}
+
+	switch def.Object {
+	case parser.DropTable:
+		return dropTable(user.Database, def)
+	case parser.DropDatabase:
+		return dropDatabase(user, def)
+	case parser.DropView:
+		return dropView(user.Database, def)
+	case parser.DropTrigger:
+		return dropTrigger(user.Database, def)
+	}
+	return nil, fmt.Errorf("objek PICEUN teu dikenal: %s", def.Object)
+}
+
+func skipMissing(def parser.DropDefinition, kind string) (*ExecutionResult, error) {
+	if def.IfExists {
+		return &ExecutionResult{Message: fmt.Sprintf("ℹ️ %s '%s' teu aya, dilewat", kind, def.Name)}, nil
+	}
+	return nil, fmt.Errorf("%s '%s' teu kapanggih", strings.ToLower(kind), def.Name)
+}
+
+func dropTable(db string, def parser.DropDefinition) (*ExecutionResult, error) {
+	table := def.Name
+	if !schema.Exists(db, table) {
+		if view.IsView(db, table) {
+			return nil, fmt.Errorf("'%s' mangrupikeun KACA. Paké PICEUN KACA", table)
+		}
+		return skipMissing(def, "Tabel")
+	}
+
+	if err := tableDependents(db, table); err != nil {
+		return nil, err
+	}
+
+	s, err := schema.Load(db, table)
+	if err != nil {
+		return nil, err
+	}
+	fields := s.GetFieldNames()
+
+	if err := storage.DropTable(db, table); err != nil {
+		return nil, fmt.Errorf("gagal miceun file data: %v", err)
+	}
+	if err := schema.Delete(db, table); err != nil {
+		return nil, fmt.Errorf("gagal miceun schema: %v", err)
+	}
+
+	for _, c := range indexing.GlobalIndexManager.IndexedColumns(table, fields) {
+		indexing.GlobalIndexManager.DropIndex(table, c)
+	}
+	for _, c := range fts.GlobalFTS.IndexedColumns(table, fields) {
+		fts.GlobalFTS.DropIndex(table, c)
+	}
+
+	triggers, _ := trigger.GlobalTriggerManager.ListTriggers(db)
+	for _, t := range triggers {
+		if t.Table == table {
+			trigger.GlobalTriggerManager.DeleteTrigger(db, t)
+		}
+	}
+
+	return &ExecutionResult{Message: fmt.Sprintf("🗑️ Tabel '%s' parantos dipiceun", table)}, nil
+}
+
+// tableDependents nolak PICEUN TABEL mun tabel masih dirujuk ku FK, KACA
+// atanapi JARAMBAH tabel séjén.
+func tableDependents(db, table string) error {
+	var reasons []string
+
+	if deps := foreignKeyDependents(db, table, ""); len(deps) > 0 {
+		reasons = append(reasons, "FK: "+strings.Join(deps, ", "))
+	}
+	if deps := viewsReferencingTable(db, table); len(deps) > 0 {
+		reasons = append(reasons, "KACA: "+strings.Join(deps, ", "))
+	}
+
+	triggers, _ := trigger.GlobalTriggerManager.ListTriggers(db)
+	var trigDeps []string
+	for _, t := range triggers {
+		if t.Table != table && queryReferencesTable(t.ActionQL, table) {
+			trigDeps = append(trigDeps, t.Name+" ("+t.Table+")")
+		}
+	}
+	if len(trigDeps) > 0 {
+		reasons = append(reasons, "JARAMBAH: "+strings.Join(trigDeps, ", "))
+	}
+
+	if len(reasons) > 0 {
+		return fmt.Errorf("'%s' masih dipaké ku %s", table, strings.Join(reasons, "; "))
+	}
+	return nil
+}
+
+func dropDatabase(user *auth.User, def parser.DropDefinition) (*ExecutionResult, error) {
+	if !storage.DatabaseExists(def.Name) {
+		return skipMissing(def, "Database")
+	}
+
+	if err := storage.DropDatabase(def.Name); err != nil {
+		return nil, fmt.Errorf("gagal miceun database: %v", err)
+	}
+
+	if user.Database == def.Name {
+		auth.SetDatabase("")
+	}
+
+	return &ExecutionResult{Message: fmt.Sprintf("🗑️ Database '%s' parantos dipiceun", def.Name)}, nil
+}
+
+func dropView(db string, def parser.DropDefinition) (*ExecutionResult, error) {
+	if !view.IsView(db, def.Name) {
+		return skipMissing(def, "Kaca")
+	}
+
+	if deps := viewsReferencingTable(db, def.Name); len(deps) > 0 {
+		return nil, fmt.Errorf("KACA '%s' masih dipaké ku KACA: %s", def.Name, strings.Join(deps, ", "))
+	}
+
+	if err := view.DeleteView(db, def.Name); err != nil {
+		return nil, err
+	}
+	return &ExecutionResult{Message: fmt.Sprintf("🗑️ Kaca (View) '%s' parantos dipiceun", def.Name)}, nil
+}
+
+func dropTrigger(db string, def parser.DropDefinition) (*ExecutionResult, error) {
+	triggers, err := trigger.GlobalTriggerManager.ListTriggers(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []trigger.TriggerAction
+	for _, t := range triggers {
+		if t.Name == def.Name && (def.Table == "" || t.Table == def.Table) {
+			matches = append(matches, t)
+		}
+	}
+
+	if len(matches) == 0 {
+		return skipMissing(def, "Jarambah")
+	}
+	if len(matches) > 1 {
+		var tables []string
+		for _, t := range matches {
+			tables = append(tables, t.Table+" ("+t.Event+")")
+		}
+		return nil, fmt.Errorf("jarambah '%s' aya di sababaraha tempat: %s. Tambihan PADA <tabel>", def.Name, strings.Join(tables, ", "))
+	}
+
+	if err := trigger.GlobalTriggerManager.DeleteTrigger(db, matches[0]); err != nil {
+		return nil, err
+	}
+	return &ExecutionResult{Message: fmt.Sprintf("🗑️ Jarambah '%s' parantos dipiceun", def.Name)}, nil
+}
+
+// execTruncate (KOSONGKEUN) ngosongkeun eusi tabel tapi ngantepkeun schema,
+// index jeung jarambah. Ditolak mun aya baris anak nu masih ngarujuk.
+func execTruncate(cmd *parser.Command) (*ExecutionResult, error) {
+	user, _ := auth.CurrentUser()
+	if user.Role != "admin" && user.Role != "supermaung" {
+		return nil, errors.New("ngan admin nu tiasa KOSONGKEUN tabel")
+	}
+	if transaction.GetManager().IsActive(user.Username) {
+		return nil, errors.New("JADIKEUN atanapi BATALKEUN transaksi heula samemeh KOSONGKEUN")
+	}
+
+	s, err := schema.Load(user.Database, cmd.Table)
+	if err != nil {
+		return nil, fmt.Errorf("tabel '%s' teu kapanggih", cmd.Table)
+	}
+
+	for _, dep := range foreignKeyDependents(user.Database, cmd.Table, "") {
+		parts := strings.SplitN(dep, ".", 2)
+		child, err := schema.Load(user.Database, parts[0])
+		if err != nil {
+			continue
+		}
+		idx := child.GetColumnIndex(parts[1])
+		rows, err := readTableRows(parts[0])
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			if idx >= 0 && idx < len(row) {
+				if !schema.IsNullFor(child.Columns[idx].Type, row[idx]) {
+					return nil, fmt.Errorf("teu tiasa KOSONGKEUN '%s': data masih dirujuk ku %s", cmd.Table, dep)
+				}
+			}
+		}
+	}
+
+	if err := rewriteTable(user.Database, cmd.Table, s, s, nil); err != nil {
+		return nil, fmt.Errorf("gagal ngosongkeun tabel: %v", err)
+	}
+	if err := rebuildTableIndexes(cmd.Table, s); err != nil {
+		return nil, err
+	}
+
+	return &ExecutionResult{Message: fmt.Sprintf("🧹 Tabel '%s' parantos dikosongkeun", cmd.Table)}, nil
+}
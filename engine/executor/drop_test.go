@@ -0,0 +1,130 @@
+package executor
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/febrd/maungdb/engine/auth"
+	"github.com/febrd/maungdb/internal/config"
+)
+
+// TestDrop: PICEUN ditolak salami objekna masih dipaké, sareng MUN AYA
+// ngalewat objek nu teu aya.
+func TestDrop(t *testing.T) {
+	run(t,
+		"DAMEL lembur id:INT:PK, ngaran:STRING",
+		"DAMEL imah id:INT:PK, lembur_id:INT:FK(lembur.id)",
+		"DAMEL imah_log id:INT:PK, catetan:STRING",
+		"DAMEL KACA lembur_ngaran TINA TINGALI ngaran TI lembur",
+		"DAMEL KACA lembur_ngaran2 TINA TINGALI ngaran TI lembur_ngaran",
+		"DAMEL JARAMBAH imah_audit WAKTU SANGGEUS SIMPEN PADA imah LAKUKAN SIMPEN imah_log (id, catetan) NILAI (ANYAR.id, 'anyar')",
+		"SIMPEN lembur NILAI (1, 'Cibiru')",
+		"SIMPEN imah NILAI (1, 1)",
+	)
+
+	steps := []struct {
+		query string
+		want  string // potongan error; kosong = kedah hasil
+	}{
+		{"PICEUN TABEL lembur", "FK: imah.lembur_id"},
+		{"PICEUN TABEL imah_log", "JARAMBAH: imah_audit (imah)"},
+		{"PICEUN KACA lembur_ngaran", "masih dipaké ku KACA: lembur_ngaran2"},
+		{"PICEUN TABEL lembur_ngaran", "Paké PICEUN KACA"},
+		{"PICEUN TABEL euweuh", "teu kapanggih"},
+		{"PICEUN TABEL MUN AYA euweuh", ""},
+		{"PICEUN KACA lembur_ngaran2", ""},
+		{"PICEUN KACA lembur_ngaran", ""},
+		{"PICEUN JARAMBAH imah_audit PADA imah", ""},
+		{"PICEUN JARAMBAH imah_audit", "teu kapanggih"},
+		{"PICEUN TABEL imah_log", ""},
+		{"PICEUN TABEL imah", ""},
+		{"PICEUN TABEL lembur", ""},
+	}
+	for _, s := range steps {
+		_, err := exec(s.query)
+		switch {
+		case s.want == "" && err != nil:
+			t.Fatalf("%s: %v", s.query, err)
+		case s.want != "" && (err == nil || !strings.Contains(err.Error(), s.want)):
+			t.Fatalf("%s: err = %v, want %q", s.query, err, s.want)
+		}
+	}
+
+	if _, err := exec("TINGALI * TI lembur"); err == nil {
+		t.Error("tabel lembur masih aya saatos dipiceun")
+	}
+	run(t, "DAMEL lembur id:INT:PK, ngaran:STRING")
+	if got := rowsOf(run(t, "TINGALI * TI lembur")); len(got) != 0 {
+		t.Errorf("tabel anyar kedahna kosong, aya %v", got)
+	}
+	run(t, "PICEUN TABEL lembur")
+}
+
+// TestTruncate: KOSONGKEUN ngosongkeun data tapi schema sareng index tetep.
+func TestTruncate(t *testing.T) {
+	run(t,
+		"DAMEL kebon id:INT:PK, ngaran:STRING",
+		"DAMEL tangkal id:INT:PK, kebon_id:INT:FK(kebon.id)",
+		"SIMPEN kebon NILAI (1, 'Ciwidey'), (2, 'Lembang')",
+		"SIMPEN tangkal NILAI (1, 1)",
+	)
+
+	if _, err := exec("KOSONGKEUN kebon"); err == nil || !strings.Contains(err.Error(), "masih dirujuk ku tangkal.kebon_id") {
+		t.Fatalf("KOSONGKEUN kebon err = %v", err)
+	}
+	run(t, "KOSONGKEUN tangkal", "KOSONGKEUN TABEL kebon")
+	if got := rowsOf(run(t, "TINGALI * TI kebon")); len(got) != 0 {
+		t.Fatalf("kebon teu kosong: %v", got)
+	}
+
+	run(t, "SIMPEN kebon NILAI (1, 'Pangalengan')")
+	if _, err := exec("SIMPEN kebon NILAI (1, 'deui')"); err == nil {
+		t.Error("PK kedah tetep dijaga saatos KOSONGKEUN")
+	}
+	if got := rowsOf(run(t, "TINGALI * TI kebon")); !reflect.DeepEqual(got, []string{"1|Pangalengan"}) {
+		t.Errorf("kebon = %v", got)
+	}
+}
+
+// loginAs ngaganti sési ka user anyar nu gaduh role, teras balik deui ka
+// maung saatos tés réngsé.
+func loginAs(t *testing.T, name, role string) {
+	t.Helper()
+	if err := auth.CreateUser(name, "rahasia", role); err != nil {
+		t.Fatal(err)
+	}
+	if err := auth.SetUserDatabases(name, []string{"uji"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := auth.Login(name, "rahasia"); err != nil {
+		t.Fatal(err)
+	}
+	if err := auth.SetDatabase("uji"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		auth.Login(config.DefaultUser, config.DefaultPass)
+		auth.SetDatabase("uji")
+	})
+}
+
+// TestDropRequiresAdmin: ngan admin nu tiasa PICEUN/KOSONGKEUN, sareng
+// PICEUN PANGKAL ngan kanggo supermaung.
+func TestDropRequiresAdmin(t *testing.T) {
+	cases := []struct {
+		name, role, query, want string
+	}{
+		{"piceun_user", "user", "PICEUN TABEL MUN AYA euweuh", "ngan admin"},
+		{"kosongkeun_user", "user", "KOSONGKEUN euweuh", "ngan admin"},
+		{"piceun_admin", "admin", "PICEUN PANGKAL MUN AYA euweuh", "ngan supermaung"},
+	}
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			loginAs(t, c.name, c.role)
+			if _, err := exec(c.query); err == nil || !strings.Contains(err.Error(), c.want) {
+				t.Errorf("err = %v, want %q", err, c.want)
+			}
+		})
+	}
+}
@@ -2,9 +2,9 @@ package executor
 
 import (
 	"os"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -35,10 +35,53 @@ type ExecutionResult struct {
 	TimeTaken string     `json:"time_taken"` 
 }
 
+// MarshalJSON ngirim NULL salaku null JSON, sanés panyiri internalna.
+func (r ExecutionResult) MarshalJSON() ([]byte, error) {
+	var rows [][]*string
+	if r.Rows != nil {
+		rows = make([][]*string, len(r.Rows))
+	}
+	for i, row := range r.Rows {
+		rows[i] = make([]*string, len(row))
+		for j := range row {
+			if !schema.IsNull(row[j]) {
+				rows[i][j] = &row[j]
+			}
+		}
+	}
+
+	type plain ExecutionResult
+	return json.Marshal(struct {
+		plain
+		Rows [][]*string `json:"rows"`
+	}{plain(r), rows})
+}
+
 func Execute(cmd *parser.Command) (*ExecutionResult, error) {
     start := time.Now() 
     res, err := executeInternal(cmd)
 
+    // Parobahan file langsung dicatet kana log réplikasi (mun aktip). Paréntah
+    // maca teu kedah nyeken DataDir; tulisan dina transaksi dicatet nalika JADIKEUN.
+    if err == nil && changesData(cmd) {
+        if capErr := replication.GlobalReplication.Capture(); capErr != nil {
+            fmt.Println("⚠️ Réplikasi: gagal nyatet parobahan:", capErr)
+        }
+    }
+
+    // Réplikasi sinkron: paréntah tulis di luar transaksi ngantosan ANAK
+    // numutkeun aturan database (transaksi diantosan dina JADIKEUN).
+    if err == nil && isSyncedWrite(cmd.Type) {
+        if user, uErr := auth.CurrentUser(); uErr == nil && !transaction.GetManager().IsActive(user.Username) {
+            // ErrNotReplicated dipulangkeun babarengan sareng res: datana parantos komit.
+            note, syncErr := awaitReplicas(user.Database, nil)
+            if note != "" && res != nil {
+                res.Message = strings.TrimSpace(res.Message + " " + note)
+            }
+            err = syncErr
+        }
+    }
+
     elapsed := time.Since(start)
 
     if res != nil {
@@ -51,12 +94,27 @@ func Execute(cmd *parser.Command) (*ExecutionResult, error) {
     return res, err
 }
 
+// ExecuteSystem ngajalankeun cmd salaku runner internal (maung migrate),
+// nu kénging nyerat ka tabel sistem sapertos MigrationTable.
+func ExecuteSystem(cmd *parser.Command) (*ExecutionResult, error) {
+	cmd.System = true
+	return Execute(cmd)
+}
+
 func executeInternal(cmd *parser.Command) (*ExecutionResult, error) {
-	isWriteOp := (cmd.Type == parser.CmdInsert || cmd.Type == parser.CmdUpdate || cmd.Type == parser.CmdDelete)
+	isWriteOp := (cmd.Type == parser.CmdInsert || cmd.Type == parser.CmdUpdate || cmd.Type == parser.CmdDelete || cmd.Type == parser.CmdAlterTable ||
+		cmd.Type == parser.CmdDrop || cmd.Type == parser.CmdTruncate || cmd.Type == parser.CmdCreate || cmd.Type == parser.CmdCreateView ||
+		cmd.Type == parser.CmdCreateTrigger || cmd.Type == parser.CmdIndex || cmd.Type == "CREATE_FTS" || cmd.Type == parser.CmdSetSync)
 	if isWriteOp {
 		if err := replication.GlobalReplication.CanWrite(); err != nil {
 			return nil, err
 		}
+		if isCatalogTable(cmd.Table) {
+			return nil, fmt.Errorf("'%s' mangrupikeun katalog, ngan kénging dibaca", cmd.Table)
+		}
+		if t := systemTableTarget(cmd); t != "" && !cmd.System {
+			return nil, fmt.Errorf("'%s' dikokolakeun ku maung migrate, ngan kénging dibaca", t)
+		}
 	}
 
 	switch cmd.Type {
@@ -81,13 +139,32 @@ func executeInternal(cmd *parser.Command) (*ExecutionResult, error) {
 		return execCreateTrigger(cmd)
 	case parser.CmdIndex:
 		return execIndex(cmd)
+	case parser.CmdAlterTable:
+		return execAlterTable(cmd)
+	case parser.CmdDrop:
+		return execDrop(cmd)
+	case parser.CmdTruncate:
+		return execTruncate(cmd)
+	case parser.CmdCheckFK:
+		return execCheckFK(cmd)
+	case parser.CmdReject:
+		return execReject(cmd)
 
 	// [FIX 1] Case-case ini sekarang ada DI DALAM block switch
 	case "JADI_INDUNG":
-		replication.GlobalReplication.SetMaster()
-		return &ExecutionResult{Message: "👑 Mode Berubah: INDUNG (Master). Tiasa nulis data."}, nil
+		note, err := replication.GlobalReplication.Promote(cmd.Arg1 == "PAKSA")
+		if err != nil {
+			return nil, err
+		}
+		return &ExecutionResult{Message: strings.TrimSpace("👑 Mode Berubah: INDUNG (Master). Tiasa nulis data. " + note)}, nil
+	case parser.CmdShowReplication:
+		return execShowReplication()
+	case parser.CmdSetSync:
+		return execSetSync(cmd)
 	case "JADI_ANAK":
-		replication.GlobalReplication.SetSlave(cmd.Arg1)
+		if err := replication.GlobalReplication.SetSlave(cmd.Arg1); err != nil {
+			return nil, err
+		}
 		return &ExecutionResult{Message: fmt.Sprintf("👶 Mode Berubah: ANAK (Slave). Ngintil ka %s.", cmd.Arg1)}, nil
 	case "CREATE_FTS":
 		return execCreateFTS(cmd)
@@ -98,31 +175,6 @@ func executeInternal(cmd *parser.Command) (*ExecutionResult, error) {
 	return nil, fmt.Errorf("paréntah teu dikenal: %s", cmd.Type)
 }
 
-func runTriggers(dbName, table, event string) {
-    triggers, err := trigger.GlobalTriggerManager.GetTriggers(dbName, table, event)
-    if err != nil || len(triggers) == 0 {
-        return
-    }
-
-    fmt.Printf("⚡ [JARAMBAH] Ngajalankeun %d trigger keur %s di %s...\n", len(triggers), event, table)
-
-    for _, t := range triggers {
-        cmd, err := parser.Parse(t.ActionQL)
-        if err != nil {
-            fmt.Printf("❌ Trigger '%s' gagal parse: %v\n", t.Name, err)
-            continue
-        }
-
-        _, err = Execute(cmd) 
-        if err != nil {
-            fmt.Printf("❌ Trigger '%s' gagal eksekusi: %v\n", t.Name, err)
-        } else {
-             fmt.Printf("✅ Trigger '%s' suksés!\n", t.Name)
-        }
-    }
-}
-
-
 func execCreateFTS(cmd *parser.Command) (*ExecutionResult, error) {
 	user, _ := auth.CurrentUser()
 	s, err := schema.Load(user.Database, cmd.Table)
@@ -130,22 +182,44 @@ func execCreateFTS(cmd *parser.Command) (*ExecutionResult, error) {
 		return nil, err
 	}
 	
-	err = fts.GlobalFTS.BuildIndex(cmd.Table, cmd.Column, s.GetFieldNames())
+	an, err := fts.NewAnalyzer(cmd.Arg1)
+	if err != nil {
+		return nil, err
+	}
+
+	err = fts.GlobalFTS.BuildIndex(cmd.Table, cmd.Column, s.GetFieldNames(), an.Name())
 	if err != nil {
 		return nil, fmt.Errorf("gagal nyieun indeks teks: %v", err)
 	}
 
-	return &ExecutionResult{Message: fmt.Sprintf("📚 Indeks Teks (Korehan) parantos didamel kanggo %s.%s", cmd.Table, cmd.Column)}, nil
+	return &ExecutionResult{Message: fmt.Sprintf("📚 Indeks Teks (Korehan) parantos didamel kanggo %s.%s (analyzer: %s)", cmd.Table, cmd.Column, an.Name())}, nil
 }
 
 func execFTS(cmd *parser.Command) (*ExecutionResult, error) {
-	rowIDs, err := fts.GlobalFTS.Search(cmd.Table, cmd.Column, cmd.Arg1)
+	user, _ := auth.CurrentUser()
+	s, err := schema.Load(user.Database, cmd.Table)
+	if err != nil {
+		return nil, fmt.Errorf("tabel '%s' teu kapanggih", cmd.Table)
+	}
+	if !s.Can(user.Role, "read") {
+		return nil, errors.New("akses ditolak: anjeun teu boga hak maca tabel ieu")
+	}
+
+	opts := cmd.Search
+	if opts == nil {
+		opts = &parser.SearchOptions{}
+	}
+	res, err := fts.GlobalFTS.Search(cmd.Table, cmd.Column, cmd.Arg1, fts.Options{Fuzzy: opts.Fuzzy})
 	if err != nil {
 		return nil, err
 	}
 
-	if len(rowIDs) == 0 {
-		return &ExecutionResult{Message: "Teu aya hasil nu kapendak."}, nil
+	columns := append(s.GetFieldNames(), relevanceColumn(cmd.Column))
+	if opts.Highlight {
+		columns = append(columns, snippetColumn(cmd.Column))
+	}
+	if len(res.Hits) == 0 {
+		return &ExecutionResult{Columns: columns, Rows: [][]string{}, Message: "Teu aya hasil nu kapendak."}, nil
 	}
 
 	rawRows, err := storage.ReadAll(cmd.Table)
@@ -153,28 +227,39 @@ func execFTS(cmd *parser.Command) (*ExecutionResult, error) {
 		return nil, err
 	}
 
-	var results [][]string
-	
-	idMap := make(map[string]bool)
-	for _, id := range rowIDs {
-		idMap[id] = true
-	}
-
-	count := 0
+	byID := make(map[string][]string)
 	for _, raw := range rawRows {
 		if raw == "" {
 			continue
 		}
 		parts := strings.Split(raw, "|")
-		
-		if idMap[parts[0]] {
-			results = append(results, parts)
-			count++
+		byID[parts[0]] = parts
+	}
+
+	// Baris diruntuykeun dumasar relevansi, skor BM25 saatos kolom tabel,
+	// dituturkeun ku cuplikan mun SOROT.
+	colIdx := s.GetColumnIndex(cmd.Column)
+	highlight := fts.Highlight{Pre: opts.Pre, Post: opts.Post, Fragment: opts.Fragment}
+	var results [][]string
+	for _, hit := range res.Hits {
+		parts, ok := byID[hit.RowID]
+		if !ok {
+			continue
 		}
+		row := append(parts, strconv.FormatFloat(hit.Score, 'f', 4, 64))
+		if opts.Highlight {
+			text := ""
+			if colIdx >= 0 && colIdx < len(parts) && parts[colIdx] != schema.NullValue {
+				text = parts[colIdx]
+			}
+			row = append(row, res.Snippet(text, highlight))
+		}
+		results = append(results, row)
 	}
 
 	return &ExecutionResult{
-		Message: fmt.Sprintf("%d hasil kapendak pikeun kata kunci '%s'", count, cmd.Arg1),
+		Columns: columns,
+		Message: fmt.Sprintf("%d hasil kapendak pikeun kata kunci '%s'", len(results), cmd.Arg1),
 		Rows:    results,
 	}, nil
 }
@@ -188,11 +273,18 @@ func execCreateTrigger(cmd *parser.Command) (*ExecutionResult, error) {
     if evt == "SIMPEN" { evt = "INSERT" }
     if evt == "OMEAN" { evt = "UPDATE" }
     if evt == "MICEUN" { evt = "DELETE" }
+    if evt != "INSERT" && evt != "UPDATE" && evt != "DELETE" {
+        return nil, fmt.Errorf("event jarambah '%s' teu dikenal (SIMPEN, OMEAN, MICEUN)", def.Event)
+    }
+
+    timing := def.Timing
+    if timing == "" { timing = trigger.TimingAfter }
 
     t := trigger.TriggerAction{
         Name:     def.Name,
         Event:    evt,
         Table:    def.Table,
+        Timing:   timing,
         ActionQL: def.ActionQL,
         CreatedAt: time.Now().Format(time.RFC3339),
     }
@@ -203,7 +295,7 @@ func execCreateTrigger(cmd *parser.Command) (*ExecutionResult, error) {
     }
 
     return &ExecutionResult{
-        Message: fmt.Sprintf("✅ Jarambah '%s' parantos dijieun keur tabel '%s'", def.Name, def.Table),
+        Message: fmt.Sprintf("✅ Jarambah '%s' (%s %s) parantos dijieun keur tabel '%s'", def.Name, timingName(timing), eventVerb(evt), def.Table),
     }, nil
 }
 
@@ -314,16 +406,28 @@ func execTransaction(cmd *parser.Command) (*ExecutionResult, error) {
         if user == nil { return nil, errors.New("kedah login heula") }
         txID, err := tm.Begin(user.Username)
         if err != nil { return nil, err }
-        return &ExecutionResult{Message: fmt.Sprintf("🏁 Transaksi dimimitian (ID: %s)", txID)}, nil
+        setTxSync(user.Username, cmd.Sync)
+        msg := fmt.Sprintf("🏁 Transaksi dimimitian (ID: %s)", txID)
+        if cmd.Sync != nil && cmd.Sync.Replicas > 0 {
+            msg += fmt.Sprintf(", JADIKEUN ngantosan %d ANAK", cmd.Sync.Replicas)
+        }
+        return &ExecutionResult{Message: msg}, nil
 
     case "JADIKEUN", "COMMIT":
         if user == nil { return nil, errors.New("kedah login heula") }
+        override := popTxSync(user.Username)
+        pending := tm.Pending(user.Username)
         err := tm.Commit(user.Username)
         if err != nil { return nil, err }
-        return &ExecutionResult{Message: "✅ Transaksi SUKSES disimpen (Committed)"}, nil
+        refreshRowIndexes(walOps(pending), func(table string) (*schema.Definition, error) {
+            return schema.Load(user.Database, table)
+        })
+        note, err := awaitReplicas(user.Database, override)
+        return &ExecutionResult{Message: strings.TrimSpace("✅ Transaksi SUKSES disimpen (Committed) " + note)}, err
 
     case "BATALKEUN", "ROLLBACK":
         if user == nil { return nil, errors.New("kedah login heula") }
+        setTxSync(user.Username, nil)
         err := tm.Rollback(user.Username)
         if err != nil { return nil, err }
         return &ExecutionResult{Message: "✅ Transaksi dibatalkeun (Rolled Back)"}, nil
@@ -341,6 +445,9 @@ func execCreate(cmd *parser.Command) (*ExecutionResult, error) {
 	if len(columns) == 0 {
 		return nil, errors.New("gagal membuat tabel: tidak ada definisi kolom")
 	}
+	if err := validateColumnDefinitions(columns); err != nil {
+		return nil, err
+	}
 
 	perms := map[string][]string{
 		"read":  {"user", "admin", "supermaung"},
@@ -366,7 +473,7 @@ func ParseColumnDefinitions(input string) []schema.Column {
 	rawDefs := splitColumns(input)
 
 	for _, def := range rawDefs {
-		parts := strings.Split(def, ":")
+		parts := schema.SplitDefinition(def)
 		
 		if len(parts) < 2 { continue }
 
@@ -380,21 +487,8 @@ func ParseColumnDefinitions(input string) []schema.Column {
 			Args: args,
 		}
 
-		if len(parts) > 2 {
-			for _, constraintRaw := range parts[2:] {
-				c := strings.ToUpper(strings.TrimSpace(constraintRaw))
-				switch {
-				case c == "PRIMARY" || c == "PK" || c == "PRIMARY KEY":
-					col.IsPrimary = true; col.IsNotNull = true; col.IsUnique = true
-				case c == "UNIQUE":
-					col.IsUnique = true
-				case c == "NOT NULL" || c == "NOTNULL":
-					col.IsNotNull = true
-				case strings.HasPrefix(c, "FK(") && strings.HasSuffix(c, ")"):
-					inner := c[3 : len(c)-1]
-					col.ForeignKey = inner
-				}
-			}
+		for _, constraintRaw := range parts[2:] {
+			schema.ApplyFlag(&col, constraintRaw)
 		}
 		columns = append(columns, col)
 	}
@@ -456,46 +550,94 @@ func execInsert(cmd *parser.Command) (*ExecutionResult, error) {
     if err != nil { return nil, err }
     if !s.Can(user.Role, "write") { return nil, errors.New("akses ditolak: anjeun teu boga hak nulis ka tabel ieu") }
 
-    if err := s.ValidateRow(cmd.Data); err != nil { return nil, err }
-    if err := ValidateConstraints(s, cmd.Table, cmd.Data); err != nil {
-        return nil, fmt.Errorf("gagal validasi data: %v", err)
+    ret, err := newReturning(s, cmd.Returning)
+    if err != nil { return nil, err }
+
+    rawRows, err := insertRows(s, cmd)
+    if err != nil { return nil, err }
+    if len(rawRows) == 0 {
+        return ret.result(fmt.Sprintf("✅ Teu aya data nu asup ka table '%s'", cmd.Table)), nil
     }
 
-    tm := transaction.GetManager()
-    if tm.IsActive(user.Username) {
-        err := tm.AddOperation(user.Username, transaction.OpInsert, cmd.Table, cmd.Data, "")
-        if err != nil {
-            return nil, fmt.Errorf("gagal nambah ke transaksi: %v", err)
-        }
+    rows := make([]string, 0, len(rawRows))
+    for i, raw := range rawRows {
+        data, err := completeRow(user.Database, cmd.Table, s, raw)
+        if err == nil { err = s.ValidateRow(data) }
+        if err != nil { return nil, rowError(i, len(rawRows), err) }
+        rows = append(rows, data)
+    }
 
-        return &ExecutionResult{
-            Message: "✅ Data disimpen samentawis (nunggu JADIKEUN)",
-        }, nil
+    if cmd.Insert.OnConflict != nil {
+        return execUpsert(user, s, cmd, rows, ret)
     }
 
-    if err := storage.Append(cmd.Table, cmd.Data); err != nil { 
-        return nil, fmt.Errorf("gagal nulis ka disk: %v", err) 
+    // Sadaya baris divalidasi heula; mun aya hiji nu gagal, teu aya nu ditulis.
+    var keys *uniqueKeys
+    if len(rows) > 1 { keys = newUniqueKeys(s, cmd.Table) }
+    for i, data := range rows {
+        if err := validateConstraints(s, cmd.Table, data, keys); err != nil {
+            return nil, rowError(i, len(rows), fmt.Errorf("gagal validasi data: %v", err))
+        }
+        ret.add(strings.Split(data, "|"))
     }
+    cmd.Data = rows[0]
 
-    go func() {
-        indexing.GlobalIndexManager.UpdateIndexOnInsert(cmd.Table, cmd.Data, s.GetFieldNames())
-    }()
-    
-	go runTriggers(user.Database, cmd.Table, "INSERT")
+    ops := make([]planOp, len(rows))
+    for i, data := range rows {
+        ops[i] = planOp{Type: transaction.OpInsert, Table: cmd.Table, Data: data}
+    }
 
-    return &ExecutionResult{
-        Message: fmt.Sprintf("✅ Data asup ka table '%s'", cmd.Table),
-    }, nil
+    tm := transaction.GetManager()
+    inTx := tm.IsActive(user.Username)
+    err = withTriggers(user, cmd.TriggerDepth, planEvents(ops), func() error {
+        // Transaksi implisit jarambah ogé ngaliwatan jalur ieu.
+        if tm.IsActive(user.Username) {
+            for _, data := range rows {
+                if err := tm.AddOperation(user.Username, transaction.OpInsert, cmd.Table, data, ""); err != nil {
+                    return fmt.Errorf("gagal nambah ke transaksi: %v", err)
+                }
+            }
+            return nil
+        }
+
+        if err := storage.AppendRows(cmd.Table, rows); err != nil {
+            return fmt.Errorf("gagal nulis ka disk: %v", err)
+        }
+        refreshRowIndexes(ops, func(string) (*schema.Definition, error) { return s, nil })
+        return nil
+    })
+    if err != nil { return nil, err }
+
+    if inTx {
+        msg := "✅ Data disimpen samentawis (nunggu JADIKEUN)"
+        if len(rows) > 1 { msg = fmt.Sprintf("✅ %d data disimpen samentawis (nunggu JADIKEUN)", len(rows)) }
+        return ret.result(msg), nil
+    }
+
+    msg := fmt.Sprintf("✅ Data asup ka table '%s'", cmd.Table)
+    if len(rows) > 1 { msg = fmt.Sprintf("✅ %d data asup ka table '%s'", len(rows), cmd.Table) }
+    return ret.result(msg), nil
 }
 
 func execSelect(cmd *parser.Command) (*ExecutionResult, error) {
     user, _ := auth.CurrentUser()
-    var mainRaw []string
+    var source RowIterator
     var sMain *schema.Definition
-    
+    var scan *scanIter
+
+    search, err := planSearch(cmd, user.Database)
+    if err != nil { return nil, err }
+
     isView := view.IsView(user.Database, cmd.Table)
 
-    if isView {
+    if isCatalogTable(cmd.Table) {
+
+        src, def, err := catalogSource(user, cmd.Table)
+        if err != nil { return nil, err }
+        sMain = def
+        source = src
+
+    } else if isView {
 
         viewQueryStr, err := view.LoadView(user.Database, cmd.Table)
         if err != nil { return nil, fmt.Errorf("gagal maca kaca '%s': %v", cmd.Table, err) }
@@ -504,19 +646,18 @@ func execSelect(cmd *parser.Command) (*ExecutionResult, error) {
         viewRes, err := execSelect(viewCmd)
         if err != nil { return nil, fmt.Errorf("error nalika muka kaca: %v", err) }
 
-        for _, row := range viewRes.Rows {
-            mainRaw = append(mainRaw, strings.Join(row, "|"))
-        }
-
         virtualCols := []schema.Column{}
+        var viewHeader []string
         for _, colName := range viewRes.Columns {
             cleanName := colName
             if parts := strings.Split(colName, "."); len(parts) > 1 {
                 cleanName = parts[1]
             }
             virtualCols = append(virtualCols, schema.Column{Name: cleanName, Type: "STRING"})
+            viewHeader = append(viewHeader, cmd.Table+"."+cleanName)
         }
         sMain = &schema.Definition{Columns: virtualCols}
+        source = &sliceIter{header: viewHeader, rows: viewRes.Rows}
 
     } else {
 
@@ -529,131 +670,84 @@ func execSelect(cmd *parser.Command) (*ExecutionResult, error) {
         }
         sMain = s
 
-        mainRaw, err = storage.ReadAll(cmd.Table)
-        if err != nil { return nil, err }
-    }
-
-    var indexedPKs map[string]bool = nil 
-    
-    if !isView && len(cmd.Where) == 1 && cmd.Where[0].Operator == "=" {
-        cond := cmd.Where[0]
-        pks, err := indexing.GlobalIndexManager.Lookup(cmd.Table, cond.Field, cond.Value)
-        if err == nil {
-            indexedPKs = make(map[string]bool)
-            for _, pk := range pks { indexedPKs[pk] = true }
-             fmt.Printf("⚡ [OPTIMIZER] Index Scan on table '%s'\n", cmd.Table)
+        var indexedPKs map[string]bool
+        if len(cmd.Where) == 1 {
+            cond := cmd.Where[0]
+            if idxMap, err := indexing.GlobalIndexManager.LoadIndex(cmd.Table, cond.Field); err == nil {
+                collation := cmd.Collation
+                if i := s.GetColumnIndex(cond.Field); collation == "" && i != -1 {
+                    collation = s.Columns[i].Collation
+                }
+                if collation == schema.CollateBinary { collation = "" }
+                if pks, ok := indexCandidates(idxMap, cond, collation); ok {
+                    indexedPKs = pks
+                    fmt.Printf("⚡ [OPTIMIZER] Index Scan on table '%s'\n", cmd.Table)
+                }
+            }
         }
-    }
-
-    var currentHeader []string
-    mainCols := sMain.GetFieldNames()
-    for _, col := range mainCols {
-        currentHeader = append(currentHeader, cmd.Table+"."+col)
-    }
 
-    var currentRows [][]string
-    for _, row := range mainRaw {
-        if strings.TrimSpace(row) == "" { continue }
-        parts := strings.Split(row, "|")
-        
-        if indexedPKs != nil {
-            if len(parts) > 0 {
-                if !indexedPKs[parts[0]] { continue }
+        if search != nil {
+            if ids := search.candidates(cmd); ids != nil {
+                indexedPKs = ids
+                fmt.Printf("⚡ [OPTIMIZER] Text Index Scan on table '%s'\n", cmd.Table)
             }
         }
-        currentRows = append(currentRows, parts)
-    }
 
-    if len(currentRows) == 0 && len(cmd.Joins) == 0 && !isAggregateCheck(cmd.Fields) {
-        return &ExecutionResult{Columns: sMain.GetFieldNames(), Rows: [][]string{}, Message: "Data kosong"}, nil
+        scan = newScanIter(cmd.Table, s, indexedPKs)
+        source = scan
     }
 
+    colDefs := make(map[string]schema.Column)
+    addColumnDefs(colDefs, cmd.Table, sMain)
+
+    it := source
     for _, join := range cmd.Joins {
         targetSchema, err := schema.Load(user.Database, join.Table)
         if err != nil { return nil, fmt.Errorf("tabel join '%s' teu kapanggih", join.Table) }
+        addColumnDefs(colDefs, join.Table, targetSchema)
 
-        targetRaw, err := storage.ReadAll(join.Table)
+        targetCount, err := storage.RowCount(user.Database, join.Table)
         if err != nil { return nil, err }
 
         var targetHeaderFull []string
-        targetCols := targetSchema.GetFieldNames()
-        for _, h := range targetCols {
+        for _, h := range targetSchema.GetFieldNames() {
             targetHeaderFull = append(targetHeaderFull, join.Table+"."+h)
         }
 
-        targetRows := [][]string{}
-        for _, r := range targetRaw {
-            if strings.TrimSpace(r) != "" { targetRows = append(targetRows, strings.Split(r, "|")) }
+        it = &joinIter{
+            left:      it,
+            leftTable: cmd.Table,
+            join:      join,
+            right:     joinSource{Table: join.Table, Header: targetHeaderFull, Schema: targetSchema, Count: targetCount},
         }
-
-        var nextRows [][]string
-        matchedRightIndices := make(map[int]bool)
-
-        for _, leftRow := range currentRows {
-            matchedLeft := false
-            for tIdx, rightRow := range targetRows {
-                isMatch := evaluateJoinCondition(
-                    leftRow, rightRow,
-                    currentHeader, targetHeaderFull,
-                    cmd.Table, join.Table,
-                    join.Condition,
-                )
-
-                if isMatch {
-                    merged := append([]string{}, leftRow...)
-                    merged = append(merged, rightRow...)
-                    nextRows = append(nextRows, merged)
-                    matchedLeft = true
-                    matchedRightIndices[tIdx] = true
-                }
-            }
-            if !matchedLeft && (join.Type == "LEFT" || join.Type == "KENCA") {
-                merged := append([]string{}, leftRow...)
-                for range targetHeaderFull { merged = append(merged, "NULL") }
-                nextRows = append(nextRows, merged)
-            }
-        }
-
-        if join.Type == "RIGHT" || join.Type == "KATUHU" {
-            for tIdx, rightRow := range targetRows {
-                if !matchedRightIndices[tIdx] {
-                    merged := []string{}
-                    for range currentHeader { merged = append(merged, "NULL") }
-                    merged = append(merged, rightRow...)
-                    nextRows = append(nextRows, merged)
-                }
-            }
-        }
-        currentRows = nextRows
-        currentHeader = append(currentHeader, targetHeaderFull...)
     }
 
-    var filteredMaps []map[string]string
-    
-    for _, cols := range currentRows {
-        rowMap := make(map[string]string)
-        for i, val := range cols {
-            if i < len(currentHeader) {
-                fullKey := currentHeader[i]
-                rowMap[fullKey] = val
-                parts := strings.Split(fullKey, ".")
-                if len(parts) > 1 { rowMap[parts[1]] = val }
-            }
+    if cmd.Collation != "" {
+        collation := cmd.Collation
+        if collation == schema.CollateBinary { collation = "" }
+        for k, d := range colDefs {
+            d.Collation = collation
+            colDefs[k] = d
         }
+    }
 
-        matches := true
-        if len(cmd.Where) > 0 {
-            matches = evaluateMapCondition(rowMap, cmd.Where)
-        }
+    var searchIt *searchIter
+    if search != nil {
+        searchIt = &searchIter{child: it, plan: search}
+        it = searchIt
+        search.addColumnDefs(colDefs)
+    }
 
-        if matches {
-            filteredMaps = append(filteredMaps, rowMap)
-        }
+    if len(cmd.Where) > 0 {
+        it = &filterIter{child: it, conds: cmd.Where, defs: colDefs}
     }
 
     selectedFields := cmd.Fields
     if len(selectedFields) == 0 || selectedFields[0] == "*" {
-        selectedFields = currentHeader
+        selectedFields = it.Columns()
+        if searchIt != nil {
+            selectedFields = searchIt.baseColumns()
+        }
     }
 
     var parsedCols []ParsedColumn
@@ -664,130 +758,73 @@ func execSelect(cmd *parser.Command) (*ExecutionResult, error) {
         if pc.IsAggregate { isAggregateQuery = true }
     }
 
-    var finalResult [][]string
-    var finalHeader []string
-
-    for _, pc := range parsedCols {
-        displayText := pc.TargetCol
-        if pc.IsAggregate {
-            displayText = pc.OriginalText 
-        } else if parts := strings.Split(displayText, "."); len(parts) > 1 {
-            displayText = parts[1] 
+    // RUNTUYKEUN RELEVANSI(...) dirunut samemeh proyéksi, sangkan teu kedah
+    // aya dina daptar kolom.
+    sorted := false
+    if _, ok := parser.RelevanceTarget(cmd.OrderBy); ok && searchIt != nil && cmd.GroupBy == "" && !isAggregateQuery {
+        if colIdx := indexOf(cmd.OrderBy, it.Columns()); colIdx != -1 {
+            it = newSortIter(it, colIdx, cmd.OrderDesc, colDefs[cmd.OrderBy])
+            sorted = true
         }
-        finalHeader = append(finalHeader, displayText)
     }
 
-    if cmd.GroupBy != "" {
-        groups := make(map[string][]map[string]string)
-        for _, row := range filteredMaps {
-            groupVal, ok := row[cmd.GroupBy]
-            if !ok { 
-                 groupVal = row[cmd.Table+"."+cmd.GroupBy]
-            }
-            if groupVal == "" { groupVal = "NULL" }
-            
-            groups[groupVal] = append(groups[groupVal], row)
-        }
-
-        for _, groupRows := range groups {
-            var resultRow []string
-            
-            calculatedValues := make(map[string]string)
-            if len(groupRows) > 0 {
-                for k, v := range groupRows[0] { calculatedValues[k] = v }
-            }
-
-            for _, pc := range parsedCols {
-                val := ""
-                if pc.IsAggregate {
-                    val, _ = CalculateAggregate(groupRows, pc)
-                    calculatedValues[pc.OriginalText] = val
-                } else {
-                    if len(groupRows) > 0 {
-                        v, ok := groupRows[0][pc.TargetCol]
-                        if !ok { v = groupRows[0][cmd.Table+"."+pc.TargetCol] }
-                        val = v
-                    }
-                }
-                resultRow = append(resultRow, val)
-            }
-
-            matchesHaving := true
-            if len(cmd.Having) > 0 {
-                matchesHaving = evaluateMapCondition(calculatedValues, cmd.Having)
-            }
-
-            if matchesHaving {
-                finalResult = append(finalResult, resultRow)
-            }
-        }
-
-    } else if isAggregateQuery {
-        var resultRow []string       
-        for _, pc := range parsedCols {
-            val, _ := CalculateAggregate(filteredMaps, pc)
-            resultRow = append(resultRow, val)
-        }
-        finalResult = append(finalResult, resultRow)
-
+    if cmd.GroupBy != "" || isAggregateQuery {
+        it = &aggregateIter{child: it, cols: parsedCols, table: cmd.Table, groupBy: cmd.GroupBy, having: cmd.Having, defs: colDefs}
     } else {
-        for _, rowMap := range filteredMaps {
-            var rowData []string
-            for _, pc := range parsedCols {
-                if val, ok := rowMap[pc.TargetCol]; ok {
-                    rowData = append(rowData, val)
-                } else {
-                    found := false
-                    for k, v := range rowMap {
-                        if strings.HasSuffix(k, "."+pc.TargetCol) {
-                            rowData = append(rowData, v)
-                            found = true; break
-                        }
-                    }
-                    if !found { rowData = append(rowData, "NULL") }
-                }
-            }
-            finalResult = append(finalResult, rowData)
-        }
+        it = &projectIter{child: it, cols: parsedCols}
     }
+    finalHeader := it.Columns()
 
-    if cmd.OrderBy != "" && len(finalResult) > 0 {
+    if cmd.Distinct {
+        var cols []schema.Column
+        for _, h := range finalHeader { cols = append(cols, colDefs[h]) }
+        it = &distinctIter{child: it, cols: cols}
+    }
+
+    if cmd.OrderBy != "" && !sorted {
         colIdx := indexOf(cmd.OrderBy, finalHeader)
         if colIdx == -1 {
-            for i, h := range finalHeader {
-                if h == cmd.OrderBy { colIdx = i; break }
+            if parts := strings.Split(cmd.OrderBy, "."); len(parts) > 1 {
+                colIdx = indexOf(parts[1], finalHeader)
             }
         }
-
         if colIdx != -1 {
-            sort.Slice(finalResult, func(i, j int) bool {
-                valA := finalResult[i][colIdx]
-                valB := finalResult[j][colIdx]
-                fA, errA := strconv.ParseFloat(valA, 64)
-                fB, errB := strconv.ParseFloat(valB, 64)
-
-                isLess := false
-                if errA == nil && errB == nil { isLess = fA < fB } else { isLess = valA < valB }
-                
-                if cmd.OrderDesc { return !isLess }
-                return isLess
-            })
+            it = newSortIter(it, colIdx, cmd.OrderDesc, colDefs[finalHeader[colIdx]])
         }
     }
 
-    totalRows := len(finalResult)
-    start, end := 0, totalRows
-    if cmd.Offset > 0 { start = cmd.Offset; if start > totalRows { start = totalRows } }
-    if cmd.Limit > 0 { end = start + cmd.Limit; if end > totalRows { end = totalRows } }
-    
+    if cmd.Offset > 0 || cmd.Limit > 0 {
+        it = &limitIter{child: it, offset: cmd.Offset, limit: cmd.Limit}
+    }
+
+    rows, err := drainIterator(it)
+    if err != nil {
+        return nil, err
+    }
+
+    if scan != nil && scan.scanned == 0 && len(cmd.Joins) == 0 && !isAggregateCheck(cmd.Fields) {
+        return &ExecutionResult{Columns: sMain.GetFieldNames(), Rows: [][]string{}, Message: "Data kosong"}, nil
+    }
+
     return &ExecutionResult{
         Columns: finalHeader,
-        Rows:    finalResult[start:end],
-        Message: fmt.Sprintf("%d baris kapendak", len(finalResult[start:end])),
+        Rows:    rows,
+        Message: fmt.Sprintf("%d baris kapendak", len(rows)),
     }, nil
 }
 
 
+// addColumnDefs ngadaptarkeun definisi kolom dina dua wangun ngaran:
+// "kolom" jeung "tabel.kolom" (dipaké pikeun RUNTUYKEUN jeung agregat nu sadar tipe).
+func addColumnDefs(defs map[string]schema.Column, table string, s *schema.Definition) {
+    for _, c := range s.Columns {
+        if _, taken := defs[c.Name]; !taken {
+            defs[c.Name] = c
+        }
+        defs[table+"."+c.Name] = c
+    }
+}
+
 func cleanHeaders(headers []string) []string {
 	seen := map[string]bool{}
 	out := []string{}
@@ -807,24 +844,13 @@ func cleanHeaders(headers []string) []string {
 
 
 func evaluateConditions(cols []string, schemaCols []schema.Column, conditions []parser.Condition) bool {
-	if len(conditions) == 0 { return true }
+	return conditionsTruth(cols, schemaCols, conditions) == truthTrue
+}
 
-	result := evaluateOne(cols, schemaCols, conditions[0])
-	
-	for i := 0; i < len(conditions)-1; i++ {
-		cond := conditions[i]
-		if cond.LogicOp == "" { break }
-		
-		nextResult := evaluateOne(cols, schemaCols, conditions[i+1])
-		op := strings.ToUpper(cond.LogicOp)
-		
-		if op == "SARENG" || op == "AND" {
-			result = result && nextResult
-		} else if op == "ATAWA" || op == "OR" {
-			result = result || nextResult
-		}
-	}
-	return result
+func conditionsTruth(cols []string, schemaCols []schema.Column, conditions []parser.Condition) truth {
+	return combineTruth(conditions, func(c parser.Condition) truth {
+		return evaluateOne(cols, schemaCols, c)
+	})
 }
 
 
@@ -838,157 +864,159 @@ func execUpdate(cmd *parser.Command) (*ExecutionResult, error) {
 		return nil, errors.New("teu boga hak nulis (omean)")
 	}
 
-	rawRows, err := storage.ReadAll(cmd.Table)
+	ret, err := newReturning(s, cmd.Returning)
 	if err != nil {
 		return nil, err
 	}
 
-	activeTxID := auth.GetSessionTxID()
-	var tm *transaction.TxManager
-	if activeTxID != "" {
-		tm = transaction.GetManager()
+	plan := newWritePlan(user.Database)
+	rows, err := plan.table(cmd.Table)
+	if err != nil {
+		return nil, err
 	}
 
-	var newRows []string 
 	updatedCount := 0
-
-	for _, raw := range rawRows {
-		if raw == "" { continue }
-		cols := strings.Split(raw, "|")
-
-		shouldUpdate := true
-		if len(cmd.Where) > 0 {
-			shouldUpdate = evaluateOne(cols, s.Columns, cmd.Where[0])
-			for i := 0; i < len(cmd.Where)-1; i++ {
-				cond := cmd.Where[i]
-				if cond.LogicOp == "" { break }
-				nextResult := evaluateOne(cols, s.Columns, cmd.Where[i+1])
-				op := strings.ToUpper(cond.LogicOp)
-				if op == "SARENG" || op == "AND" {
-					shouldUpdate = shouldUpdate && nextResult
-				} else if op == "ATAWA" || op == "OR" {
-					shouldUpdate = shouldUpdate || nextResult
-				}
-			}
+	for _, cols := range append([][]string{}, rows...) {
+		if !evaluateConditions(cols, s.Columns, cmd.Where) {
+			continue
 		}
 
-		if shouldUpdate {
-			newCols := make([]string, len(cols))
-			copy(newCols, cols)
-
-			for colName, newVal := range cmd.Updates {
-				idx := indexOf(colName, s.GetFieldNames())
-				if idx != -1 {
-					newCols[idx] = newVal
-				}
-			}
-			
-			newData := strings.Join(newCols, "|")
+		newCols := make([]string, len(cols))
+		copy(newCols, cols)
 
-			if activeTxID != "" {
-				err := tm.AddOperation(activeTxID, transaction.OpUpdate, cmd.Table, newData, raw)
-				if err != nil {
-					return nil, fmt.Errorf("gagal nambah ke transaksi: %v", err)
-				}
-			} else {
-	
-				cols = newCols 
+		for colName, newVal := range cmd.Updates {
+			idx := indexOf(colName, s.GetFieldNames())
+			if idx != -1 {
+				newCols[idx] = schema.NormalizeValue(s.Columns[idx], schema.InputValue(s.Columns[idx], newVal))
 			}
-			updatedCount++
 		}
 
-		if activeTxID == "" {
-			newRows = append(newRows, strings.Join(cols, "|"))
+		if err := validateUpdatedRow(plan, cmd.Table, s, cols, newCols); err != nil {
+			return nil, fmt.Errorf("gagal validasi data: %v", err)
 		}
+		if err := plan.update(cmd.Table, cols, newCols); err != nil {
+			return nil, err
+		}
+		ret.add(newCols)
+		updatedCount++
 	}
 
-	if activeTxID != "" {
-		return &ExecutionResult{
-			Message: fmt.Sprintf("✅ %d data diomean (nunggu JADIKEUN/COMMIT)", updatedCount),
-		}, nil
+	if err := plan.finish(); err != nil {
+		return nil, err
 	}
 
-	if err := storage.Rewrite(cmd.Table, newRows); err != nil {
+	inTx, err := commitWithTriggers(user, cmd.TriggerDepth, plan)
+	if err != nil {
 		return nil, err
 	}
 
-	if updatedCount > 0 {
-		go runTriggers(user.Database, cmd.Table, "UPDATE")
+	if inTx {
+		return ret.result(fmt.Sprintf("✅ %d data diomean (nunggu JADIKEUN/COMMIT)%s", updatedCount, cascadeNote(plan, updatedCount))), nil
 	}
 
-	return &ExecutionResult{
-		Message: fmt.Sprintf("✅ %d data geus diomean", updatedCount),
-	}, nil
+	return ret.result(fmt.Sprintf("✅ %d data geus diomean%s", updatedCount, cascadeNote(plan, updatedCount))), nil
+}
+
+// validateUpdatedRow mariksa baris hasil OMEAN: tipe, NOT NULL, CHECK,
+// PK/UNIQUE (dina kaayaan rencana ayeuna) sareng FK kolom nu robah.
+func validateUpdatedRow(plan *writePlan, table string, s *schema.Definition, oldCols, newCols []string) error {
+	if err := s.ValidateRow(strings.Join(newCols, "|")); err != nil {
+		return err
+	}
+
+	user, _ := auth.CurrentUser()
+	for i, col := range s.Columns {
+		val := strings.TrimSpace(newCols[i])
+		if val == strings.TrimSpace(oldCols[i]) {
+			continue
+		}
+		isEmpty := schema.IsNullFor(col.Type, val)
+
+		if col.IsNotNull && isEmpty {
+			return fmt.Errorf("kolom '%s' teu kenging kosong (NOT NULL)", col.Name)
+		}
+
+		if (col.IsPrimary || col.IsUnique) && !isEmpty {
+			rows, err := plan.table(table)
+			if err != nil {
+				return err
+			}
+			for _, r := range rows {
+				if r[0] != oldCols[0] && !plan.isDeleted(table, r[0]) && i < len(r) &&
+					schema.CollationKey(col.Collation, strings.TrimSpace(r[i])) == schema.CollationKey(col.Collation, val) {
+					return fmt.Errorf("data '%s' parantos aya di kolom '%s'", val, col.Name)
+				}
+			}
+		}
+
+		if col.ForeignKey != "" && !isEmpty {
+			parts := strings.Split(col.ForeignKey, ".")
+			if len(parts) != 2 {
+				return fmt.Errorf("definisi FK salah di kolom %s (format kedah: tabel.kolom)", col.Name)
+			}
+			targetTable := strings.ToLower(strings.TrimSpace(parts[0]))
+			exists, err := checkForeignKeyExists(user.Database, targetTable, strings.TrimSpace(parts[1]), val)
+			if err != nil {
+				return fmt.Errorf("gagal validasi FK: %v", err)
+			}
+			if !exists {
+				return fmt.Errorf("violation foreign key: data '%s' teu kapanggih di tabel induk '%s'", val, col.ForeignKey)
+			}
+		}
+	}
+
+	return validateChecks(s, newCols)
+}
+
+func cascadeNote(plan *writePlan, direct int) string {
+	if extra := len(plan.ops) - direct; extra > 0 {
+		return fmt.Sprintf(" (+%d baris anak kapangaruhan FK)", extra)
+	}
+	return ""
 }
 
 func execDelete(cmd *parser.Command) (*ExecutionResult, error) {
-    user, _ := auth.CurrentUser()
-    s, err := schema.Load(user.Database, cmd.Table)
-    if err != nil {
-        return nil, err
-    }
-    if !s.Can(user.Role, "write") {
-        return nil, errors.New("teu boga hak nulis (miceun) di tabel ieu")
-    }
+	user, _ := auth.CurrentUser()
+	s, err := schema.Load(user.Database, cmd.Table)
+	if err != nil {
+		return nil, err
+	}
+	if !s.Can(user.Role, "write") {
+		return nil, errors.New("teu boga hak nulis (miceun) di tabel ieu")
+	}
 
-    rawRows, err := storage.ReadAll(cmd.Table)
-    if err != nil {
-        return nil, err
-    }
+	ret, err := newReturning(s, cmd.Returning)
+	if err != nil {
+		return nil, err
+	}
 
-    tm := transaction.GetManager()
-    isActiveTx := tm.IsActive(user.Username)
-    deletedCount := 0
+	plan := newWritePlan(user.Database)
+	rows, err := plan.table(cmd.Table)
+	if err != nil {
+		return nil, err
+	}
 
-    for _, raw := range rawRows {
-        if raw == "" { continue }
-        cols := strings.Split(raw, "|")
-        shouldDelete := true
-        
-        if len(cmd.Where) > 0 {
-            shouldDelete = evaluateOne(cols, s.Columns, cmd.Where[0])
-            for i := 0; i < len(cmd.Where)-1; i++ {
-                cond := cmd.Where[i]
-                if cond.LogicOp == "" { break }
-                
-                nextResult := evaluateOne(cols, s.Columns, cmd.Where[i+1])
-                op := strings.ToUpper(cond.LogicOp)
-                
-                if op == "SARENG" || op == "AND" {
-                    shouldDelete = shouldDelete && nextResult
-                } else if op == "ATAWA" || op == "OR" {
-                    shouldDelete = shouldDelete || nextResult
-                }
-            }
-        }
+	deletedCount := 0
+	for _, cols := range append([][]string{}, rows...) {
+		if plan.isDeleted(cmd.Table, cols[0]) || !evaluateConditions(cols, s.Columns, cmd.Where) {
+			continue
+		}
+		if err := plan.delete(cmd.Table, cols); err != nil {
+			return nil, err
+		}
+		ret.add(cols)
+		deletedCount++
+	}
 
-        if shouldDelete {
-            rowID := cols[0] 
-            if isActiveTx {
-                err := tm.AddOperation(user.Username, transaction.OpDelete, cmd.Table, raw, raw)
-                if err != nil {
-                    return nil, fmt.Errorf("gagal nambah operasi delete ke transaksi: %v", err)
-                }
-            } else {
-                if err := storage.CommitDelete(cmd.Table, rowID); err != nil {
-                    return nil, fmt.Errorf("gagal ngahapus data fisik ID %s: %v", rowID, err)
-                }
-                
-                go func(tbl, id string) {
-                    indexing.GlobalIndexManager.RemoveIndex(tbl, id) 
-                }(cmd.Table, rowID)
-            }
-            deletedCount++
-        }
-    }
+	if err := plan.finish(); err != nil {
+		return nil, err
+	}
 
-	if deletedCount > 0 {
-		go runTriggers(user.Database, cmd.Table, "DELETE")
+	if _, err := commitWithTriggers(user, cmd.TriggerDepth, plan); err != nil {
+		return nil, err
 	}
 
-    return &ExecutionResult{
-        Message: fmt.Sprintf("✅ %d data geus dipiceun", deletedCount),
-    }, nil
+	return ret.result(fmt.Sprintf("✅ %d data geus dipiceun%s", deletedCount, cascadeNote(plan, deletedCount))), nil
 }
 
 func isAggregateCheck(fields []string) bool {
@@ -1008,41 +1036,32 @@ func indexOf(field string, fields []string) int {
 }
 
 
-func evaluateMapCondition(rowMap map[string]string, conditions []parser.Condition) bool {
+// evaluateMapCondition: defs (upami aya) nangtukeun tipe kolom sangkan
+// babandingan DECIMAL/DATETIME/... leres.
+func evaluateMapCondition(rowMap map[string]string, conditions []parser.Condition, defs map[string]schema.Column) bool {
     if len(conditions) == 0 { return true }
 
-    check := func(c parser.Condition) bool {
-        valData, ok := rowMap[c.Field]
+    check := func(c parser.Condition) truth {
+        key := c.Field
+        valData, ok := rowMap[key]
         
         if !ok { 
             found := false
             suffix := "." + c.Field
             for k, v := range rowMap {
                 if strings.HasSuffix(k, suffix) {
-                    valData = v
+                    key, valData = k, v
                     found = true
                     break
                 }
             }
-            if !found { return false } 
+            if !found { return truthFalse } 
         } 
         
-        return match(valData, c.Operator, c.Value, "") 
+        return matchTruth(valData, c, defs[key]) 
     }
 
-    result := check(conditions[0])
-    for i := 0; i < len(conditions)-1; i++ {
-        cond := conditions[i]
-        if cond.LogicOp == "" { break }
-        nextRes := check(conditions[i+1])
-        op := strings.ToUpper(cond.LogicOp)
-        if op == "SARENG" || op == "AND" {
-            result = result && nextRes
-        } else if op == "ATAWA" || op == "OR" {
-            result = result || nextRes
-        }
-    }
-    return result
+    return combineTruth(conditions, check) == truthTrue
 }
 
 func evaluateJoinCondition(rowA, rowB []string, headA, headB []string, tblA, tblB string, cond parser.Condition) bool {
@@ -1067,68 +1086,59 @@ func evaluateJoinCondition(rowA, rowB []string, headA, headB []string, tblA, tbl
         valB = cond.Value
     }
 
-    return match(valA, cond.Operator, valB, "")
+    return match(valA, cond.Operator, valB, schema.Column{})
 }
 
 
-func match(a, op, b, colType string) bool {
-    op = strings.TrimSpace(op)
-
-    if strings.ToUpper(op) == "JIGA" || strings.ToUpper(op) == "LIKE" {
-        return strings.Contains(strings.ToLower(a), strings.ToLower(b))
-    }
+// match ngabandingkeun nilai nuturkeun tipe kolom; téks nuturkeun kolasi
+// kolom (BINARY, NOCASE, ID).
+func match(a, op, b string, col schema.Column) bool {
+    return matchTruth(a, parser.Condition{Operator: op, Value: b}, col) == truthTrue
+}
 
-    isNumeric := false
-    var fA, fB float64
-    var errA, errB error
+func matchTruth(a string, cond parser.Condition, col schema.Column) truth {
+    op, b := strings.TrimSpace(cond.Operator), cond.Value
 
-    if colType == "" || colType == "INT" || colType == "FLOAT" {
-        fA, errA = strconv.ParseFloat(a, 64)
-        fB, errB = strconv.ParseFloat(b, 64)
-        if errA == nil && errB == nil {
-            isNumeric = true
-        }
+    if t, ok := matchNull(a, op, b, col.Type); ok {
+        return t
     }
 
-    if isNumeric {
-        switch op {
-        case "=": return fA == fB
-        case "!=": return fA != fB
-        case ">": return fA > fB
-        case "<": return fA < fB
-        case ">=": return fA >= fB
-        case "<=": return fA <= fB
-        }
+    if kind := patternKind(op); kind != patternNone {
+        return matchPattern(kind, a, cond, col)
     }
 
+    cmp, ok := schema.CompareValues(col.Type, a, b)
+    if !ok {
+        cmp = schema.CompareText(col.Collation, a, b)
+    }
 
     switch op {
-    case "=": return a == b
-    case "!=": return a != b
-    case ">": return a > b
-    case "<": return a < b
-    case ">=": return a >= b
-    case "<=": return a <= b
+    case "=": return toTruth(cmp == 0)
+    case "!=": return toTruth(cmp != 0)
+    case ">": return toTruth(cmp > 0)
+    case "<": return toTruth(cmp < 0)
+    case ">=": return toTruth(cmp >= 0)
+    case "<=": return toTruth(cmp <= 0)
     }
 
-    return false
+    return truthFalse
 }
 
-func evaluateOne(row []string, cols []schema.Column, cond parser.Condition) bool {
+func evaluateOne(row []string, cols []schema.Column, cond parser.Condition) truth {
 	idx := -1
-	var colType string
+	var col schema.Column
 
 	for i, c := range cols {
 		if c.Name == cond.Field {
 			idx = i
-			colType = c.Type
+			col = c
 			break
 		}
 	}
 
 	if idx < 0 || idx >= len(row) {
-		return false
+		return truthFalse
 	}
 
-	return match(row[idx], cond.Operator, cond.Value, colType)
+	return matchTruth(row[idx], cond, col)
 }
\ No newline at end of file
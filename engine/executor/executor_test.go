@@ -0,0 +1,89 @@
+package executor
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/febrd/maungdb/engine/auth"
+	"github.com/febrd/maungdb/engine/parser"
+	"github.com/febrd/maungdb/engine/schema"
+	"github.com/febrd/maungdb/engine/storage"
+	"github.com/febrd/maungdb/engine/transaction"
+	"github.com/febrd/maungdb/internal/config"
+)
+
+// TestMain nyiapkeun diréktori data samentawis, login maung sareng
+// database "uji" kanggo sadaya tés executor.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "maung-executor-")
+	if err != nil {
+		panic(err)
+	}
+	config.DataDir = dir
+	transaction.InitManager(filepath.Join(dir, "wal.log"))
+	if err := storage.Init(); err != nil {
+		panic(err)
+	}
+	if err := auth.Login(config.DefaultUser, config.DefaultPass); err != nil {
+		panic(err)
+	}
+	if err := storage.CreateDatabase("uji"); err != nil {
+		panic(err)
+	}
+	if err := auth.SetDatabase("uji"); err != nil {
+		panic(err)
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// run ngajalankeun query sareng ngagagalkeun tés mun aya error.
+func run(t *testing.T, queries ...string) *ExecutionResult {
+	t.Helper()
+	var res *ExecutionResult
+	for _, q := range queries {
+		var err error
+		if res, err = exec(q); err != nil {
+			t.Fatalf("%s: %v", q, err)
+		}
+	}
+	return res
+}
+
+func exec(query string) (*ExecutionResult, error) {
+	cmd, err := parser.Parse(query)
+	if err != nil {
+		return nil, err
+	}
+	return Execute(cmd)
+}
+
+// rowsOf: baris hasil TINGALI dihijikeun ku "|", kanggo dibandingkeun.
+func rowsOf(res *ExecutionResult) []string {
+	rows := make([]string, len(res.Rows))
+	for i, r := range res.Rows {
+		rows[i] = strings.Join(r, "|")
+	}
+	return rows
+}
+
+// sorted: salinan rows nu diurutkeun, kanggo hasil nu urutanana teu dijamin.
+func sorted(rows []string) []string {
+	out := append([]string{}, rows...)
+	sort.Strings(out)
+	return out
+}
+
+func mustSchema(t *testing.T, table string) *schema.Definition {
+	t.Helper()
+	s, err := schema.Load("uji", table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
@@ -0,0 +1,551 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/febrd/maungdb/engine/auth"
+	"github.com/febrd/maungdb/engine/fts"
+	"github.com/febrd/maungdb/engine/indexing"
+	"github.com/febrd/maungdb/engine/parser"
+	"github.com/febrd/maungdb/engine/schema"
+	"github.com/febrd/maungdb/engine/storage"
+	"github.com/febrd/maungdb/engine/transaction"
+)
+
+// maxCascadeDepth ngawatesan CASCADE nu silih rujuk (sangkan teu muter terus).
+const maxCascadeDepth = 32
+
+// fkRef: hiji kolom anak nu FK-na nunjuk ka kolom induk.
+type fkRef struct {
+	Child     string
+	ChildCol  string
+	ChildIdx  int
+	ParentIdx int
+	ParentCol schema.Column
+	OnDelete  string
+	OnUpdate  string
+	NotNull   bool
+}
+
+// referencingColumns ngumpulkeun sadaya FK (kaasup nu ti tabel sorangan)
+// nu nunjuk ka tabel parent.
+func referencingColumns(db, parent string, parentDef *schema.Definition) ([]fkRef, error) {
+	tables, err := storage.ListTables(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []fkRef
+	for _, t := range tables {
+		s, err := schema.Load(db, t)
+		if err != nil {
+			continue
+		}
+		for i, c := range s.Columns {
+			parts := strings.Split(c.ForeignKey, ".")
+			if len(parts) != 2 || !strings.EqualFold(strings.TrimSpace(parts[0]), parent) {
+				continue
+			}
+
+			parentIdx := -1
+			for j, pc := range parentDef.Columns {
+				if strings.EqualFold(pc.Name, strings.TrimSpace(parts[1])) {
+					parentIdx = j
+					break
+				}
+			}
+			if parentIdx == -1 {
+				continue
+			}
+
+			refs = append(refs, fkRef{
+				Child:     t,
+				ChildCol:  c.Name,
+				ChildIdx:  i,
+				ParentIdx: parentIdx,
+				ParentCol: parentDef.Columns[parentIdx],
+				OnDelete:  c.OnDelete,
+				OnUpdate:  c.OnUpdate,
+				NotNull:   c.IsNotNull,
+			})
+		}
+	}
+	return refs, nil
+}
+
+type planOp struct {
+	Type  transaction.OpType
+	Table string
+	Data  string
+	Prev  string
+}
+
+type noActionCheck struct {
+	ref   fkRef
+	value string
+}
+
+// writePlan ngumpulkeun sakabéh parobahan hiji paréntah OMEAN/MICEUN,
+// kaasup akibat CASCADE / SET NULL, samemeh aya nu ditulis. Mun aya
+// nu ngalanggar, teu aya hiji baris ogé nu robah.
+type writePlan struct {
+	db       string
+	ops      []planOp
+	rows     map[string][][]string
+	deleted  map[string]map[string]bool
+	defs     map[string]*schema.Definition
+	refs     map[string][]fkRef
+	noAction []noActionCheck
+	depth    int
+}
+
+func newWritePlan(db string) *writePlan {
+	return &writePlan{
+		db:      db,
+		rows:    make(map[string][][]string),
+		deleted: make(map[string]map[string]bool),
+		defs:    make(map[string]*schema.Definition),
+		refs:    make(map[string][]fkRef),
+	}
+}
+
+// table mulihkeun kaayaan tabel dumasar rencana (baris nu dipiceun dilewat),
+// kalebet parobahan transaksi pangguna nu can dijadikeun.
+func (p *writePlan) table(name string) ([][]string, error) {
+	if rows, ok := p.rows[name]; ok {
+		return rows, nil
+	}
+	rows, err := readTableRows(name)
+	if err != nil {
+		return nil, err
+	}
+	rows = withPending(name, rows)
+	p.rows[name] = rows
+	return rows, nil
+}
+
+// withPending nerapkeun operasi transaksi aktif pangguna kana rows (sapertos
+// storage nalika JADIKEUN), sangkan paréntah di jero transaksi — kalebet
+// aksi jarambah SANGGEUS — ningali baris nu can ditulis ka disk.
+func withPending(table string, rows [][]string) [][]string {
+	user, err := auth.CurrentUser()
+	if err != nil {
+		return rows
+	}
+	pending := transaction.GetManager().Pending(user.Username)
+	if len(pending) == 0 {
+		return rows
+	}
+
+	pos := make(map[string]int, len(rows))
+	for i, r := range rows {
+		pos[r[0]] = i
+	}
+	removed := make(map[int]bool)
+	for _, e := range pending {
+		if e.TableName != table {
+			continue
+		}
+		row := strings.Split(e.Data, "|")
+		if e.Type == transaction.OpInsert {
+			pos[row[0]] = len(rows)
+			rows = append(rows, row)
+			continue
+		}
+
+		source := e.Data
+		if e.PrevData != "" {
+			source = e.PrevData
+		}
+		id := strings.SplitN(source, "|", 2)[0]
+		i, ok := pos[id]
+		if !ok {
+			continue
+		}
+		delete(pos, id)
+		if e.Type == transaction.OpDelete {
+			removed[i] = true
+			continue
+		}
+		rows[i] = row
+		pos[row[0]] = i
+	}
+	if len(removed) == 0 {
+		return rows
+	}
+
+	out := rows[:0]
+	for i, r := range rows {
+		if !removed[i] {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func (p *writePlan) def(name string) (*schema.Definition, error) {
+	if d, ok := p.defs[name]; ok {
+		return d, nil
+	}
+	d, err := schema.Load(p.db, name)
+	if err != nil {
+		return nil, err
+	}
+	p.defs[name] = d
+	return d, nil
+}
+
+func (p *writePlan) references(table string) ([]fkRef, error) {
+	if refs, ok := p.refs[table]; ok {
+		return refs, nil
+	}
+	d, err := p.def(table)
+	if err != nil {
+		return nil, err
+	}
+	refs, err := referencingColumns(p.db, table, d)
+	if err != nil {
+		return nil, err
+	}
+	p.refs[table] = refs
+	return refs, nil
+}
+
+func (p *writePlan) isDeleted(table, pk string) bool {
+	return p.deleted[table][pk]
+}
+
+func (p *writePlan) delete(table string, row []string) error {
+	pk := row[0]
+	if p.isDeleted(table, pk) {
+		return nil
+	}
+	if p.deleted[table] == nil {
+		p.deleted[table] = make(map[string]bool)
+	}
+	p.deleted[table][pk] = true
+
+	raw := strings.Join(row, "|")
+	p.ops = append(p.ops, planOp{Type: transaction.OpDelete, Table: table, Data: raw, Prev: raw})
+
+	refs, err := p.references(table)
+	if err != nil {
+		return err
+	}
+	for _, ref := range refs {
+		if ref.ParentIdx >= len(row) {
+			continue
+		}
+		if err := p.applyAction(table, ref, ref.OnDelete, row[ref.ParentIdx], nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *writePlan) update(table string, oldRow, newRow []string) error {
+	rows, err := p.table(table)
+	if err != nil {
+		return err
+	}
+	for i, r := range rows {
+		if r[0] == oldRow[0] {
+			rows[i] = newRow
+			break
+		}
+	}
+
+	p.ops = append(p.ops, planOp{
+		Type:  transaction.OpUpdate,
+		Table: table,
+		Data:  strings.Join(newRow, "|"),
+		Prev:  strings.Join(oldRow, "|"),
+	})
+
+	refs, err := p.references(table)
+	if err != nil {
+		return err
+	}
+	for _, ref := range refs {
+		if ref.ParentIdx >= len(oldRow) || oldRow[ref.ParentIdx] == newRow[ref.ParentIdx] {
+			continue
+		}
+		newVal := newRow[ref.ParentIdx]
+		if err := p.applyAction(table, ref, ref.OnUpdate, oldRow[ref.ParentIdx], &newVal); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyAction ngalaksanakeun aksi referensial kana baris anak nu ngarujuk
+// value. newVal nil hartosna induk dipiceun.
+func (p *writePlan) applyAction(parent string, ref fkRef, action, value string, newVal *string) error {
+	value = strings.TrimSpace(value)
+	if value == "" || schema.IsNull(value) {
+		return nil
+	}
+
+	children, err := p.children(ref, value)
+	if err != nil || len(children) == 0 {
+		return err
+	}
+
+	verb := "miceun"
+	if newVal != nil {
+		verb = "ngomean"
+	}
+
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxCascadeDepth {
+		return fmt.Errorf("CASCADE leuwih ti %d tingkat di tabel '%s'", maxCascadeDepth, ref.Child)
+	}
+
+	switch action {
+	case schema.FKCascade:
+		for _, child := range children {
+			if newVal == nil {
+				if err := p.delete(ref.Child, child); err != nil {
+					return err
+				}
+				continue
+			}
+			updated := append([]string{}, child...)
+			updated[ref.ChildIdx] = *newVal
+			if err := p.update(ref.Child, child, updated); err != nil {
+				return err
+			}
+		}
+
+	case schema.FKSetNull:
+		if ref.NotNull {
+			return fmt.Errorf("teu tiasa SET NULL: kolom '%s.%s' NOT NULL", ref.Child, ref.ChildCol)
+		}
+		for _, child := range children {
+			updated := append([]string{}, child...)
+			updated[ref.ChildIdx] = schema.NullValue
+			if err := p.update(ref.Child, child, updated); err != nil {
+				return err
+			}
+		}
+
+	case schema.FKRestrict:
+		return fmt.Errorf("teu tiasa %s '%s' di '%s': masih dirujuk ku %d baris di %s.%s (RESTRICT)",
+			verb, value, parent, len(children), ref.Child, ref.ChildCol)
+
+	default:
+		// NO ACTION: dipariksa di ahir paréntah, sangkan baris anak nu
+		// dipiceun dina paréntah nu sami teu dianggap pelanggaran.
+		p.noAction = append(p.noAction, noActionCheck{ref: ref, value: value})
+	}
+	return nil
+}
+
+func (p *writePlan) children(ref fkRef, value string) ([][]string, error) {
+	rows, err := p.table(ref.Child)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fkKey(ref.ParentCol, value)
+	var out [][]string
+	for _, r := range rows {
+		if p.isDeleted(ref.Child, r[0]) || ref.ChildIdx >= len(r) || schema.IsNull(r[ref.ChildIdx]) {
+			continue
+		}
+		if fkKey(ref.ParentCol, r[ref.ChildIdx]) == key {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+// fkKey: bentuk baku nilai FK kanggo dibandingkeun. Angka (INT/FLOAT/
+// DECIMAL) dibandingkeun numerik (01 = 1 = 1.0), sésana dinormalisasi
+// (schema.NormalizeValue) teras nuturkeun kolasi kolom induk.
+func fkKey(col schema.Column, val string) string {
+	val = strings.TrimSpace(val)
+	switch col.Type {
+	case "INT", "FLOAT", "DECIMAL":
+		if r, ok := schema.ParseDecimal(val); ok {
+			return r.RatString()
+		}
+	}
+	return schema.CollationKey(col.Collation, schema.NormalizeValue(col, val))
+}
+
+// finish mariksa FK NO ACTION sanggeus sakabéh parobahan direncanakeun.
+func (p *writePlan) finish() error {
+	for _, check := range p.noAction {
+		children, err := p.children(check.ref, check.value)
+		if err != nil {
+			return err
+		}
+		if len(children) > 0 {
+			return fmt.Errorf("violation foreign key: '%s' masih dirujuk ku %d baris di %s.%s",
+				check.value, len(children), check.ref.Child, check.ref.ChildCol)
+		}
+	}
+	return nil
+}
+
+// commit nerapkeun rencana. Mun aya transaksi aktif, operasi ditambihkeun
+// kana transaksi éta; mun henteu, dibungkus dina transaksi implisit
+// (WAL + apply) sangkan CASCADE sareng induk robah babarengan.
+func (p *writePlan) commit(username string) (inTx bool, err error) {
+	if len(p.ops) == 0 {
+		return false, nil
+	}
+
+	tm := transaction.GetManager()
+	inTx = tm.IsActive(username)
+	if !inTx {
+		if _, err := tm.Begin(username); err != nil {
+			return false, err
+		}
+	}
+
+	for _, op := range p.ops {
+		if err := tm.AddOperation(username, op.Type, op.Table, op.Data, op.Prev); err != nil {
+			if !inTx {
+				tm.Rollback(username)
+			}
+			return inTx, err
+		}
+	}
+
+	if inTx {
+		return true, nil
+	}
+	if err := tm.Commit(username); err != nil {
+		tm.Rollback(username)
+		return false, err
+	}
+
+	p.refreshIndexes()
+	return false, nil
+}
+
+// insert nambihan baris anyar kana rencana (SIMPEN ... MUN AYA), sangkan
+// dijadikeun babarengan jeung OMEAN dina hiji transaksi.
+func (p *writePlan) insert(table string, row []string) {
+	p.ops = append(p.ops, planOp{Type: transaction.OpInsert, Table: table, Data: strings.Join(row, "|")})
+	if rows, ok := p.rows[table]; ok {
+		p.rows[table] = append(rows, row)
+	}
+}
+
+func (p *writePlan) refreshIndexes() {
+	refreshRowIndexes(p.ops, p.def)
+}
+
+// walOps ngarobih operasi transaksi jadi planOp kanggo refreshRowIndexes.
+func walOps(entries []transaction.WALEntry) []planOp {
+	ops := make([]planOp, len(entries))
+	for i, e := range entries {
+		ops[i] = planOp{Type: e.Type, Table: e.TableName, Data: e.Data, Prev: e.PrevData}
+	}
+	return ops
+}
+
+// refreshRowIndexes ngapdet index .idx sareng indeks teks .fts tina
+// operasi nu parantos diterapkeun (paréntah langsung atanapi JADIKEUN).
+func refreshRowIndexes(ops []planOp, def func(string) (*schema.Definition, error)) {
+	var tables []string
+	textOps := make(map[string][]fts.RowOp)
+
+	for _, op := range ops {
+		var removeID string
+		if op.Type != transaction.OpInsert {
+			removeID = strings.SplitN(op.Prev, "|", 2)[0]
+			indexing.GlobalIndexManager.RemoveIndex(op.Table, removeID)
+		}
+
+		var row string
+		if op.Type == transaction.OpUpdate || op.Type == transaction.OpInsert {
+			row = op.Data
+			if d, err := def(op.Table); err == nil {
+				indexing.GlobalIndexManager.UpdateIndexOnInsert(op.Table, op.Data, d.GetFieldNames())
+			}
+		}
+
+		if _, ok := textOps[op.Table]; !ok {
+			tables = append(tables, op.Table)
+		}
+		textOps[op.Table] = append(textOps[op.Table], fts.RowOp{RemoveID: removeID, Row: row})
+	}
+
+	for _, table := range tables {
+		d, err := def(table)
+		if err != nil {
+			continue
+		}
+		if err := fts.GlobalFTS.ApplyRowOps(table, d.GetFieldNames(), textOps[table]); err != nil {
+			fmt.Printf("⚠️ Gagal ngapdet indeks teks %s: %v\n", table, err)
+		}
+	}
+}
+
+// execCheckFK (PARIKSA FK [tabel]) ngalaporkeun baris yatim: nilai FK nu
+// teu aya di tabel induk.
+func execCheckFK(cmd *parser.Command) (*ExecutionResult, error) {
+	user, _ := auth.CurrentUser()
+	if user.Database == "" {
+		return nil, errors.New("database can dipilih")
+	}
+
+	tables := []string{cmd.Table}
+	if cmd.Table == "" {
+		var err error
+		if tables, err = storage.ListTables(user.Database); err != nil {
+			return nil, err
+		}
+	}
+
+	header := []string{"tabel", "kolom", "id", "nilai", "rujukan"}
+	var report [][]string
+
+	for _, t := range tables {
+		s, err := schema.Load(user.Database, t)
+		if err != nil {
+			return nil, fmt.Errorf("tabel '%s' teu kapanggih", t)
+		}
+
+		var rows [][]string
+		for i, col := range s.Columns {
+			if col.ForeignKey == "" {
+				continue
+			}
+			parents, err := loadForeignValues(user.Database, t, col, s)
+			if err != nil {
+				report = append(report, []string{t, col.Name, "-", "-", col.ForeignKey + " (" + err.Error() + ")"})
+				continue
+			}
+
+			if rows == nil {
+				if rows, err = readTableRows(t); err != nil {
+					return nil, err
+				}
+			}
+			for _, r := range rows {
+				if i >= len(r) {
+					continue
+				}
+				val := strings.TrimSpace(r[i])
+				if schema.IsNullFor(col.Type, val) || parents.has(val) {
+					continue
+				}
+				report = append(report, []string{t, col.Name, r[0], val, col.ForeignKey})
+			}
+		}
+	}
+
+	msg := "✅ Teu aya baris yatim, sadaya FK konsisten"
+	if len(report) > 0 {
+		msg = fmt.Sprintf("⚠️ %d baris yatim kapendak", len(report))
+	}
+	return &ExecutionResult{Columns: header, Rows: report, Message: msg}, nil
+}
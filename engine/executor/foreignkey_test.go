@@ -0,0 +1,58 @@
+package executor
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/febrd/maungdb/engine/schema"
+)
+
+func TestFKKey(t *testing.T) {
+	tests := []struct {
+		name string
+		col  schema.Column
+		a, b string
+		same bool
+	}{
+		{"INT nol payun", schema.Column{Type: "INT"}, "01", "1", true},
+		{"INT béda", schema.Column{Type: "INT"}, "1", "10", false},
+		{"DECIMAL skala", schema.Column{Type: "DECIMAL", Args: []string{"10", "2"}}, "1.0", "1.00", true},
+		{"FLOAT", schema.Column{Type: "FLOAT"}, "2.50", "2.5", true},
+		{"STRING persis", schema.Column{Type: "STRING"}, "01", "1", false},
+		{"STRING NOCASE", schema.Column{Type: "STRING", Collation: schema.CollateNoCase}, "Bandung", "bandung", true},
+		{"UUID hurup ageung", schema.Column{Type: "UUID"}, "A0EEBC99-9C0B-4EF8-BB6D-6BB9BD380A11", "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fkKey(tt.col, tt.a) == fkKey(tt.col, tt.b); got != tt.same {
+				t.Errorf("fkKey(%q) == fkKey(%q): %v, want %v", tt.a, tt.b, got, tt.same)
+			}
+		})
+	}
+}
+
+func TestCascadeMatchesNumericValues(t *testing.T) {
+	run(t,
+		"DAMEL induk id:INT:PK, ngaran:STRING",
+		"DAMEL anak id:INT:PK, induk_id:FLOAT:FK(induk.id):ON DELETE CASCADE:ON UPDATE CASCADE",
+		"SIMPEN induk 1|hiji",
+		"SIMPEN induk 2|dua",
+		"SIMPEN anak 10|1.0",
+		"SIMPEN anak 11|01",
+		"SIMPEN anak 12|2",
+	)
+
+	if _, err := exec("SIMPEN anak 13|3.0"); err == nil {
+		t.Fatal("FK teu kapendak kedahna ditolak")
+	}
+
+	run(t, "OMEAN induk JADI id = 5 DIMANA id = 2")
+	if got, want := rowsOf(run(t, "TINGALI * TI anak DIMANA id = 12")), []string{"12|5"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ON UPDATE CASCADE: got %v, want %v", got, want)
+	}
+
+	run(t, "MICEUN TI induk DIMANA id = 1")
+	if got, want := rowsOf(run(t, "TINGALI * TI anak")), []string{"12|5"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ON DELETE CASCADE: got %v, want %v", got, want)
+	}
+}
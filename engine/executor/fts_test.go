@@ -0,0 +1,53 @@
+package executor
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// korehanIDs: id baris hasil KOREHAN (kolom munggaran), diurutkeun.
+func korehanIDs(t *testing.T, table, col, query string) []string {
+	t.Helper()
+	res := run(t, "KOREHAN "+table+" DINA "+col+` MILARI "`+query+`"`)
+	ids := []string{}
+	for _, row := range res.Rows {
+		ids = append(ids, row[0])
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// TestFTSKeptInSync: indeks teks ngiring SIMPEN, OMEAN, MICEUN sareng
+// JADIKEUN/BATALKEUN tanpa DAMEL INDEKS_TEKS deui.
+func TestFTSKeptInSync(t *testing.T) {
+	run(t,
+		"DAMEL buku_fts id:INT:PK, judul:STRING",
+		"SIMPEN buku_fts 1|sejarah sunda",
+		"DAMEL INDEKS_TEKS buku_fts DINA judul",
+	)
+
+	steps := []struct {
+		name    string
+		queries []string
+		search  string
+		want    []string
+	}{
+		{"indeks awal", nil, "sejarah", []string{"1"}},
+		{"SIMPEN", []string{"SIMPEN buku_fts 2|sejarah jawa"}, "sejarah", []string{"1", "2"}},
+		{"OMEAN ngaganti kecap", []string{"OMEAN buku_fts JADI judul = 'dongeng sunda' DIMANA id = 1"}, "sejarah", []string{"2"}},
+		{"OMEAN kecap anyar", nil, "dongeng", []string{"1"}},
+		{"MICEUN", []string{"MICEUN TI buku_fts DIMANA id = 2"}, "sejarah", []string{}},
+		{"BATALKEUN", []string{"MIMITIAN", "SIMPEN buku_fts 3|carita pantun", "BATALKEUN"}, "pantun", []string{}},
+		{"JADIKEUN", []string{"MIMITIAN", "SIMPEN buku_fts 4|carita pantun", "OMEAN buku_fts JADI judul = 'pantun sunda' DIMANA id = 1", "JADIKEUN"}, "pantun", []string{"1", "4"}},
+		{"KOSONGKEUN", []string{"KOSONGKEUN buku_fts"}, "sunda", []string{}},
+	}
+	for _, s := range steps {
+		t.Run(s.name, func(t *testing.T) {
+			run(t, s.queries...)
+			if got := korehanIDs(t, "buku_fts", "judul", s.search); !reflect.DeepEqual(got, s.want) {
+				t.Errorf("KOREHAN %q = %v, want %v", s.search, got, s.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,343 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/febrd/maungdb/engine/auth"
+	"github.com/febrd/maungdb/engine/parser"
+	"github.com/febrd/maungdb/engine/schema"
+)
+
+// insertRows mulihkeun baris-baris SIMPEN (format a|b|c, urutan schema)
+// samemeh dilengkepan ku completeRow: tina NILAI (...), tina SIMPEN ...
+// TINGALI, atanapi tina cmd.Data (format heubeul).
+func insertRows(s *schema.Definition, cmd *parser.Command) ([]string, error) {
+	ins := cmd.Insert
+
+	switch {
+	case ins.Select != nil:
+		res, err := execSelect(ins.Select)
+		if err != nil {
+			return nil, err
+		}
+		var rows []string
+		for _, values := range res.Rows {
+			literal := make([]string, len(values))
+			for i, v := range values {
+				literal[i] = literalValue(v)
+			}
+			row, err := buildInsertRow(s, ins.Columns, literal)
+			if err != nil {
+				return nil, err
+			}
+			rows = append(rows, row)
+		}
+		return rows, nil
+
+	case len(ins.Rows) > 0:
+		rows := make([]string, 0, len(ins.Rows))
+		for i, values := range ins.Rows {
+			row, err := buildInsertRow(s, ins.Columns, values)
+			if err != nil {
+				return nil, fmt.Errorf("baris ka-%d: %v", i+1, err)
+			}
+			rows = append(rows, row)
+		}
+		return rows, nil
+	}
+
+	return []string{cmd.Data}, nil
+}
+
+// buildInsertRow nyusun nilai dumasar daptar kolom. Kolom nu teu disebut
+// dieusi DEFAULT/AUTO (ku completeRow) atanapi NULL.
+func buildInsertRow(s *schema.Definition, columns, values []string) (string, error) {
+	for _, v := range values {
+		if strings.ContainsAny(v, "|\n") {
+			return "", errors.New("nilai teu kénging ngandung '|' atanapi baris anyar")
+		}
+	}
+
+	if len(columns) == 0 {
+		return strings.Join(values, "|"), nil
+	}
+	if len(values) != len(columns) {
+		return "", fmt.Errorf("jumlah nilai (%d) teu sami sareng jumlah kolom (%d)", len(values), len(columns))
+	}
+
+	given := make(map[int]string, len(columns))
+	for i, name := range columns {
+		idx := s.GetColumnIndex(name)
+		if idx == -1 {
+			for j, col := range s.Columns {
+				if strings.EqualFold(col.Name, name) {
+					idx = j
+					break
+				}
+			}
+		}
+		if idx == -1 {
+			return "", fmt.Errorf("kolom '%s' teu aya", name)
+		}
+		given[idx] = values[i]
+	}
+
+	full := make([]string, len(s.Columns))
+	for i, col := range s.Columns {
+		val, ok := given[i]
+		switch {
+		case ok:
+			full[i] = val
+		case col.AutoIncrement || col.HasDefault:
+			full[i] = ""
+		default:
+			full[i] = schema.NullValue
+		}
+	}
+	return strings.Join(full, "|"), nil
+}
+
+// literalValue ngarobah nilai nu geus disimpen (hasil TINGALI) jadi bentuk
+// input, sangkan téks "NULL" atanapi "" teu kabaca salaku NULL/DEFAULT.
+func literalValue(val string) string {
+	switch {
+	case schema.IsNull(val):
+		return "NULL"
+	case val == "":
+		return "''"
+	case strings.EqualFold(strings.TrimSpace(val), "NULL"):
+		return "'" + strings.TrimSpace(val) + "'"
+	}
+	return val
+}
+
+func rowError(i, total int, err error) error {
+	if total > 1 {
+		return fmt.Errorf("baris ka-%d: %v", i+1, err)
+	}
+	return err
+}
+
+// returning ngumpulkeun baris nu kapangaruhan pikeun BALIKKEUN / RETURNING.
+// Nil mun query teu nganggo BALIKKEUN.
+type returning struct {
+	idx     []int
+	columns []string
+	rows    [][]string
+}
+
+func newReturning(s *schema.Definition, names []string) (*returning, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	ret := &returning{rows: [][]string{}}
+	for _, name := range names {
+		if name == "*" {
+			for i, col := range s.Columns {
+				ret.idx = append(ret.idx, i)
+				ret.columns = append(ret.columns, col.Name)
+			}
+			continue
+		}
+		i := s.GetColumnIndex(name)
+		if i == -1 {
+			return nil, fmt.Errorf("kolom BALIKKEUN '%s' teu aya", name)
+		}
+		ret.idx = append(ret.idx, i)
+		ret.columns = append(ret.columns, s.Columns[i].Name)
+	}
+	return ret, nil
+}
+
+func (r *returning) add(row []string) {
+	if r == nil {
+		return
+	}
+	out := make([]string, len(r.idx))
+	for j, i := range r.idx {
+		if i < len(row) {
+			out[j] = row[i]
+		} else {
+			out[j] = schema.NullValue
+		}
+	}
+	r.rows = append(r.rows, out)
+}
+
+func (r *returning) result(msg string) *ExecutionResult {
+	if r == nil {
+		return &ExecutionResult{Message: msg}
+	}
+	return &ExecutionResult{Message: msg, Columns: r.columns, Rows: r.rows}
+}
+
+// upsertPlan: hasil misahkeun baris SIMPEN ... MUN AYA jadi baris anyar,
+// baris nu diomean (heubeul -> anyar) sareng baris nu dilewat.
+type upsertPlan struct {
+	inserts [][]string
+	updates [][2][]string
+	skipped int
+}
+
+// conflictColumns: kolom nu disebut dina MUN AYA (kedah PK/UNIQUE), atanapi
+// sadaya kolom PK/UNIQUE.
+func conflictColumns(s *schema.Definition, oc *parser.ConflictClause) ([]int, error) {
+	var idx []int
+	if len(oc.Columns) == 0 {
+		for i, col := range s.Columns {
+			if col.IsPrimary || col.IsUnique {
+				idx = append(idx, i)
+			}
+		}
+		return idx, nil
+	}
+
+	for _, name := range oc.Columns {
+		i := s.GetColumnIndex(name)
+		if i == -1 {
+			return nil, fmt.Errorf("kolom MUN AYA '%s' teu aya", name)
+		}
+		if !s.Columns[i].IsPrimary && !s.Columns[i].IsUnique {
+			return nil, fmt.Errorf("kolom MUN AYA '%s' sanés PK atanapi UNIQUE", name)
+		}
+		idx = append(idx, i)
+	}
+	return idx, nil
+}
+
+// resolveConflicts milarian baris heubeul nu bentrok (dina salah sahiji
+// kolom konflik) pikeun unggal baris anyar. Hiji baris teu kénging
+// kapangaruhan dua kali ku paréntah nu sami.
+func resolveConflicts(s *schema.Definition, rows []string, existing [][]string, oc *parser.ConflictClause) (*upsertPlan, error) {
+	conflictIdx, err := conflictColumns(s, oc)
+	if err != nil {
+		return nil, err
+	}
+
+	// owner[kolom][konci] = indéks baris dina existing, atanapi -1 mun baris
+	// éta nembé disimpen ku paréntah ieu.
+	owner := make(map[int]map[string]int)
+	for _, i := range conflictIdx {
+		owner[i] = make(map[string]int)
+		for r, row := range existing {
+			if i < len(row) && !schema.IsNullFor(s.Columns[i].Type, row[i]) {
+				owner[i][schema.CollationKey(s.Columns[i].Collation, strings.TrimSpace(row[i]))] = r
+			}
+		}
+	}
+
+	plan := &upsertPlan{}
+	touched := make(map[int]bool)
+	for n, data := range rows {
+		newRow := strings.Split(data, "|")
+
+		match := -2
+		for _, i := range conflictIdx {
+			if schema.IsNullFor(s.Columns[i].Type, newRow[i]) {
+				continue
+			}
+			if r, ok := owner[i][schema.CollationKey(s.Columns[i].Collation, strings.TrimSpace(newRow[i]))]; ok {
+				match = r
+				break
+			}
+		}
+
+		switch {
+		case match == -2:
+			plan.inserts = append(plan.inserts, newRow)
+			for _, i := range conflictIdx {
+				if !schema.IsNullFor(s.Columns[i].Type, newRow[i]) {
+					owner[i][schema.CollationKey(s.Columns[i].Collation, strings.TrimSpace(newRow[i]))] = -1
+				}
+			}
+		case oc.DoNothing:
+			plan.skipped++
+		case match == -1 || touched[match]:
+			return nil, rowError(n, len(rows), errors.New("MUN AYA OMEAN teu tiasa ngarobah hiji baris dua kali dina paréntah nu sami"))
+		default:
+			touched[match] = true
+			updated, err := applyConflictUpdates(s, existing[match], newRow, oc.Updates)
+			if err != nil {
+				return nil, err
+			}
+			plan.updates = append(plan.updates, [2][]string{existing[match], updated})
+		}
+	}
+	return plan, nil
+}
+
+// applyConflictUpdates nerapkeun "kolom=nilai" kana baris heubeul. Nilai
+// ANYAR.kolom / EXCLUDED.kolom dicandak tina baris nu badé disimpen.
+func applyConflictUpdates(s *schema.Definition, oldRow, newRow []string, updates map[string]string) ([]string, error) {
+	updated := append([]string{}, oldRow...)
+	for name, val := range updates {
+		i := s.GetColumnIndex(name)
+		if i == -1 {
+			return nil, fmt.Errorf("kolom '%s' teu aya", name)
+		}
+
+		if prefix, src, ok := strings.Cut(val, "."); ok && (strings.EqualFold(prefix, "ANYAR") || strings.EqualFold(prefix, "EXCLUDED")) {
+			j := s.GetColumnIndex(src)
+			if j == -1 {
+				return nil, fmt.Errorf("kolom '%s' teu aya", src)
+			}
+			updated[i] = newRow[j]
+			continue
+		}
+		updated[i] = schema.NormalizeValue(s.Columns[i], schema.InputValue(s.Columns[i], val))
+	}
+	return updated, nil
+}
+
+// execUpsert ngajalankeun SIMPEN ... MUN AYA: baris anyar sareng OMEAN
+// dijadikeun babarengan dina hiji rencana (hiji transaksi).
+func execUpsert(user *auth.User, s *schema.Definition, cmd *parser.Command, rows []string, ret *returning) (*ExecutionResult, error) {
+	plan := newWritePlan(user.Database)
+	existing, err := plan.table(cmd.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	up, err := resolveConflicts(s, rows, append([][]string{}, existing...), cmd.Insert.OnConflict)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := newUniqueKeys(s, cmd.Table)
+	for _, row := range up.inserts {
+		if err := validateConstraints(s, cmd.Table, strings.Join(row, "|"), keys); err != nil {
+			return nil, fmt.Errorf("gagal validasi data: %v", err)
+		}
+	}
+	for _, u := range up.updates {
+		if err := validateUpdatedRow(plan, cmd.Table, s, u[0], u[1]); err != nil {
+			return nil, fmt.Errorf("gagal validasi data: %v", err)
+		}
+		if err := plan.update(cmd.Table, u[0], u[1]); err != nil {
+			return nil, err
+		}
+		ret.add(u[1])
+	}
+	for _, row := range up.inserts {
+		plan.insert(cmd.Table, row)
+		ret.add(row)
+	}
+
+	if err := plan.finish(); err != nil {
+		return nil, err
+	}
+	inTx, err := commitWithTriggers(user, cmd.TriggerDepth, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	direct := len(up.inserts) + len(up.updates)
+	msg := fmt.Sprintf("✅ %d data asup, %d diomean, %d dilewat di table '%s'%s", len(up.inserts), len(up.updates), up.skipped, cmd.Table, cascadeNote(plan, direct))
+	if inTx {
+		return ret.result(msg + " (nunggu JADIKEUN)"), nil
+	}
+	return ret.result(msg), nil
+}
@@ -0,0 +1,637 @@
+package executor
+
+import (
+	"io"
+	"strings"
+
+	"github.com/febrd/maungdb/engine/parser"
+	"github.com/febrd/maungdb/engine/schema"
+	"github.com/febrd/maungdb/engine/storage"
+)
+
+// RowIterator nyaéta operator pipeline gaya volcano: Open nyiapkeun
+// sumber, Next mulangkeun hiji baris (io.EOF mun béak), Close ngabébaskeun.
+type RowIterator interface {
+	Open() error
+	Next() ([]string, error)
+	Close() error
+	Columns() []string
+}
+
+func drainIterator(it RowIterator) ([][]string, error) {
+	if err := it.Open(); err != nil {
+		it.Close()
+		return nil, err
+	}
+	defer it.Close()
+
+	rows := [][]string{}
+	for {
+		row, err := it.Next()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+}
+
+func rowToMap(header, row []string) map[string]string {
+	rowMap := make(map[string]string, len(header)*2)
+	for i, val := range row {
+		if i < len(header) {
+			fullKey := header[i]
+			rowMap[fullKey] = val
+			parts := strings.Split(fullKey, ".")
+			if len(parts) > 1 {
+				rowMap[parts[1]] = val
+			}
+		}
+	}
+	return rowMap
+}
+
+// ==========================================
+// SCAN
+// ==========================================
+
+type scanIter struct {
+	table      string
+	header     []string
+	indexedPKs map[string]bool
+	scanner    *storage.TableScanner
+	scanned    int
+}
+
+func newScanIter(table string, s *schema.Definition, indexedPKs map[string]bool) *scanIter {
+	var header []string
+	for _, col := range s.GetFieldNames() {
+		header = append(header, table+"."+col)
+	}
+	return &scanIter{table: table, header: header, indexedPKs: indexedPKs}
+}
+
+func (it *scanIter) Open() error {
+	ts, err := storage.OpenTable(it.table)
+	if err != nil {
+		return err
+	}
+	it.scanner = ts
+	return nil
+}
+
+func (it *scanIter) Next() ([]string, error) {
+	for {
+		raw, ok := it.scanner.Next()
+		if !ok {
+			if err := it.scanner.Err(); err != nil {
+				return nil, err
+			}
+			return nil, io.EOF
+		}
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+
+		parts := strings.Split(raw, "|")
+		if it.indexedPKs != nil && !it.indexedPKs[parts[0]] {
+			continue
+		}
+		it.scanned++
+		return parts, nil
+	}
+}
+
+func (it *scanIter) Close() error {
+	if it.scanner == nil {
+		return nil
+	}
+	return it.scanner.Close()
+}
+
+func (it *scanIter) Columns() []string { return it.header }
+
+// sliceIter ngalirkeun baris nu geus aya di memori (hasil KACA, join
+// nu dimaterialisasi, jsb).
+type sliceIter struct {
+	header []string
+	rows   [][]string
+	pos    int
+}
+
+func (it *sliceIter) Open() error { it.pos = 0; return nil }
+
+func (it *sliceIter) Next() ([]string, error) {
+	if it.pos >= len(it.rows) {
+		return nil, io.EOF
+	}
+	row := it.rows[it.pos]
+	it.pos++
+	return row, nil
+}
+
+func (it *sliceIter) Close() error      { return nil }
+func (it *sliceIter) Columns() []string { return it.header }
+
+// ==========================================
+// FILTER
+// ==========================================
+
+type filterIter struct {
+	child RowIterator
+	conds []parser.Condition
+	defs  map[string]schema.Column
+}
+
+func (it *filterIter) Open() error { return it.child.Open() }
+
+func (it *filterIter) Next() ([]string, error) {
+	header := it.child.Columns()
+	for {
+		row, err := it.child.Next()
+		if err != nil {
+			return nil, err
+		}
+		if evaluateMapCondition(rowToMap(header, row), it.conds, it.defs) {
+			return row, nil
+		}
+	}
+}
+
+func (it *filterIter) Close() error      { return it.child.Close() }
+func (it *filterIter) Columns() []string { return it.child.Columns() }
+
+// ==========================================
+// JOIN
+// ==========================================
+
+// joinIter ngagabungkeun sisi kénca sareng tabel join. Mun sisi kénca
+// leuwih leutik, sisi kénca dimaterialisasi sareng tabel join dialirkeun
+// (index / merge / hash join); mun leuwih gedé, tabel join dibaca ngaliwatan
+// scan jadi hash table (build side) sareng sisi kénca dialirkeun.
+type joinIter struct {
+	left      RowIterator
+	leftTable string
+	join      parser.JoinClause
+	right     joinSource
+
+	out *sliceIter
+
+	buffered     [][]string
+	leftDone     bool
+	cond         parser.Condition
+	idxA, idxB   int
+	rightRows    [][]string
+	hash         map[string][]int
+	matchedRight map[int]bool
+	pending      [][]string
+	rightFlushed bool
+}
+
+func (it *joinIter) Open() error {
+	if err := it.left.Open(); err != nil {
+		return err
+	}
+
+	limit := it.right.Count
+	for len(it.buffered) <= limit {
+		row, err := it.left.Next()
+		if err == io.EOF {
+			it.leftDone = true
+			break
+		}
+		if err != nil {
+			return err
+		}
+		it.buffered = append(it.buffered, row)
+	}
+
+	leftSide := joinSide{Table: it.leftTable, Header: it.left.Columns(), Rows: it.buffered}
+
+	if it.leftDone {
+		rows, err := executeJoin(leftSide, it.right, it.join)
+		if err != nil {
+			return err
+		}
+		it.out = &sliceIter{header: it.Columns(), rows: rows}
+		return nil
+	}
+
+	rows, err := drainIterator(it.right.scan(nil))
+	if err != nil {
+		return err
+	}
+	it.rightRows = rows
+	it.cond = it.join.Condition
+	it.idxA, it.idxB = resolveJoinColumns(leftSide, it.right.side(rows), &it.cond)
+	it.matchedRight = make(map[int]bool)
+
+	strategy := joinNestedLoop
+	if it.cond.Operator == "=" && it.idxA != -1 && it.idxB != -1 {
+		strategy = joinHash
+		it.hash = hashRows(rows, it.idxB)
+	}
+	logJoinStrategy(strategy, it.leftTable, -1, it.right.Table, len(rows))
+	return nil
+}
+
+func (it *joinIter) Next() ([]string, error) {
+	if it.out != nil {
+		return it.out.Next()
+	}
+
+	for len(it.pending) == 0 {
+		leftRow, err := it.nextLeft()
+		if err == io.EOF {
+			return it.nextUnmatchedRight()
+		}
+		if err != nil {
+			return nil, err
+		}
+		it.probe(leftRow)
+	}
+
+	row := it.pending[0]
+	it.pending = it.pending[1:]
+	return row, nil
+}
+
+func (it *joinIter) nextLeft() ([]string, error) {
+	if len(it.buffered) > 0 {
+		row := it.buffered[0]
+		it.buffered = it.buffered[1:]
+		return row, nil
+	}
+	return it.left.Next()
+}
+
+func (it *joinIter) probe(leftRow []string) {
+	var matches []int
+	if it.hash != nil {
+		if it.idxA < len(leftRow) && !schema.IsNull(leftRow[it.idxA]) {
+			matches = it.hash[joinKey(leftRow[it.idxA])]
+		}
+	} else {
+		for rIdx, rightRow := range it.rightRows {
+			if evaluateJoinCondition(leftRow, rightRow, it.left.Columns(), it.right.Header, it.leftTable, it.right.Table, it.cond) {
+				matches = append(matches, rIdx)
+			}
+		}
+	}
+
+	for _, rIdx := range matches {
+		merged := append([]string{}, leftRow...)
+		merged = append(merged, it.rightRows[rIdx]...)
+		it.pending = append(it.pending, merged)
+		it.matchedRight[rIdx] = true
+	}
+
+	if len(matches) == 0 && keepsLeft(it.join.Type) {
+		merged := append([]string{}, leftRow...)
+		for range it.right.Header {
+			merged = append(merged, schema.NullValue)
+		}
+		it.pending = append(it.pending, merged)
+	}
+}
+
+func (it *joinIter) nextUnmatchedRight() ([]string, error) {
+	if !keepsRight(it.join.Type) {
+		return nil, io.EOF
+	}
+	if !it.rightFlushed {
+		it.rightFlushed = true
+		for rIdx, rightRow := range it.rightRows {
+			if !it.matchedRight[rIdx] {
+				merged := []string{}
+				for range it.left.Columns() {
+					merged = append(merged, schema.NullValue)
+				}
+				it.pending = append(it.pending, append(merged, rightRow...))
+			}
+		}
+	}
+	if len(it.pending) == 0 {
+		return nil, io.EOF
+	}
+	row := it.pending[0]
+	it.pending = it.pending[1:]
+	return row, nil
+}
+
+func (it *joinIter) Close() error { return it.left.Close() }
+
+func (it *joinIter) Columns() []string {
+	return append(append([]string{}, it.left.Columns()...), it.right.Header...)
+}
+
+// ==========================================
+// PROJECT
+// ==========================================
+
+type projectIter struct {
+	child     RowIterator
+	cols      []ParsedColumn
+	positions []int
+}
+
+func (it *projectIter) Open() error {
+	if err := it.child.Open(); err != nil {
+		return err
+	}
+	header := it.child.Columns()
+	it.positions = make([]int, len(it.cols))
+	for i, pc := range it.cols {
+		it.positions[i] = resolveProjection(pc.TargetCol, header)
+	}
+	return nil
+}
+
+// resolveProjection nuturkeun aturan rowMap baheula: ngaran lengkep
+// heula, tuluy ngaran pondok (kolom pangahirna nu meunang).
+func resolveProjection(target string, header []string) int {
+	if idx := indexOf(target, header); idx != -1 {
+		return idx
+	}
+	pos := -1
+	for i, h := range header {
+		if parts := strings.Split(h, "."); len(parts) > 1 && parts[1] == target {
+			pos = i
+		}
+	}
+	if pos != -1 {
+		return pos
+	}
+	for i, h := range header {
+		if strings.HasSuffix(h, "."+target) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (it *projectIter) Next() ([]string, error) {
+	row, err := it.child.Next()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, len(it.positions))
+	for i, pos := range it.positions {
+		if pos != -1 && pos < len(row) {
+			out[i] = row[pos]
+		} else {
+			out[i] = schema.NullValue
+		}
+	}
+	return out, nil
+}
+
+func (it *projectIter) Close() error { return it.child.Close() }
+
+func (it *projectIter) Columns() []string {
+	var header []string
+	for _, pc := range it.cols {
+		header = append(header, displayName(pc))
+	}
+	return header
+}
+
+func displayName(pc ParsedColumn) string {
+	if pc.IsAggregate || strings.HasSuffix(pc.TargetCol, ")") {
+		return pc.OriginalText
+	}
+	if parts := strings.Split(pc.TargetCol, "."); len(parts) > 1 {
+		return parts[1]
+	}
+	return pc.TargetCol
+}
+
+// ==========================================
+// AGGREGATE (KUMPULKEUN / JUMLAH / TOTAL ...)
+// ==========================================
+
+type aggregateIter struct {
+	child   RowIterator
+	cols    []ParsedColumn
+	table   string
+	groupBy string
+	having  []parser.Condition
+	defs    map[string]schema.Column
+	out     *sliceIter
+}
+
+func (it *aggregateIter) Open() error {
+	if err := it.child.Open(); err != nil {
+		return err
+	}
+
+	for i := range it.cols {
+		if def, ok := it.defs[it.cols[i].TargetCol]; ok {
+			it.cols[i].ColType = def.Type
+			it.cols[i].Scale = schema.DecimalScale(def)
+			it.cols[i].Collation = def.Collation
+		} else {
+			it.cols[i].Scale = -1
+		}
+	}
+
+	groupCol, ok := it.defs[it.groupBy]
+	if !ok {
+		groupCol = it.defs[it.table+"."+it.groupBy]
+	}
+
+	header := it.child.Columns()
+	var groupOrder []string
+	groups := make(map[string][]map[string]string)
+	var all []map[string]string
+
+	for {
+		row, err := it.child.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		rowMap := rowToMap(header, row)
+
+		if it.groupBy == "" {
+			all = append(all, rowMap)
+			continue
+		}
+
+		groupVal, ok := rowMap[it.groupBy]
+		if !ok {
+			groupVal, ok = rowMap[it.table+"."+it.groupBy]
+		}
+		if !ok {
+			groupVal = schema.NullValue
+		}
+		key := schema.CollationKey(groupCol.Collation, groupVal)
+		if _, seen := groups[key]; !seen {
+			groupOrder = append(groupOrder, key)
+		}
+		groups[key] = append(groups[key], rowMap)
+	}
+
+	var result [][]string
+	if it.groupBy == "" {
+		var resultRow []string
+		for _, pc := range it.cols {
+			val, _ := CalculateAggregate(all, pc)
+			resultRow = append(resultRow, val)
+		}
+		result = append(result, resultRow)
+	}
+
+	for _, key := range groupOrder {
+		groupRows := groups[key]
+		var resultRow []string
+
+		calculatedValues := make(map[string]string)
+		for k, v := range groupRows[0] {
+			calculatedValues[k] = v
+		}
+
+		for _, pc := range it.cols {
+			val := ""
+			if pc.IsAggregate {
+				val, _ = CalculateAggregate(groupRows, pc)
+				calculatedValues[pc.OriginalText] = val
+			} else {
+				v, ok := groupRows[0][pc.TargetCol]
+				if !ok {
+					v = groupRows[0][it.table+"."+pc.TargetCol]
+				}
+				val = v
+			}
+			resultRow = append(resultRow, val)
+		}
+
+		if len(it.having) == 0 || evaluateMapCondition(calculatedValues, it.having, nil) {
+			result = append(result, resultRow)
+		}
+	}
+
+	it.out = &sliceIter{rows: result}
+	return nil
+}
+
+func (it *aggregateIter) Next() ([]string, error) { return it.out.Next() }
+func (it *aggregateIter) Close() error            { return it.child.Close() }
+
+func (it *aggregateIter) Columns() []string {
+	var header []string
+	for _, pc := range it.cols {
+		header = append(header, displayName(pc))
+	}
+	return header
+}
+
+// ==========================================
+// DISTINCT (BEDA)
+// ==========================================
+
+// distinctIter (BEDA / DISTINCT) ngaleungitkeun baris kembar. Téks
+// dibandingkeun nuturkeun kolasi kolomna, NULL dianggap sami.
+type distinctIter struct {
+	child RowIterator
+	cols  []schema.Column
+	seen  map[string]bool
+}
+
+func (it *distinctIter) Open() error {
+	it.seen = make(map[string]bool)
+	return it.child.Open()
+}
+
+func (it *distinctIter) Next() ([]string, error) {
+	for {
+		row, err := it.child.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		keys := make([]string, len(row))
+		for i, v := range row {
+			collation := ""
+			if i < len(it.cols) {
+				collation = it.cols[i].Collation
+			}
+			keys[i] = schema.CollationKey(collation, v)
+		}
+		key := strings.Join(keys, "\x00")
+
+		if !it.seen[key] {
+			it.seen[key] = true
+			return row, nil
+		}
+	}
+}
+
+func (it *distinctIter) Close() error      { return it.child.Close() }
+func (it *distinctIter) Columns() []string { return it.child.Columns() }
+
+// ==========================================
+// LIMIT / OFFSET (SAKADAR / LIWATAN)
+// ==========================================
+
+// limitIter eureun narik baris ti anakna pas SAKADAR geus kaeusi, jadi
+// scan tabel ogé eureun mimiti. Anakna ditutup sakali waé, boh di dieu boh
+// dina Close.
+type limitIter struct {
+	child   RowIterator
+	offset  int
+	limit   int
+	emitted int
+	done    bool
+	closed  bool
+}
+
+func (it *limitIter) Open() error { return it.child.Open() }
+
+func (it *limitIter) Next() ([]string, error) {
+	if it.done {
+		return nil, io.EOF
+	}
+	for it.offset > 0 {
+		if _, err := it.child.Next(); err != nil {
+			return nil, err
+		}
+		it.offset--
+	}
+	if it.limit > 0 && it.emitted >= it.limit {
+		it.done = true
+		if err := it.closeChild(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+	row, err := it.child.Next()
+	if err != nil {
+		return nil, err
+	}
+	it.emitted++
+	return row, nil
+}
+
+func (it *limitIter) closeChild() error {
+	if it.closed {
+		return nil
+	}
+	it.closed = true
+	return it.child.Close()
+}
+
+func (it *limitIter) Close() error      { return it.closeChild() }
+func (it *limitIter) Columns() []string { return it.child.Columns() }
+
+func keepsLeft(joinType string) bool {
+	return joinType == "LEFT" || joinType == "KENCA" || joinType == "FULL" || joinType == "PINUH"
+}
+
+func keepsRight(joinType string) bool {
+	return joinType == "RIGHT" || joinType == "KATUHU" || joinType == "FULL" || joinType == "PINUH"
+}
@@ -0,0 +1,58 @@
+package executor
+
+import (
+	"reflect"
+	"testing"
+)
+
+// countingIter: sliceIter nu ngitung baris nu dibaca sareng sabaraha kali
+// Close disauran.
+type countingIter struct {
+	*sliceIter
+	reads, closes int
+}
+
+func (it *countingIter) Next() ([]string, error) {
+	row, err := it.sliceIter.Next()
+	if err == nil {
+		it.reads++
+	}
+	return row, err
+}
+
+func (it *countingIter) Close() error {
+	it.closes++
+	return it.sliceIter.Close()
+}
+
+func TestLimitIter(t *testing.T) {
+	cases := []struct {
+		name          string
+		offset, limit int
+		want          [][]string
+		wantReads     int
+	}{
+		{"SAKADAR", 0, 2, [][]string{{"1"}, {"2"}}, 2},
+		{"LIWATAN", 3, 0, [][]string{{"4"}, {"5"}}, 5},
+		{"LIWATAN sareng SAKADAR", 1, 2, [][]string{{"2"}, {"3"}}, 3},
+		{"SAKADAR langkung ti baris", 0, 9, [][]string{{"1"}, {"2"}, {"3"}, {"4"}, {"5"}}, 5},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			child := &countingIter{sliceIter: &sliceIter{header: []string{"id"}, rows: [][]string{{"1"}, {"2"}, {"3"}, {"4"}, {"5"}}}}
+			rows, err := drainIterator(&limitIter{child: child, offset: c.offset, limit: c.limit})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(rows, c.want) {
+				t.Errorf("rows = %v, want %v", rows, c.want)
+			}
+			if child.reads != c.wantReads {
+				t.Errorf("maca %d baris ti anakna, want %d", child.reads, c.wantReads)
+			}
+			if child.closes != 1 {
+				t.Errorf("anakna ditutup %d kali, want 1", child.closes)
+			}
+		})
+	}
+}
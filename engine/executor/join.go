@@ -0,0 +1,399 @@
+package executor
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/febrd/maungdb/engine/indexing"
+	"github.com/febrd/maungdb/engine/parser"
+	"github.com/febrd/maungdb/engine/schema"
+)
+
+type joinStrategy string
+
+const (
+	joinNestedLoop  joinStrategy = "NESTED LOOP"
+	joinHash        joinStrategy = "HASH JOIN"
+	joinMerge       joinStrategy = "MERGE JOIN"
+	joinIndexLookup joinStrategy = "INDEX NESTED LOOP"
+)
+
+// Index nested-loop ngan dipilih mun sisi kénca leuwih leutik batan
+// 1/indexProbeRatio tina tabel join, sabab unggal probe kudu muka index.
+const indexProbeRatio = 4
+
+type joinSide struct {
+	Table  string
+	Header []string
+	Rows   [][]string
+}
+
+// joinSource: tabel join (sisi katuhu). Barisna dibaca ngaliwatan scan
+// iterator, teu dimuat sadayana ka memori; Count ngan kanggo optimizer.
+type joinSource struct {
+	Table  string
+	Header []string
+	Schema *schema.Definition
+	Count  int
+}
+
+func (src joinSource) scan(pks map[string]bool) RowIterator {
+	return newScanIter(src.Table, src.Schema, pks)
+}
+
+func (src joinSource) side(rows [][]string) joinSide {
+	return joinSide{Table: src.Table, Header: src.Header, Rows: rows}
+}
+
+type joinPair struct {
+	Left  int
+	Right int
+}
+
+// executeJoin ngagabungkeun sisi kénca (di memori) sareng tabel join, milih
+// strategi (index / merge / hash / nested loop) dumasar ukuran data.
+func executeJoin(left joinSide, right joinSource, join parser.JoinClause) ([][]string, error) {
+	cond := join.Condition
+	idxA, idxB := resolveJoinColumns(left, right.side(nil), &cond)
+
+	var (
+		strategy joinStrategy
+		pairs    []joinPair
+		rows     [][]string
+		err      error
+	)
+	if idxMap, ok := joinIndex(left, right, idxA, idxB, cond, join.Type); ok {
+		strategy = joinIndexLookup
+		pairs, rows, err = indexNestedLoopJoin(left, right, idxA, idxB, idxMap)
+	} else {
+		strategy, pairs, rows, err = streamJoin(left, right, idxA, idxB, cond, keepsRight(join.Type))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	logJoinStrategy(strategy, left.Table, len(left.Rows), right.Table, right.Count)
+
+	return assembleJoinRows(left, right.side(rows), pairs, join.Type), nil
+}
+
+// logJoinStrategy: leftCount -1 hartosna sisi kénca dialirkeun (streaming).
+func logJoinStrategy(strategy joinStrategy, leftTable string, leftCount int, rightTable string, rightCount int) {
+	leftSize := "stream"
+	if leftCount >= 0 {
+		leftSize = strconv.Itoa(leftCount)
+	}
+	fmt.Printf("⚡ [OPTIMIZER] %s: %s (%s) ⨝ %s (%d)\n", strategy, leftTable, leftSize, rightTable, rightCount)
+}
+
+// resolveJoinColumns milarian posisi kolom kondisi join di unggal sisi.
+// Mun kondisina dibalik (tabel_join.kolom = tabel_utama.kolom), diswap.
+func resolveJoinColumns(left, right joinSide, cond *parser.Condition) (int, int) {
+	idxA := resolveColumn(cond.Field, left.Header, left.Table)
+	idxB := resolveColumn(cond.Value, right.Header, right.Table)
+
+	if idxA == -1 && idxB == -1 {
+		swapA := resolveColumn(cond.Value, left.Header, left.Table)
+		swapB := resolveColumn(cond.Field, right.Header, right.Table)
+		if swapA != -1 && swapB != -1 {
+			cond.Field, cond.Value = cond.Value, cond.Field
+			cond.Operator = flipOperator(cond.Operator)
+			return swapA, swapB
+		}
+	}
+	return idxA, idxB
+}
+
+func resolveColumn(field string, header []string, table string) int {
+	idx := indexOf(field, header)
+	if idx == -1 {
+		idx = indexOf(table+"."+field, header)
+	}
+	return idx
+}
+
+func flipOperator(op string) string {
+	switch op {
+	case ">":
+		return "<"
+	case "<":
+		return ">"
+	case ">=":
+		return "<="
+	case "<=":
+		return ">="
+	}
+	return op
+}
+
+// joinIndex mulihkeun index TANDAIN kolom join sisi katuhu mun index nested
+// loop cocog: equi-join, sisi kénca cekap leutik, sanés RIGHT/FULL join (nu
+// butuh sadaya baris katuhu), sareng PK tabel join aya di kolom kahiji. Index
+// nyimpen ID baris (kolom kahiji), janten baris ngan tiasa dipilarian ku PK
+// upami éta kolom PK-na.
+func joinIndex(left joinSide, right joinSource, idxA, idxB int, cond parser.Condition, joinType string) (indexing.IndexMap, bool) {
+	if cond.Operator != "=" || idxA == -1 || idxB == -1 {
+		return nil, false
+	}
+	if len(left.Rows)*indexProbeRatio >= right.Count || keepsRight(joinType) {
+		return nil, false
+	}
+	if right.Schema == nil || len(right.Schema.Columns) == 0 || !right.Schema.Columns[0].IsPrimary {
+		return nil, false
+	}
+	colName := right.Header[idxB]
+	if parts := strings.Split(colName, "."); len(parts) > 1 {
+		colName = parts[len(parts)-1]
+	}
+	idxMap, err := indexing.GlobalIndexManager.LoadIndex(right.Table, colName)
+	return idxMap, err == nil
+}
+
+// streamJoin ngalirkeun tabel join ngaliwatan scan iterator sareng
+// nyocogkeun unggal barisna ka sisi kénca: merge join mun sisi kénca runtuy
+// dumasar kolom join (mun sisi katuhu tétéla teu runtuy, sésana dicocogkeun
+// ngaliwatan hash sisi kénca), hash join mun henteu, sareng nested loop
+// kanggo kondisi sanés "=". Ngan baris katuhu nu cocog (sadayana kanggo
+// RIGHT/FULL join) nu disimpen.
+func streamJoin(left joinSide, right joinSource, idxA, idxB int, cond parser.Condition, keepRight bool) (joinStrategy, []joinPair, [][]string, error) {
+	equi := cond.Operator == "=" && idxA != -1 && idxB != -1
+	strategy := joinNestedLoop
+	var hash map[string][]int
+	if equi {
+		strategy = joinMerge
+		if !isSortedOn(left.Rows, idxA) {
+			strategy, hash = joinHash, hashRows(left.Rows, idxA)
+		}
+	}
+
+	it := right.scan(nil)
+	if err := it.Open(); err != nil {
+		it.Close()
+		return strategy, nil, nil, err
+	}
+	defer it.Close()
+
+	var (
+		pairs   []joinPair
+		rows    [][]string
+		prevKey string
+		merged  bool
+		pos     int
+	)
+	for {
+		row, err := it.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return strategy, nil, nil, err
+		}
+
+		var matched []int
+		switch {
+		case !equi:
+			for lIdx, leftRow := range left.Rows {
+				if evaluateJoinCondition(leftRow, row, left.Header, right.Header, left.Table, right.Table, cond) {
+					matched = append(matched, lIdx)
+				}
+			}
+		case idxB >= len(row) || schema.IsNull(row[idxB]):
+		case hash == nil && (!merged || compareJoinKeys(prevKey, row[idxB]) <= 0):
+			key := row[idxB]
+			for pos < len(left.Rows) && compareJoinKeys(left.Rows[pos][idxA], key) < 0 {
+				pos++
+			}
+			for l := pos; l < len(left.Rows) && compareJoinKeys(left.Rows[l][idxA], key) == 0; l++ {
+				matched = append(matched, l)
+			}
+			prevKey, merged = key, true
+		default:
+			if hash == nil {
+				strategy, hash = joinHash, hashRows(left.Rows, idxA)
+			}
+			matched = hash[joinKey(row[idxB])]
+		}
+
+		if len(matched) == 0 && !keepRight {
+			continue
+		}
+		for _, lIdx := range matched {
+			pairs = append(pairs, joinPair{lIdx, len(rows)})
+		}
+		rows = append(rows, row)
+	}
+
+	sortPairs(pairs)
+	return strategy, pairs, rows, nil
+}
+
+// hashRows: posisi baris dikelompokkeun dumasar joinKey kolom idx (NULL
+// teu kaasup).
+func hashRows(rows [][]string, idx int) map[string][]int {
+	table := make(map[string][]int, len(rows))
+	for i, row := range rows {
+		if idx < len(row) && !schema.IsNull(row[idx]) {
+			key := joinKey(row[idx])
+			table[key] = append(table[key], i)
+		}
+	}
+	return table
+}
+
+// indexNestedLoopJoin ngagunakeun index TANDAIN dina kolom tabel join.
+// Konci index disaruakeun heula ku joinKey (1 = 1.0), teras ngan baris
+// katuhu nu PK-na kapendak di index nu dibaca tina tabel. Mulihkeun pasangan
+// sareng baris katuhu nu dibaca éta.
+func indexNestedLoopJoin(left joinSide, right joinSource, idxA, idxB int, idxMap indexing.IndexMap) ([]joinPair, [][]string, error) {
+	byKey := make(map[string][]string, len(idxMap))
+	for val, pks := range idxMap {
+		if !schema.IsNull(val) {
+			key := joinKey(val)
+			byKey[key] = append(byKey[key], pks...)
+		}
+	}
+
+	wanted := make(map[string]bool)
+	for _, row := range left.Rows {
+		if idxA < len(row) && !schema.IsNull(row[idxA]) {
+			for _, pk := range byKey[joinKey(row[idxA])] {
+				wanted[pk] = true
+			}
+		}
+	}
+	if len(wanted) == 0 {
+		return nil, nil, nil
+	}
+	rows, err := drainIterator(right.scan(wanted))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byPK := make(map[string]int, len(rows))
+	for i, row := range rows {
+		byPK[row[0]] = i
+	}
+
+	var pairs []joinPair
+	for lIdx, row := range left.Rows {
+		if idxA >= len(row) || schema.IsNull(row[idxA]) {
+			continue
+		}
+		key := joinKey(row[idxA])
+		var matched []int
+		for _, pk := range byKey[key] {
+			rIdx, ok := byPK[pk]
+			if ok && idxB < len(rows[rIdx]) && joinKey(rows[rIdx][idxB]) == key {
+				matched = append(matched, rIdx)
+			}
+		}
+		sort.Ints(matched)
+		for i, rIdx := range matched {
+			if i > 0 && matched[i-1] == rIdx {
+				continue
+			}
+			pairs = append(pairs, joinPair{lIdx, rIdx})
+		}
+	}
+	return pairs, rows, nil
+}
+
+// assembleJoinRows nyusun hasil ahir, kaasup baris NULL keur
+// LEFT / RIGHT / FULL join.
+func assembleJoinRows(left, right joinSide, pairs []joinPair, joinType string) [][]string {
+	keepLeft := keepsLeft(joinType)
+	keepRight := keepsRight(joinType)
+
+	var result [][]string
+	matchedRight := make(map[int]bool)
+
+	p := 0
+	for lIdx, leftRow := range left.Rows {
+		matchedLeft := false
+		for p < len(pairs) && pairs[p].Left == lIdx {
+			merged := append([]string{}, leftRow...)
+			merged = append(merged, right.Rows[pairs[p].Right]...)
+			result = append(result, merged)
+			matchedRight[pairs[p].Right] = true
+			matchedLeft = true
+			p++
+		}
+		if !matchedLeft && keepLeft {
+			merged := append([]string{}, leftRow...)
+			for range right.Header {
+				merged = append(merged, schema.NullValue)
+			}
+			result = append(result, merged)
+		}
+	}
+
+	if keepRight {
+		for rIdx, rightRow := range right.Rows {
+			if !matchedRight[rIdx] {
+				merged := []string{}
+				for range left.Header {
+					merged = append(merged, schema.NullValue)
+				}
+				merged = append(merged, rightRow...)
+				result = append(result, merged)
+			}
+		}
+	}
+	return result
+}
+
+func sortPairs(pairs []joinPair) {
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Left != pairs[j].Left {
+			return pairs[i].Left < pairs[j].Left
+		}
+		return pairs[i].Right < pairs[j].Right
+	})
+}
+
+func isSortedOn(rows [][]string, idx int) bool {
+	for i := range rows {
+		if idx >= len(rows[i]) {
+			return false
+		}
+		if i > 0 && compareJoinKeys(rows[i-1][idx], rows[i][idx]) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// joinKey nyaruakeun angka (1 jeung 1.0) sangkan hash join satuju
+// jeung hasil match().
+func joinKey(v string) string {
+	if f, err := strconv.ParseFloat(v, 64); err == nil {
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	return v
+}
+
+// compareJoinKeys: angka dibandingkeun sacara numerik sarta salawasna
+// saméméh téks, téks dibandingkeun per bait.
+func compareJoinKeys(a, b string) int {
+	fA, errA := strconv.ParseFloat(a, 64)
+	fB, errB := strconv.ParseFloat(b, 64)
+
+	switch {
+	case errA == nil && errB == nil:
+		if fA < fB {
+			return -1
+		}
+		if fA > fB {
+			return 1
+		}
+		return 0
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,183 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/febrd/maungdb/engine/parser"
+	"github.com/febrd/maungdb/engine/storage"
+)
+
+func TestIndexNestedLoopJoin(t *testing.T) {
+	queries := []string{
+		"DAMEL pesenan id:INT:PK, produk:STRING",
+		"DAMEL produk id:INT:PK, kode:STRING, ngaran:STRING",
+		"DAMEL produk_pk2 kode:STRING, id:INT:PK, ngaran:STRING",
+		"SIMPEN pesenan 1|01",
+		"SIMPEN pesenan 2|3.0",
+		"SIMPEN pesenan 3|euweuh",
+	}
+	for i := 1; i <= 20; i++ {
+		queries = append(queries,
+			fmt.Sprintf("SIMPEN produk %d|%d|p%d", i, i, i),
+			fmt.Sprintf("SIMPEN produk_pk2 %d|%d|p%d", i, i, i),
+		)
+	}
+	queries = append(queries, "TANDAIN produk DINA kode", "TANDAIN produk_pk2 DINA kode")
+	run(t, queries...)
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"index, konci disaruakeun", "TINGALI pesenan.id, produk.ngaran TI pesenan GABUNG produk DINA pesenan.produk = produk.kode", []string{"1|p1", "2|p3"}},
+		{"index, LEFT join", "TINGALI pesenan.id, produk.ngaran TI pesenan KENCA GABUNG produk DINA pesenan.produk = produk.kode", []string{"1|p1", "2|p3", `3|\N`}},
+		{"PK sanés kolom kahiji", "TINGALI pesenan.id, produk_pk2.ngaran TI pesenan GABUNG produk_pk2 DINA pesenan.produk = produk_pk2.kode", []string{"1|p1", "2|p3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rowsOf(run(t, tt.query))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJoinIndexChoice(t *testing.T) {
+	run(t, "DAMEL pilih id:INT:PK, kode:STRING", "TANDAIN pilih DINA kode")
+	s := mustSchema(t, "pilih")
+	right := joinSource{Table: "pilih", Header: []string{"pilih.id", "pilih.kode"}, Schema: s, Count: 10}
+	eq := parser.Condition{Field: "x", Operator: "=", Value: "pilih.kode"}
+
+	tests := []struct {
+		name     string
+		leftRows int
+		op       string
+		joinType string
+		want     bool
+	}{
+		{"kénca leutik", 1, "=", "", true},
+		{"kénca ageung", 5, "=", "", false},
+		{"sanés equi-join", 1, ">", "", false},
+		{"RIGHT join", 1, "=", "KATUHU", false},
+		{"FULL join", 1, "=", "PINUH", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			left := joinSide{Rows: make([][]string, tt.leftRows)}
+			cond := eq
+			cond.Operator = tt.op
+			if _, got := joinIndex(left, right, 0, 1, cond, tt.joinType); got != tt.want {
+				t.Errorf("joinIndex = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	pk2 := *s
+	pk2.Columns = append(pk2.Columns[:0:0], s.Columns...)
+	pk2.Columns[0].IsPrimary, pk2.Columns[1].IsPrimary = false, true
+	right.Schema = &pk2
+	if _, ok := joinIndex(joinSide{Rows: make([][]string, 1)}, right, 0, 1, eq, ""); ok {
+		t.Error("index nested loop dipilih padahal PK sanés kolom kahiji")
+	}
+}
+
+func TestStreamingJoin(t *testing.T) {
+	run(t,
+		"DAMEL kota id:INT:PK, ngaran:STRING",
+		"DAMEL warga id:INT:PK, ngaran:STRING, kota:INT",
+		"SIMPEN kota 1|Bandung",
+		"SIMPEN kota 2|Garut",
+		"SIMPEN kota 3|Cianjur",
+		"SIMPEN warga 1|Asep|2",
+		"SIMPEN warga 2|Euis|1",
+		"SIMPEN warga 3|Ujang|2",
+		"SIMPEN warga 4|Dadang|9",
+		"SIMPEN warga 5|Nining|NULL",
+	)
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		// kota (3 baris) runtuy dumasar id, warga dialirkeun teu runtuy.
+		{"merge lajeng hash", "TINGALI kota.ngaran, warga.ngaran TI kota GABUNG warga DINA kota.id = warga.kota", []string{"Bandung|Euis", "Garut|Asep", "Garut|Ujang"}},
+		{"LEFT join", "TINGALI kota.ngaran, warga.ngaran TI kota KENCA GABUNG warga DINA kota.id = warga.kota", []string{"Bandung|Euis", "Garut|Asep", "Garut|Ujang", `Cianjur|\N`}},
+		{"RIGHT join", "TINGALI kota.ngaran, warga.ngaran TI kota KATUHU GABUNG warga DINA kota.id = warga.kota", []string{"Bandung|Euis", "Garut|Asep", "Garut|Ujang", `\N|Dadang`, `\N|Nining`}},
+		{"FULL join", "TINGALI kota.ngaran, warga.ngaran TI kota PINUH GABUNG warga DINA kota.id = warga.kota", []string{"Bandung|Euis", "Garut|Asep", "Garut|Ujang", `Cianjur|\N`, `\N|Dadang`, `\N|Nining`}},
+		{"nested loop", "TINGALI kota.ngaran, warga.ngaran TI kota GABUNG warga DINA kota.id > warga.kota", []string{"Garut|Euis", "Cianjur|Asep", "Cianjur|Euis", "Cianjur|Ujang"}},
+		// warga (5 baris) langkung ageung: kota janten hash build side.
+		{"kénca dialirkeun", "TINGALI warga.ngaran, kota.ngaran TI warga GABUNG kota DINA warga.kota = kota.id", []string{"Asep|Garut", "Euis|Bandung", "Ujang|Garut"}},
+		{"kénca dialirkeun, LEFT", "TINGALI warga.ngaran, kota.ngaran TI warga KENCA GABUNG kota DINA warga.kota = kota.id", []string{"Asep|Garut", "Euis|Bandung", "Ujang|Garut", `Dadang|\N`, `Nining|\N`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rowsOf(run(t, tt.query))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamJoinStrategy(t *testing.T) {
+	run(t,
+		"DAMEL runtuy id:INT:PK, ngaran:STRING",
+		"SIMPEN runtuy 1|hiji",
+		"SIMPEN runtuy 2|dua",
+		"SIMPEN runtuy 3|tilu",
+	)
+	right := joinSource{Table: "runtuy", Header: []string{"runtuy.id", "runtuy.ngaran"}, Schema: mustSchema(t, "runtuy"), Count: 3}
+	eq := parser.Condition{Field: "k.x", Operator: "=", Value: "runtuy.id"}
+
+	tests := []struct {
+		name      string
+		left      [][]string
+		op        string
+		strategy  joinStrategy
+		wantPairs []joinPair
+	}{
+		{"merge", [][]string{{"1"}, {"3"}, {"3"}}, "=", joinMerge, []joinPair{{0, 0}, {1, 1}, {2, 1}}},
+		{"hash", [][]string{{"3"}, {"1.0"}}, "=", joinHash, []joinPair{{0, 1}, {1, 0}}},
+		{"nested loop", [][]string{{"2"}}, ">", joinNestedLoop, []joinPair{{0, 0}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			left := joinSide{Table: "k", Header: []string{"k.x"}, Rows: tt.left}
+			cond := eq
+			cond.Operator = tt.op
+			strategy, pairs, _, err := streamJoin(left, right, 0, 0, cond, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strategy != tt.strategy || !reflect.DeepEqual(pairs, tt.wantPairs) {
+				t.Errorf("got %s %v, want %s %v", strategy, pairs, tt.strategy, tt.wantPairs)
+			}
+		})
+	}
+}
+
+// TestJoinMaungExtension: tabel nu filena .maung (sanés .mg) tiasa digabung.
+func TestJoinMaungExtension(t *testing.T) {
+	run(t,
+		"DAMEL dusun id:INT:PK, ngaran:STRING",
+		"DAMEL pangeusi id:INT:PK, ngaran:STRING, dusun:INT",
+		"SIMPEN dusun 1|Cikoneng",
+		"SIMPEN pangeusi 1|Iis|1",
+	)
+	dbPath := storage.DatabasePath("uji")
+	if err := os.Rename(filepath.Join(dbPath, "dusun.mg"), filepath.Join(dbPath, "dusun.maung")); err != nil {
+		t.Fatal(err)
+	}
+
+	got := rowsOf(run(t, "TINGALI pangeusi.ngaran, dusun.ngaran TI pangeusi GABUNG dusun DINA pangeusi.dusun = dusun.id"))
+	if want := []string{"Iis|Cikoneng"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
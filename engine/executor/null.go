@@ -0,0 +1,79 @@
+package executor
+
+import (
+	"strings"
+
+	"github.com/febrd/maungdb/engine/parser"
+	"github.com/febrd/maungdb/engine/schema"
+)
+
+// truth: logika tilu nilai (SQL). Babandingan jeung NULL hasilna
+// truthUnknown; DIMANA ngan nampi truthTrue, CHECK ngan nolak truthFalse.
+type truth int8
+
+const (
+	truthFalse truth = iota
+	truthUnknown
+	truthTrue
+)
+
+func toTruth(b bool) truth {
+	if b {
+		return truthTrue
+	}
+	return truthFalse
+}
+
+func (t truth) and(o truth) truth {
+	if o < t {
+		return o
+	}
+	return t
+}
+
+func (t truth) or(o truth) truth {
+	if o > t {
+		return o
+	}
+	return t
+}
+
+// combineTruth ngahijikeun hasil unggal syarat ti kénca ka katuhu nuturkeun
+// SARENG/ATAWA.
+func combineTruth(conditions []parser.Condition, eval func(parser.Condition) truth) truth {
+	if len(conditions) == 0 {
+		return truthTrue
+	}
+
+	result := eval(conditions[0])
+	for i := 0; i < len(conditions)-1; i++ {
+		cond := conditions[i]
+		if cond.LogicOp == "" {
+			break
+		}
+		next := eval(conditions[i+1])
+		switch strings.ToUpper(cond.LogicOp) {
+		case "SARENG", "AND":
+			result = result.and(next)
+		case "ATAWA", "OR":
+			result = result.or(next)
+		}
+	}
+	return result
+}
+
+// matchNull nanganan IS NULL / IS NOT NULL sareng babandingan nu ngalibetkeun
+// NULL. ok=false hartosna duanana sanés NULL, babandingan biasa dilajengkeun.
+func matchNull(a, op, b, colType string) (truth, bool) {
+	aNull := schema.IsNullFor(colType, a)
+	switch op {
+	case parser.OpIsNull:
+		return toTruth(aNull), true
+	case parser.OpIsNotNull:
+		return toTruth(!aNull), true
+	}
+	if aNull || schema.IsNull(b) {
+		return truthUnknown, true
+	}
+	return truthFalse, false
+}
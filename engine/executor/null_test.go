@@ -0,0 +1,47 @@
+package executor
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestNullLogic: NULL béda ti string kosong, sareng babandingan ka NULL
+// hasilna TEU PASTI (teu lebet kana hasil), kalebet dina SANES/ATAWA.
+func TestNullLogic(t *testing.T) {
+	run(t,
+		"DAMEL null_uji id:INT:PK, umur:INT, catetan:STRING",
+		"SIMPEN null_uji 1|20|aya",
+		"SIMPEN null_uji 2|NULL|''",
+		"SIMPEN null_uji 3||NULL",
+		"SIMPEN null_uji 4|35|'NULL'",
+	)
+	cases := []struct {
+		query string
+		want  []string
+	}{
+		{"TINGALI id TI null_uji DIMANA umur KOSONG", []string{"2", "3"}},
+		{"TINGALI id TI null_uji DIMANA umur IS NOT NULL", []string{"1", "4"}},
+		{"TINGALI id TI null_uji DIMANA catetan IS NULL", []string{"3"}},
+		{"TINGALI id TI null_uji DIMANA catetan TEU KOSONG", []string{"1", "2", "4"}},
+		{"TINGALI id TI null_uji DIMANA catetan = ''", []string{"2"}},
+		{"TINGALI id TI null_uji DIMANA catetan = 'NULL'", []string{"4"}},
+		{"TINGALI id TI null_uji DIMANA umur > 18", []string{"1", "4"}},
+		{"TINGALI id TI null_uji DIMANA umur <= 18", []string{}},
+		{"TINGALI id TI null_uji DIMANA umur != 20", []string{"4"}},
+		{"TINGALI id TI null_uji DIMANA umur = NULL", []string{}},
+		{"TINGALI id TI null_uji DIMANA umur > 30 ATAWA umur KOSONG", []string{"2", "3", "4"}},
+		{"TINGALI id TI null_uji DIMANA umur > 30 SARENG catetan KOSONG", []string{}},
+	}
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			if got := rowsOf(run(t, c.query)); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("hasil = %v, want %v", got, c.want)
+			}
+		})
+	}
+
+	res := run(t, "TINGALI catetan TI null_uji DIMANA id = 3")
+	if len(res.Rows) != 1 || res.Rows[0][0] != `\N` {
+		t.Errorf("NULL disimpen salaku %q", res.Rows)
+	}
+}
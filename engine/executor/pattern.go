@@ -0,0 +1,210 @@
+package executor
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/febrd/maungdb/engine/indexing"
+	"github.com/febrd/maungdb/engine/parser"
+	"github.com/febrd/maungdb/engine/schema"
+)
+
+// Operator pola. JIGA/LIKE teu paduli hurup gede-leutik, JIGA_PERSIS/LIKE_CS
+// persis per bait, COCOK/REGEXP nganggo regexp Go.
+const (
+	patternNone = iota
+	patternLike
+	patternLikeCS
+	patternRegexp
+)
+
+// defaultLikeEscape dipaké mun query teu nyebutkeun ESCAPE.
+const defaultLikeEscape = `\`
+
+func patternKind(op string) int {
+	switch strings.ToUpper(op) {
+	case "JIGA", "LIKE", "ILIKE":
+		return patternLike
+	case "JIGA_PERSIS", "LIKE_CS":
+		return patternLikeCS
+	case "COCOK", "REGEXP":
+		return patternRegexp
+	}
+	return patternNone
+}
+
+// patternCache nyimpen regexp nu geus dikompilasi sangkan teu dikompilasi
+// deui unggal baris. Dikosongkeun mun geus pinuh.
+var (
+	patternMu    sync.Mutex
+	patternCache = make(map[string]*regexp.Regexp)
+)
+
+const patternCacheLimit = 256
+
+func cachedRegexp(key string, build func() (string, error)) (*regexp.Regexp, error) {
+	patternMu.Lock()
+	defer patternMu.Unlock()
+
+	if re, ok := patternCache[key]; ok {
+		return re, nil
+	}
+	expr, err := build()
+	if err != nil {
+		return nil, err
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(patternCache) >= patternCacheLimit {
+		patternCache = make(map[string]*regexp.Regexp)
+	}
+	patternCache[key] = re
+	return re, nil
+}
+
+// likeCollation: JIGA nganggo NOCASE, atanapi ID mun kolomna ID (sangkan
+// "jose" JIGA "José"). JIGA_PERSIS salawasna BINARY.
+func likeCollation(kind int, col schema.Column) string {
+	if kind == patternLikeCS {
+		return ""
+	}
+	if col.Collation == schema.CollateID {
+		return schema.CollateID
+	}
+	return schema.CollateNoCase
+}
+
+// likeRegexp narjamahkeun pola LIKE (% = naon waé, _ = hiji karakter)
+// jadi regexp nu dijangkar dina awal sareng tungtung.
+func likeRegexp(pattern, escape, collation string) (*regexp.Regexp, error) {
+	return cachedRegexp("L\x00"+collation+"\x00"+escape+"\x00"+pattern, func() (string, error) {
+		esc := []rune(escape)
+		if len(esc) > 1 {
+			return "", errors.New("ESCAPE kedah hiji karakter")
+		}
+
+		var b strings.Builder
+		b.WriteString("(?s)^")
+		runes := []rune(pattern)
+		for i := 0; i < len(runes); i++ {
+			r := runes[i]
+			switch {
+			case len(esc) == 1 && r == esc[0]:
+				if i+1 >= len(runes) {
+					return "", errors.New("pola JIGA ditungtungan ku karakter ESCAPE")
+				}
+				i++
+				b.WriteString(regexp.QuoteMeta(schema.CollationKey(collation, string(runes[i]))))
+			case r == '%':
+				b.WriteString(".*")
+			case r == '_':
+				b.WriteString(".")
+			default:
+				b.WriteString(regexp.QuoteMeta(schema.CollationKey(collation, string(r))))
+			}
+		}
+		b.WriteString("$")
+		return b.String(), nil
+	})
+}
+
+func conditionEscape(cond parser.Condition) string {
+	if cond.Escape != "" {
+		return cond.Escape
+	}
+	return defaultLikeEscape
+}
+
+// matchPattern ngevaluasi JIGA / JIGA_PERSIS / COCOK. Pola nu teu valid
+// dianggap teu cocok.
+func matchPattern(kind int, a string, cond parser.Condition, col schema.Column) truth {
+	if kind == patternRegexp {
+		re, err := cachedRegexp("R\x00"+cond.Value, func() (string, error) { return cond.Value, nil })
+		if err != nil {
+			return truthFalse
+		}
+		return toTruth(re.MatchString(a))
+	}
+
+	collation := likeCollation(kind, col)
+	re, err := likeRegexp(cond.Value, conditionEscape(cond), collation)
+	if err != nil {
+		return truthFalse
+	}
+	return toTruth(re.MatchString(schema.CollationKey(collation, a)))
+}
+
+// likePrefix mulihkeun bagian literal di payuneun wildcard munggaran
+// ("Asep%" -> "Asep"). Kosong mun pola dimimitian ku wildcard.
+func likePrefix(pattern, escape string) string {
+	esc := []rune(escape)
+	var b strings.Builder
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case len(esc) == 1 && r == esc[0]:
+			if i+1 >= len(runes) {
+				return b.String()
+			}
+			i++
+			b.WriteRune(runes[i])
+		case r == '%' || r == '_':
+			return b.String()
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// indexCandidates milih PK calon tina index TANDAIN pikeun hiji syarat:
+// "=" (nuturkeun kolasi) atanapi JIGA nu gaduh awalan literal. Hasilna
+// ngan saringan kasar; syarat tetep dievaluasi deui ku filterIter.
+func indexCandidates(idxMap indexing.IndexMap, cond parser.Condition, collation string) (map[string]bool, bool) {
+	var pks []string
+
+	switch kind := patternKind(cond.Operator); {
+	case cond.Operator == "=":
+		if collation == "" {
+			pks = idxMap[cond.Value]
+			break
+		}
+		want := schema.CollationKey(collation, cond.Value)
+		for val, list := range idxMap {
+			if schema.CollationKey(collation, val) == want {
+				pks = append(pks, list...)
+			}
+		}
+
+	case kind == patternLike || kind == patternLikeCS:
+		prefix := likePrefix(cond.Value, conditionEscape(cond))
+		if prefix == "" {
+			return nil, false
+		}
+		if kind == patternLikeCS {
+			pks = idxMap.PrefixLookup(prefix)
+			break
+		}
+		likeColl := likeCollation(kind, schema.Column{Collation: collation})
+		want := schema.CollationKey(likeColl, prefix)
+		for val, list := range idxMap {
+			if strings.HasPrefix(schema.CollationKey(likeColl, val), want) {
+				pks = append(pks, list...)
+			}
+		}
+
+	default:
+		return nil, false
+	}
+
+	set := make(map[string]bool, len(pks))
+	for _, pk := range pks {
+		set[pk] = true
+	}
+	return set, true
+}
@@ -0,0 +1,91 @@
+package executor
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/febrd/maungdb/engine/parser"
+	"github.com/febrd/maungdb/engine/schema"
+)
+
+func TestMatchPattern(t *testing.T) {
+	idCol := schema.Column{Collation: schema.CollateID}
+	cases := []struct {
+		op, value, escape string
+		col               schema.Column
+		text              string
+		want              bool
+	}{
+		{"JIGA", "Asep%", "", schema.Column{}, "asep sunarya", true},
+		{"JIGA", "asep", "", schema.Column{}, "asep sunarya", false},
+		{"JIGA", "%sun%", "", schema.Column{}, "Asep Sunarya", true},
+		{"JIGA", "a_ep", "", schema.Column{}, "asep", true},
+		{"JIGA", "a_ep", "", schema.Column{}, "aep", false},
+		{"JIGA", "%\n%", "", schema.Column{}, "baris\nkadua", true},
+		{"JIGA", "100\\%", "", schema.Column{}, "100%", true},
+		{"JIGA", "100\\%", "", schema.Column{}, "1000", false},
+		{"JIGA", "100!%", "!", schema.Column{}, "100%", true},
+		{"JIGA", "a.c", "", schema.Column{}, "abc", false}, // titik sanés wildcard
+		{"JIGA", "jose", "", idCol, "José", true},
+		{"JIGA", "jose", "", schema.Column{}, "José", false},
+		{"JIGA", "abc\\", "", schema.Column{}, "abc\\", false}, // pola teu valid
+		{"JIGA", "a%", "!!", schema.Column{}, "abc", false},    // ESCAPE leuwih ti hiji karakter
+		{"JIGA_PERSIS", "Asep%", "", schema.Column{}, "asep", false},
+		{"JIGA_PERSIS", "Asep%", "", schema.Column{}, "Asep", true},
+		{"COCOK", "^[0-9]{3}-", "", schema.Column{}, "022-123", true},
+		{"COCOK", "^[0-9]{3}-", "", schema.Column{}, "0221-23", false},
+		{"COCOK", "([", "", schema.Column{}, "([", false}, // regexp teu valid
+	}
+	for _, c := range cases {
+		t.Run(c.op+" "+c.value+" "+c.text, func(t *testing.T) {
+			cond := parser.Condition{Operator: c.op, Value: c.value, Escape: c.escape}
+			got := matchPattern(patternKind(c.op), c.text, cond, c.col) == truthTrue
+			if got != c.want {
+				t.Errorf("matchPattern = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestLikePrefix(t *testing.T) {
+	cases := []struct{ pattern, escape, want string }{
+		{"Asep%", `\`, "Asep"},
+		{"%Asep", `\`, ""},
+		{"As_p", `\`, "As"},
+		{`100\%%`, `\`, "100%"},
+		{`abc\`, `\`, "abc"},
+		{"abc", `\`, "abc"},
+	}
+	for _, c := range cases {
+		if got := likePrefix(c.pattern, c.escape); got != c.want {
+			t.Errorf("likePrefix(%q) = %q, want %q", c.pattern, got, c.want)
+		}
+	}
+}
+
+// TestLikeWithIndex: hasil JIGA sami naha aya TANDAIN atanapi henteu.
+func TestLikeWithIndex(t *testing.T) {
+	run(t,
+		"DAMEL jiga_uji id:INT:PK, ngaran:STRING",
+		"SIMPEN jiga_uji 1|Asep",
+		"SIMPEN jiga_uji 2|asep sunarya",
+		"SIMPEN jiga_uji 3|Ujang",
+		"SIMPEN jiga_uji 4|100%",
+	)
+	queries := map[string][]string{
+		"TINGALI id TI jiga_uji DIMANA ngaran JIGA 'asep%'":        {"1", "2"},
+		"TINGALI id TI jiga_uji DIMANA ngaran JIGA_PERSIS 'Asep%'": {"1"},
+		"TINGALI id TI jiga_uji DIMANA ngaran JIGA '100\\%'":       {"4"},
+		"TINGALI id TI jiga_uji DIMANA ngaran COCOK '^[AU]'":       {"1", "3"},
+	}
+	check := func(stage string) {
+		for q, want := range queries {
+			if got := rowsOf(run(t, q)); !reflect.DeepEqual(got, want) {
+				t.Errorf("%s: %s = %v, want %v", stage, q, got, want)
+			}
+		}
+	}
+	check("tanpa index")
+	run(t, "TANDAIN jiga_uji DINA ngaran")
+	check("nganggo index")
+}
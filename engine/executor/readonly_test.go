@@ -0,0 +1,51 @@
+package executor
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/febrd/maungdb/engine/replication"
+)
+
+// TestReadOnlyReplica: dina node ANAK sadaya jalur nyerat ditolak, tapi
+// TINGALI tetep jalan.
+func TestReadOnlyReplica(t *testing.T) {
+	run(t,
+		"DAMEL anak_uji id:INT:PK, isi:STRING",
+		"SIMPEN anak_uji NILAI (1, 'asli')",
+	)
+
+	replication.GlobalReplication.CurrentRole = replication.RoleSlave
+	defer func() { replication.GlobalReplication.CurrentRole = replication.RoleMaster }()
+
+	denied := []string{
+		"DAMEL anak_uji2 id:INT:PK",
+		"SIMPEN anak_uji NILAI (2, 'anyar')",
+		"OMEAN anak_uji JADI isi = 'robah' DIMANA id = 1",
+		"MICEUN TI anak_uji DIMANA id = 1",
+		"ROBIH TABEL anak_uji TAMBAH KOLOM umur:INT",
+		"PICEUN TABEL anak_uji",
+		"KOSONGKEUN anak_uji",
+		"DAMEL KACA anak_kaca TINA TINGALI isi TI anak_uji",
+		"DAMEL JARAMBAH anak_jm WAKTU SANGGEUS SIMPEN PADA anak_uji LAKUKAN TOLAK 'x'",
+		"TANDAIN anak_uji DINA isi",
+		"DAMEL INDEKS_TEKS anak_uji DINA isi",
+		"ATUR SINKRON 1",
+	}
+	for _, q := range denied {
+		t.Run(q, func(t *testing.T) {
+			_, err := exec(q)
+			if err == nil || !strings.Contains(err.Error(), "ANAK") {
+				t.Errorf("teu ditolak: %v", err)
+			}
+		})
+	}
+
+	if _, err := ImportCSV("anak_uji", "teu_aya.csv"); err == nil || !strings.Contains(err.Error(), "ANAK") {
+		t.Errorf("ImportCSV teu ditolak: %v", err)
+	}
+	if got := rowsOf(run(t, "TINGALI * TI anak_uji")); !reflect.DeepEqual(got, []string{"1|asli"}) {
+		t.Errorf("anak_uji = %v", got)
+	}
+}
@@ -0,0 +1,55 @@
+package executor
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/febrd/maungdb/engine/replication"
+	"github.com/febrd/maungdb/engine/schema"
+)
+
+// execShowReplication: TINGALI REPLIKASI. Baris munggaran node ieu, sésana
+// ANAK nu nuju ngintil (ngan dina INDUNG).
+func execShowReplication() (*ExecutionResult, error) {
+	st := replication.GlobalReplication.Status()
+
+	role := st.Role
+	if st.Fenced {
+		role += " (DIPAGER)"
+	}
+	lastContact := schema.NullValue
+	if st.LastContact != nil {
+		lastContact = st.LastContact.Format(time.RFC3339)
+	}
+
+	rows := [][]string{{
+		st.Node, "-", role, strconv.FormatUint(st.Generation, 10), nullable(st.Master),
+		strconv.FormatUint(st.LSN, 10), masterLSN(st.MasterLSN, st.LSN),
+		strconv.FormatInt(st.LagBytes, 10), fmt.Sprintf("%.1f", st.LagSeconds),
+		strconv.FormatBool(st.Connected), lastContact, nullable(st.LastError),
+	}}
+	for _, r := range st.Replicas {
+		rows = append(rows, []string{
+			r.Node, r.Addr, "ANAK", schema.NullValue, "-",
+			strconv.FormatUint(r.LSN, 10), strconv.FormatUint(st.LSN, 10),
+			strconv.FormatInt(r.LagBytes, 10), fmt.Sprintf("%.1f", r.LagSeconds),
+			strconv.FormatBool(r.Connected), r.LastSeen.Format(time.RFC3339), schema.NullValue,
+		})
+	}
+
+	return &ExecutionResult{
+		Columns: []string{"node", "alamat", "peran", "generasi", "indung", "lsn", "lsn_indung",
+			"telat_bait", "telat_detik", "nyambung", "kontak_pamungkas", "kasalahan"},
+		Rows:    rows,
+		Message: fmt.Sprintf("📡 Node ieu %s, %d ANAK kacatet", role, len(st.Replicas)),
+	}, nil
+}
+
+// masterLSN: dina INDUNG, LSN indung nyaéta LSN node ieu sorangan.
+func masterLSN(master, lsn uint64) string {
+	if master == 0 {
+		return strconv.FormatUint(lsn, 10)
+	}
+	return strconv.FormatUint(master, 10)
+}
@@ -0,0 +1,204 @@
+package executor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/febrd/maungdb/engine/fts"
+	"github.com/febrd/maungdb/engine/parser"
+	"github.com/febrd/maungdb/engine/schema"
+)
+
+// searchSpec: hiji MILARI(kolom, 'query') dina DIMANA. Skorna diitung
+// sakali ti indeks teks, teras dicocogkeun ka baris dumasar kolom kahiji
+// tabelna (ID baris indeks teks).
+type searchSpec struct {
+	table  string
+	column string
+	field  string // sakumaha ditulis: "judul" atanapi "buku.judul"
+	query  string
+	idCol  string
+	scores map[string]float64
+}
+
+// searchPlan ngumpulkeun sadaya MILARI sareng RELEVANSI hiji TINGALI.
+type searchPlan struct {
+	specs     []*searchSpec
+	relevance []string // field nu RELEVANSI-na dipénta
+}
+
+func searchColumn(i int) string { return fmt.Sprintf("MILARI#%d", i) }
+
+func relevanceColumn(field string) string { return "RELEVANSI(" + field + ")" }
+
+func snippetColumn(field string) string { return "CUPLIKAN(" + field + ")" }
+
+// planSearch ngaganti unggal kaayaan MILARI dina cmd.Where ku tés kana kolom
+// samentawis (MILARI#n TEU KOSONG), sangkan tiasa digabung sareng SARENG /
+// ATAWA biasa. RELEVANSI dina kolom sareng RUNTUYKEUN dirobih kana ngaran
+// bakuna. Mulihkeun nil mun query teu nganggo MILARI.
+func planSearch(cmd *parser.Command, db string) (*searchPlan, error) {
+	plan := &searchPlan{}
+	for i, cond := range cmd.Where {
+		if cond.Operator != parser.OpSearch {
+			continue
+		}
+		table, column := cmd.Table, cond.Field
+		if t, c, ok := strings.Cut(cond.Field, "."); ok {
+			table, column = t, c
+		}
+		s, err := schema.Load(db, table)
+		if err != nil {
+			return nil, fmt.Errorf("MILARI: tabel '%s' teu kapanggih", table)
+		}
+		if s.GetColumnIndex(column) == -1 {
+			return nil, fmt.Errorf("MILARI: kolom '%s' teu aya di tabel '%s'", column, table)
+		}
+
+		res, err := fts.GlobalFTS.Search(table, column, cond.Value, fts.Options{})
+		if err != nil {
+			return nil, err
+		}
+		spec := &searchSpec{
+			table: table, column: column, field: cond.Field, query: cond.Value,
+			idCol: table + "." + s.Columns[0].Name, scores: make(map[string]float64, len(res.Hits)),
+		}
+		for _, h := range res.Hits {
+			spec.scores[h.RowID] = h.Score
+		}
+
+		cmd.Where[i] = parser.Condition{Field: searchColumn(len(plan.specs)), Operator: parser.OpIsNotNull, LogicOp: cond.LogicOp}
+		plan.specs = append(plan.specs, spec)
+	}
+
+	want := func(expr string) (string, error) {
+		field, ok := parser.RelevanceTarget(expr)
+		if !ok {
+			return expr, nil
+		}
+		if !plan.searches(field) {
+			return "", fmt.Errorf("RELEVANSI(%s) butuh MILARI(%s, '...') dina DIMANA", field, field)
+		}
+		for _, f := range plan.relevance {
+			if f == field {
+				return relevanceColumn(field), nil
+			}
+		}
+		plan.relevance = append(plan.relevance, field)
+		return relevanceColumn(field), nil
+	}
+
+	for i, f := range cmd.Fields {
+		name, err := want(strings.TrimSpace(f))
+		if err != nil {
+			return nil, err
+		}
+		cmd.Fields[i] = name
+	}
+	if cmd.OrderBy != "" {
+		name, err := want(cmd.OrderBy)
+		if err != nil {
+			return nil, err
+		}
+		cmd.OrderBy = name
+	}
+
+	if len(plan.specs) == 0 {
+		return nil, nil
+	}
+	return plan, nil
+}
+
+func (p *searchPlan) searches(field string) bool {
+	for _, s := range p.specs {
+		if s.field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// candidates: mun DIMANA ngan ukur hiji MILARI kana tabel utama, ID baris
+// hasil KOREHAN dipaké langsung kanggo scan (sapertos index scan TANDAIN).
+func (p *searchPlan) candidates(cmd *parser.Command) map[string]bool {
+	if len(cmd.Where) != 1 || len(p.specs) != 1 || p.specs[0].table != cmd.Table {
+		return nil
+	}
+	ids := make(map[string]bool, len(p.specs[0].scores))
+	for id := range p.specs[0].scores {
+		ids[id] = true
+	}
+	return ids
+}
+
+// addColumnDefs: RELEVANSI dirunut sacara numerik.
+func (p *searchPlan) addColumnDefs(defs map[string]schema.Column) {
+	for _, f := range p.relevance {
+		defs[relevanceColumn(f)] = schema.Column{Name: relevanceColumn(f), Type: "FLOAT"}
+	}
+}
+
+// searchIter nambihan kolom samentawis MILARI#n (skor atanapi NULL) sareng
+// RELEVANSI(kolom) (jumlah skor MILARI kana kolom éta) dina tungtung baris.
+type searchIter struct {
+	child RowIterator
+	plan  *searchPlan
+	ids   []int
+}
+
+func (it *searchIter) Open() error {
+	if err := it.child.Open(); err != nil {
+		return err
+	}
+	header := it.child.Columns()
+	it.ids = make([]int, len(it.plan.specs))
+	for i, s := range it.plan.specs {
+		it.ids[i] = resolveProjection(s.idCol, header)
+		if it.ids[i] == -1 {
+			return fmt.Errorf("MILARI: tabel '%s' teu aya dina TINGALI ieu", s.table)
+		}
+	}
+	return nil
+}
+
+func (it *searchIter) Next() ([]string, error) {
+	row, err := it.child.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	relevance := make(map[string]float64)
+	out := append([]string{}, row...)
+	for i, s := range it.plan.specs {
+		score, ok := s.scores[row[it.ids[i]]]
+		if !ok {
+			out = append(out, schema.NullValue)
+			continue
+		}
+		out = append(out, strconv.FormatFloat(score, 'f', 4, 64))
+		relevance[s.field] += score
+	}
+	for _, f := range it.plan.relevance {
+		out = append(out, strconv.FormatFloat(relevance[f], 'f', 4, 64))
+	}
+	return out, nil
+}
+
+func (it *searchIter) Close() error { return it.child.Close() }
+
+func (it *searchIter) Columns() []string {
+	header := append([]string{}, it.child.Columns()...)
+	for i := range it.plan.specs {
+		header = append(header, searchColumn(i))
+	}
+	for _, f := range it.plan.relevance {
+		header = append(header, relevanceColumn(f))
+	}
+	return header
+}
+
+// baseColumns: kolom "*" teu kalebet kolom samentawis MILARI/RELEVANSI.
+func (it *searchIter) baseColumns() []string {
+	return it.child.Columns()
+}
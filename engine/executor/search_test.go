@@ -0,0 +1,82 @@
+package executor
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestSearchPredicates: MILARI dina DIMANA tiasa digabung sareng kaayaan
+// biasa sareng GABUNG, sareng RELEVANSI tiasa dipidangkeun atanapi dirunut.
+func TestSearchPredicates(t *testing.T) {
+	run(t,
+		"DAMEL artikel id:INT:PK, judul:STRING, isi:STRING",
+		"SIMPEN artikel NILAI (1, 'a', 'maung lapar di leuweung'), (2, 'b', 'maung maung maung di kebon'), (3, 'c', 'ucing di imah'), (4, 'd', 'maung bodas')",
+		"DAMEL INDEKS_TEKS artikel DINA isi",
+		"DAMEL komentar id:INT:PK, artikel_id:INT, teks:STRING",
+		"SIMPEN komentar NILAI (1, 1, 'tulisan alus'), (2, 3, 'kirang alus'), (3, 4, 'biasa')",
+		"DAMEL INDEKS_TEKS komentar DINA teks",
+	)
+
+	cases := []struct {
+		query   string
+		want    []string
+		ordered bool
+	}{
+		{"TINGALI id TI artikel DIMANA MILARI(isi, 'maung')", []string{"1", "2", "4"}, false},
+		{"TINGALI id TI artikel DIMANA MILARI(isi, 'maung') SARENG id > 1", []string{"2", "4"}, false},
+		{"TINGALI id TI artikel DIMANA MILARI(isi, 'ucing') ATAWA id = 4", []string{"3", "4"}, false},
+		{`TINGALI id TI artikel DIMANA MATCH(isi, "imah")`, []string{"3"}, false},
+		{"TINGALI id TI artikel DIMANA MILARI(isi, 'gajah')", []string{}, false},
+		{"TINGALI * TI artikel DIMANA MILARI(isi, 'bodas')", []string{"4|d|maung bodas"}, false},
+		{"TINGALI id TI artikel DIMANA MILARI(isi, 'maung') RUNTUYKEUN RELEVANSI(isi)", []string{"2", "4", "1"}, true},
+		{"TINGALI id TI artikel DIMANA MILARI(isi, 'maung') RUNTUYKEUN RELEVANSI(isi) NAEK", []string{"1", "4", "2"}, true},
+		{"TINGALI artikel.id, komentar.id TI artikel GABUNG komentar DINA artikel.id = komentar.artikel_id DIMANA MILARI(komentar.teks, 'alus')", []string{"1|1", "3|2"}, false},
+		{"TINGALI artikel.id TI artikel GABUNG komentar DINA artikel.id = komentar.artikel_id DIMANA MILARI(komentar.teks, 'alus') SARENG MILARI(artikel.isi, 'maung')", []string{"1"}, false},
+	}
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			got := rowsOf(run(t, c.query))
+			if !c.ordered {
+				got = sorted(got)
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("hasil = %v, want %v", got, c.want)
+			}
+		})
+	}
+
+	res := run(t, "TINGALI id, RELEVANSI(isi) TI artikel DIMANA MILARI(isi, 'maung')")
+	if want := []string{"id", "RELEVANSI(isi)"}; !reflect.DeepEqual(res.Columns, want) {
+		t.Errorf("kolom = %v, want %v", res.Columns, want)
+	}
+	for _, row := range res.Rows {
+		if score, err := strconv.ParseFloat(row[1], 64); err != nil || score <= 0 {
+			t.Errorf("RELEVANSI baris %s = %q", row[0], row[1])
+		}
+	}
+}
+
+func TestSearchPredicateErrors(t *testing.T) {
+	run(t, "DAMEL artikel_gagal id:INT:PK, isi:STRING")
+
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{"TINGALI id, RELEVANSI(isi) TI artikel_gagal", "butuh MILARI(isi"},
+		{"TINGALI id TI artikel_gagal RUNTUYKEUN RELEVANSI(isi)", "butuh MILARI(isi"},
+		{"TINGALI id TI artikel_gagal DIMANA MILARI(euweuh, 'x')", "kolom 'euweuh' teu aya"},
+		{"TINGALI id TI artikel_gagal DIMANA MILARI(euweuh.isi, 'x')", "tabel 'euweuh' teu kapanggih"},
+		{"TINGALI id TI artikel_gagal DIMANA MILARI(isi, 'x')", "INDEKS_TEKS"},
+	}
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			_, err := exec(c.query)
+			if err == nil || !strings.Contains(err.Error(), c.want) {
+				t.Fatalf("err = %v, want %q", err, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package executor
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/febrd/maungdb/engine/schema"
+	"github.com/febrd/maungdb/internal/config"
+)
+
+// sortIter (RUNTUYKEUN) ngumpulkeun baris nepi ka config.SortMemoryBudget.
+// Mun leuwih, baris nu geus dirunut ditulis ka file samentawis (run) sarta
+// dihijikeun deui ku k-way merge nalika Next.
+type sortIter struct {
+	child  RowIterator
+	colIdx int
+	col    schema.Column
+	desc   bool
+	budget int
+
+	runs   []sortRun
+	merger *runHeap
+}
+
+func newSortIter(child RowIterator, colIdx int, desc bool, col schema.Column) *sortIter {
+	return &sortIter{child: child, colIdx: colIdx, col: col, desc: desc, budget: config.SortMemoryBudget}
+}
+
+func (it *sortIter) Open() error {
+	if err := it.child.Open(); err != nil {
+		return err
+	}
+
+	var buf [][]string
+	size := 0
+	for {
+		row, err := it.child.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		buf = append(buf, row)
+		size += estimateRowSize(row)
+
+		if size > it.budget {
+			run, err := it.spill(buf)
+			if err != nil {
+				return err
+			}
+			it.runs = append(it.runs, run)
+			buf, size = nil, 0
+		}
+	}
+
+	it.sortRows(buf)
+	it.runs = append(it.runs, &memoryRun{rows: buf})
+
+	it.merger = &runHeap{less: it.less}
+	for i, run := range it.runs {
+		row, err := run.next()
+		if err == io.EOF {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		it.merger.items = append(it.merger.items, runHead{row: row, run: i})
+	}
+	heap.Init(it.merger)
+	return nil
+}
+
+func (it *sortIter) Next() ([]string, error) {
+	if it.merger == nil || it.merger.Len() == 0 {
+		return nil, io.EOF
+	}
+
+	head := it.merger.items[0]
+	next, err := it.runs[head.run].next()
+	switch {
+	case err == io.EOF:
+		heap.Pop(it.merger)
+	case err != nil:
+		return nil, err
+	default:
+		it.merger.items[0].row = next
+		heap.Fix(it.merger, 0)
+	}
+	return head.row, nil
+}
+
+func (it *sortIter) Close() error {
+	for _, run := range it.runs {
+		run.close()
+	}
+	it.runs = nil
+	return it.child.Close()
+}
+
+func (it *sortIter) Columns() []string { return it.child.Columns() }
+
+func (it *sortIter) less(a, b []string) bool {
+	cmp := compareSortValues(it.col, a[it.colIdx], b[it.colIdx])
+	if it.desc {
+		return cmp > 0
+	}
+	return cmp < 0
+}
+
+func (it *sortIter) sortRows(rows [][]string) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		return it.less(rows[i], rows[j])
+	})
+}
+
+func (it *sortIter) spill(rows [][]string) (sortRun, error) {
+	it.sortRows(rows)
+
+	f, err := os.CreateTemp("", "maung-sort-*.run")
+	if err != nil {
+		return nil, err
+	}
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, row := range rows {
+		if err := enc.Encode(row); err != nil {
+			f.Close()
+			os.Remove(f.Name())
+			return nil, err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+
+	return &fileRun{file: f, dec: json.NewDecoder(bufio.NewReader(f))}, nil
+}
+
+// compareSortValues nuturkeun tipe kolom (DECIMAL, DATETIME, ...); mun
+// tipe teu kanyahoan, angka dibandingkeun numerik, téks nuturkeun kolasi.
+// NULL dianggap pangleutikna: di payun dina ASC, di pengker dina DESC.
+func compareSortValues(col schema.Column, a, b string) int {
+	aNull, bNull := schema.IsNullFor(col.Type, a), schema.IsNullFor(col.Type, b)
+	switch {
+	case aNull && bNull:
+		return 0
+	case aNull:
+		return -1
+	case bNull:
+		return 1
+	}
+	if cmp, ok := schema.CompareValues(col.Type, a, b); ok {
+		return cmp
+	}
+	return schema.CompareText(col.Collation, a, b)
+}
+
+func estimateRowSize(row []string) int {
+	size := 24
+	for _, v := range row {
+		size += len(v) + 16
+	}
+	return size
+}
+
+type sortRun interface {
+	next() ([]string, error)
+	close()
+}
+
+type memoryRun struct {
+	rows [][]string
+	pos  int
+}
+
+func (r *memoryRun) next() ([]string, error) {
+	if r.pos >= len(r.rows) {
+		return nil, io.EOF
+	}
+	row := r.rows[r.pos]
+	r.pos++
+	return row, nil
+}
+
+func (r *memoryRun) close() { r.rows = nil }
+
+type fileRun struct {
+	file *os.File
+	dec  *json.Decoder
+}
+
+func (r *fileRun) next() ([]string, error) {
+	var row []string
+	if err := r.dec.Decode(&row); err != nil {
+		return nil, err
+	}
+	return row, nil
+}
+
+func (r *fileRun) close() {
+	if r.file == nil {
+		return
+	}
+	r.file.Close()
+	os.Remove(r.file.Name())
+	r.file = nil
+}
+
+type runHead struct {
+	row []string
+	run int
+}
+
+type runHeap struct {
+	items []runHead
+	less  func(a, b []string) bool
+}
+
+func (h *runHeap) Len() int { return len(h.items) }
+
+func (h *runHeap) Less(i, j int) bool {
+	if h.less(h.items[i].row, h.items[j].row) {
+		return true
+	}
+	if h.less(h.items[j].row, h.items[i].row) {
+		return false
+	}
+	return h.items[i].run < h.items[j].run
+}
+
+func (h *runHeap) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+
+func (h *runHeap) Push(x any) { h.items = append(h.items, x.(runHead)) }
+
+func (h *runHeap) Pop() any {
+	old := h.items
+	item := old[len(old)-1]
+	h.items = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,133 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/febrd/maungdb/engine/auth"
+	"github.com/febrd/maungdb/engine/parser"
+	"github.com/febrd/maungdb/engine/replication"
+)
+
+// txSync: aturan SINKRON tina MIMITIAN SINKRON, per pangguna, lumaku dugi
+// ka JADIKEUN / BATALKEUN.
+var (
+	txSyncMu sync.Mutex
+	txSync   = make(map[string]*parser.SyncDefinition)
+)
+
+func setTxSync(username string, def *parser.SyncDefinition) {
+	txSyncMu.Lock()
+	defer txSyncMu.Unlock()
+	if def == nil {
+		delete(txSync, username)
+		return
+	}
+	txSync[username] = def
+}
+
+func popTxSync(username string) *parser.SyncDefinition {
+	txSyncMu.Lock()
+	defer txSyncMu.Unlock()
+	def := txSync[username]
+	delete(txSync, username)
+	return def
+}
+
+// execSetSync: ATUR SINKRON <n> [TUNGGU <detik>] [TULUY|GAGAL] kanggo
+// database nu nuju dipaké. SINKRON 0 mulihkeun kana asinkron.
+func execSetSync(cmd *parser.Command) (*ExecutionResult, error) {
+	user, err := auth.CurrentUser()
+	if err != nil {
+		return nil, err
+	}
+	if user.Database == "" {
+		return nil, errors.New("can use database heula")
+	}
+
+	p := replication.SyncPolicy{Replicas: cmd.Sync.Replicas, Timeout: cmd.Sync.Timeout, OnTimeout: cmd.Sync.OnTimeout}
+	if p.Replicas > 0 && p.OnTimeout == "" {
+		p.OnTimeout = parser.SyncFail
+	}
+	if err := replication.SaveSyncPolicy(user.Database, p); err != nil {
+		return nil, fmt.Errorf("gagal nyimpen aturan sinkron: %v", err)
+	}
+
+	if p.Replicas == 0 {
+		return &ExecutionResult{Message: fmt.Sprintf("🔓 Database '%s' balik deui kana réplikasi asinkron", user.Database)}, nil
+	}
+	return &ExecutionResult{Message: fmt.Sprintf("🔁 Database '%s' ayeuna sinkron: ngantosan %s", user.Database, describeSync(p))}, nil
+}
+
+func describeSync(p replication.SyncPolicy) string {
+	return fmt.Sprintf("%d ANAK, paling lami %v, mun telat: %s", p.Replicas, p.TimeoutDuration(), p.OnTimeout)
+}
+
+// syncPolicyFor: aturan transaksi (mun aya) ngaléngkahan aturan database.
+func syncPolicyFor(db string, override *parser.SyncDefinition) (replication.SyncPolicy, error) {
+	if override != nil {
+		p := replication.SyncPolicy{Replicas: override.Replicas, Timeout: override.Timeout, OnTimeout: override.OnTimeout}
+		if p.OnTimeout == "" {
+			p.OnTimeout = parser.SyncFail
+		}
+		return p, nil
+	}
+	if db == "" {
+		return replication.SyncPolicy{}, nil
+	}
+	return replication.LoadSyncPolicy(db)
+}
+
+// ErrNotReplicated: paréntahna parantos KOMIT di INDUNG (teu dibatalkeun),
+// ngan aturan SINKRON ... GAGAL teu kacumponan dina waktosna. Pariksa ku
+// errors.Is; ulah ngulang paréntahna, datana parantos aya.
+var ErrNotReplicated = errors.New("parantos komit di INDUNG tapi teu karéplikasi")
+
+// awaitReplicas dipanggil saatos parobahan disimpen di INDUNG. Mun aturanana
+// sinkron, ngantosan dugi ka cekap ANAK mastikeun (ack saatos fsync).
+// Mulihkeun catetan kanggo pesen hasil, atanapi ErrNotReplicated mun
+// aturanana GAGAL.
+func awaitReplicas(db string, override *parser.SyncDefinition) (string, error) {
+	p, err := syncPolicyFor(db, override)
+	if err != nil {
+		return "", fmt.Errorf("gagal maca aturan sinkron: %v", err)
+	}
+	if p.Replicas == 0 {
+		return "", nil
+	}
+
+	rm := replication.GlobalReplication
+	if !rm.Serving() {
+		return syncFallback(p, "réplikasi teu aktip dina prosés ieu (jalankeun 'maung server'), teu aya ANAK nu tiasa mastikeun")
+	}
+
+	if err := rm.Capture(); err != nil {
+		return "", fmt.Errorf("réplikasi: gagal nyatet parobahan: %v", err)
+	}
+	timeout := p.TimeoutDuration()
+	acked := rm.WaitForReplicas(rm.LastLSN(), p.Replicas, timeout)
+	if acked >= p.Replicas {
+		return fmt.Sprintf("🔁 dikonfirmasi %d ANAK", acked), nil
+	}
+	return syncFallback(p, fmt.Sprintf("ngan %d/%d ANAK nu mastikeun dina %v", acked, p.Replicas, timeout))
+}
+
+func syncFallback(p replication.SyncPolicy, reason string) (string, error) {
+	if p.OnTimeout == parser.SyncContinue {
+		return "⚠️ " + reason + "; dilajengkeun asinkron", nil
+	}
+	return "", fmt.Errorf("⛔ %w: %s", ErrNotReplicated, reason)
+}
+
+// isSyncedWrite: paréntah nu ngarobih data sareng kedah ngantosan ANAK
+// mun databasena sinkron. Transaksi diurus nyalira dina JADIKEUN.
+func isSyncedWrite(t parser.CommandType) bool {
+	switch t {
+	case parser.CmdInsert, parser.CmdUpdate, parser.CmdDelete, parser.CmdAlterTable,
+		parser.CmdDrop, parser.CmdTruncate, parser.CmdCreate, parser.CmdCreateView,
+		parser.CmdCreateTrigger, parser.CmdIndex, "CREATE_FTS":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package executor
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestSyncFailStillCommitted: SINKRON ... GAGAL tanpa ANAK mulihkeun
+// ErrNotReplicated, tapi datana tetep komit (teu dibatalkeun).
+func TestSyncFailStillCommitted(t *testing.T) {
+	run(t, "DAMEL sinkron_gagal id:INT:PK", "ATUR SINKRON 1 TUNGGU 0.05 GAGAL")
+	defer run(t, "ATUR SINKRON 0")
+
+	steps := []struct {
+		query string
+		want  error
+	}{
+		{"SIMPEN sinkron_gagal 1", ErrNotReplicated},
+		{"MIMITIAN", nil},
+		{"SIMPEN sinkron_gagal 2", nil},
+		{"JADIKEUN", ErrNotReplicated},
+	}
+	for _, s := range steps {
+		res, err := exec(s.query)
+		if s.want == nil && err != nil || s.want != nil && !errors.Is(err, s.want) {
+			t.Fatalf("%s: err = %v, want %v", s.query, err, s.want)
+		}
+		if res == nil {
+			t.Errorf("%s: hasil kedah tetep dipulangkeun", s.query)
+		}
+	}
+
+	got := rowsOf(run(t, "TINGALI * TI sinkron_gagal"))
+	if want := []string{"1", "2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("baris = %v, want %v", got, want)
+	}
+}
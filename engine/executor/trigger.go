@@ -0,0 +1,302 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/febrd/maungdb/engine/auth"
+	"github.com/febrd/maungdb/engine/parser"
+	"github.com/febrd/maungdb/engine/schema"
+	"github.com/febrd/maungdb/engine/transaction"
+	"github.com/febrd/maungdb/engine/trigger"
+)
+
+// maxTriggerDepth: wates jarambah nu micu jarambah séjén (mis. jarambah
+// SIMPEN nu nyimpen deui ka tabel nu sami).
+const maxTriggerDepth = 16
+
+// rowEvent: hiji baris nu robah ku paréntah, kanggo jarambah tingkat baris.
+// newRow nil kanggo MICEUN, oldRow nil kanggo SIMPEN.
+type rowEvent struct {
+	table  string
+	event  string
+	newRow []string
+	oldRow []string
+}
+
+// planEvents ngarobih operasi rencana (kalebet akibat CASCADE) jadi rowEvent.
+func planEvents(ops []planOp) []rowEvent {
+	events := make([]rowEvent, 0, len(ops))
+	for _, op := range ops {
+		e := rowEvent{table: op.Table, event: string(op.Type)}
+		if op.Type != transaction.OpDelete {
+			e.newRow = strings.Split(op.Data, "|")
+		}
+		if op.Type != transaction.OpInsert {
+			e.oldRow = strings.Split(op.Prev, "|")
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+// eventVerb: ngaran paréntah MaungQL hiji event (kanggo pesen).
+func eventVerb(event string) string {
+	switch event {
+	case string(transaction.OpInsert):
+		return "SIMPEN"
+	case string(transaction.OpUpdate):
+		return "OMEAN"
+	case string(transaction.OpDelete):
+		return "MICEUN"
+	}
+	return event
+}
+
+// timingName: ngaran MaungQL waktos jarambah.
+func timingName(timing string) string {
+	if timing == trigger.TimingBefore {
+		return "SAMEMEH"
+	}
+	return "SANGGEUS"
+}
+
+// triggerSet: jarambah sareng schema tabel nu dimuat sakali per paréntah.
+// depth nyaéta jero jarambah paréntah éta (parser.Command.TriggerDepth).
+type triggerSet struct {
+	db       string
+	depth    int
+	triggers map[string][]trigger.TriggerAction
+	defs     map[string]*schema.Definition
+}
+
+func newTriggerSet(db string, depth int) *triggerSet {
+	return &triggerSet{db: db, depth: depth, triggers: make(map[string][]trigger.TriggerAction), defs: make(map[string]*schema.Definition)}
+}
+
+func (ts *triggerSet) get(table, event string) []trigger.TriggerAction {
+	key := table + "|" + event
+	list, ok := ts.triggers[key]
+	if !ok {
+		list, _ = trigger.GlobalTriggerManager.GetTriggers(ts.db, table, event)
+		ts.triggers[key] = list
+	}
+	return list
+}
+
+func (ts *triggerSet) def(table string) (*schema.Definition, error) {
+	if d, ok := ts.defs[table]; ok {
+		return d, nil
+	}
+	d, err := schema.Load(ts.db, table)
+	if err != nil {
+		return nil, err
+	}
+	ts.defs[table] = d
+	return d, nil
+}
+
+// any: leres mun aya jarambah kanggo salah sahiji event.
+func (ts *triggerSet) any(events []rowEvent) bool {
+	for _, e := range events {
+		if len(ts.get(e.table, e.event)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// triggerError: kagagalan jarambah (parantos nyebatkeun ngaran jarambahna).
+type triggerError struct{ error }
+
+func (e *triggerError) Unwrap() error { return e.error }
+
+// fire ngajalankeun jarambah timing kanggo unggal baris, sacara sinkron
+// sareng saurutan. Error munggaran ngeureunkeun sadayana.
+func (ts *triggerSet) fire(timing string, events []rowEvent) error {
+	for _, e := range events {
+		for _, t := range ts.get(e.table, e.event) {
+			if t.When() != timing {
+				continue
+			}
+			err := ts.run(t, e)
+			var nested *triggerError
+			switch {
+			case err == nil:
+				continue
+			case errors.As(err, &nested):
+				// Jarambah nu dipicu ku jarambah: pesen nu pangjerona wungkul.
+				return err
+			case timing == trigger.TimingBefore:
+				return &triggerError{fmt.Errorf("⛔ jarambah '%s' nolak %s di '%s': %v", t.Name, eventVerb(e.event), e.table, err)}
+			}
+			return &triggerError{fmt.Errorf("⛔ jarambah '%s' (SANGGEUS %s di '%s') gagal, paréntah dibatalkeun: %v", t.Name, eventVerb(e.event), e.table, err)}
+		}
+	}
+	return nil
+}
+
+// run ngeusian ANYAR/HEUBEUL kana aksi jarambah teras ngajalankeunana
+// dina transaksi paréntah nu micuna.
+func (ts *triggerSet) run(t trigger.TriggerAction, e rowEvent) error {
+	if ts.depth >= maxTriggerDepth {
+		return fmt.Errorf("jarambah nyambung leuwih ti %d tingkat", maxTriggerDepth)
+	}
+
+	d, err := ts.def(e.table)
+	if err != nil {
+		return err
+	}
+	query, err := bindRowRefs(t.ActionQL, e, d)
+	if err != nil {
+		return err
+	}
+	cmd, err := parser.Parse(query)
+	if err != nil {
+		return err
+	}
+	if cmd.Type == parser.CmdTransaction {
+		return errors.New("aksi jarambah teu kénging MIMITIAN/JADIKEUN/BATALKEUN")
+	}
+	cmd.TriggerDepth = ts.depth + 1
+	_, err = Execute(cmd)
+	return err
+}
+
+// rowRefNames: awalan rujukan baris dina aksi jarambah.
+var rowRefNames = []string{"ANYAR", "NEW", "HEUBEUL", "OLD"}
+
+var reRowRef = regexp.MustCompile(`(?i)\b(ANYAR|NEW|HEUBEUL|OLD)\.(\w+)`)
+
+// bindRowRefs ngaganti ANYAR.kolom / HEUBEUL.kolom (NEW/OLD) ku nilai baris.
+// Di jero tanda petik nilaina dilebetkeun kitu waé; di luar petik NULL jadi
+// NULL sareng téks kosong jadi ''.
+func bindRowRefs(query string, e rowEvent, d *schema.Definition) (string, error) {
+	var bindErr error
+	var b strings.Builder
+	var quote rune
+	last := 0
+	for _, loc := range reRowRef.FindAllStringSubmatchIndex(query, -1) {
+		for _, r := range query[last:loc[0]] {
+			switch {
+			case quote != 0 && r == quote:
+				quote = 0
+			case quote == 0 && (r == '\'' || r == '"'):
+				quote = r
+			}
+		}
+		b.WriteString(query[last:loc[0]])
+		last = loc[1]
+
+		ref, col := strings.ToUpper(query[loc[2]:loc[3]]), query[loc[4]:loc[5]]
+		row, name := e.newRow, "ANYAR"
+		if ref == "HEUBEUL" || ref == "OLD" {
+			row, name = e.oldRow, "HEUBEUL"
+		}
+		if row == nil {
+			bindErr = fmt.Errorf("%s teu aya dina jarambah %s", name, eventVerb(e.event))
+			break
+		}
+		idx := d.GetColumnIndex(col)
+		if idx == -1 || idx >= len(row) {
+			bindErr = fmt.Errorf("kolom '%s' teu aya di tabel '%s' (%s.%s)", col, e.table, name, col)
+			break
+		}
+
+		val := row[idx]
+		switch {
+		case quote == 0:
+			val = literalValue(val)
+		case schema.IsNull(val):
+			val = ""
+		}
+		b.WriteString(val)
+	}
+	if bindErr != nil {
+		return "", bindErr
+	}
+	b.WriteString(query[last:])
+	return b.String(), nil
+}
+
+// withTriggers ngajalankeun apply (nyerat parobahan paréntah) diapit ku
+// jarambah SAMEMEH sareng SANGGEUS kanggo unggal baris dina events, sadayana
+// dina hiji transaksi: di luar MIMITIAN dibuka transaksi implisit, di jero
+// MIMITIAN dianggo titik simpen. Mun jarambah gagal, parobahan paréntah ieu
+// (kalebet parobahan ku jarambahna) dibatalkeun. Tanpa jarambah, apply
+// dijalankeun langsung. depth nyaéta TriggerDepth paréntah nu micuna.
+func withTriggers(user *auth.User, depth int, events []rowEvent, apply func() error) error {
+	ts := newTriggerSet(user.Database, depth)
+	if !ts.any(events) {
+		return apply()
+	}
+
+	tm := transaction.GetManager()
+	inTx := tm.IsActive(user.Username)
+	mark := 0
+	if inTx {
+		mark = tm.Savepoint(user.Username)
+	} else if _, err := tm.Begin(user.Username); err != nil {
+		return err
+	}
+	abort := func(err error) error {
+		if inTx {
+			tm.RollbackTo(user.Username, mark)
+		} else {
+			tm.Rollback(user.Username)
+		}
+		return err
+	}
+
+	if err := ts.fire(trigger.TimingBefore, events); err != nil {
+		return abort(err)
+	}
+	if err := apply(); err != nil {
+		return abort(err)
+	}
+	if err := ts.fire(trigger.TimingAfter, events); err != nil {
+		return abort(err)
+	}
+	if inTx {
+		return nil
+	}
+
+	pending := tm.Pending(user.Username)
+	if err := tm.Commit(user.Username); err != nil {
+		return abort(err)
+	}
+	refreshRowIndexes(walOps(pending), ts.def)
+	return nil
+}
+
+// commitWithTriggers nerapkeun rencana OMEAN/MICEUN/SIMPEN ... MUN AYA
+// sareng jarambah unggal barisna. inTx leres mun parobahanana ngantosan
+// JADIKEUN.
+func commitWithTriggers(user *auth.User, depth int, plan *writePlan) (inTx bool, err error) {
+	inTx = transaction.GetManager().IsActive(user.Username)
+	err = withTriggers(user, depth, planEvents(plan.ops), func() error {
+		if _, err := plan.commit(user.Username); err != nil {
+			return fmt.Errorf("gagal nyimpen parobahan: %v", err)
+		}
+		return nil
+	})
+	return inTx, err
+}
+
+// execReject: TOLAK '<pesen>' [LAMUN <kaayaan>]. Kaayaanana dievaluasi
+// saatos ANYAR/HEUBEUL dieusi nilai, janten dua sisina mangrupikeun nilai.
+func execReject(cmd *parser.Command) (*ExecutionResult, error) {
+	reject := combineTruth(cmd.Where, func(c parser.Condition) truth {
+		field := strings.TrimSpace(c.Field)
+		if strings.EqualFold(field, "NULL") {
+			field = schema.NullValue
+		}
+		return matchTruth(strings.Trim(field, "'\""), c, schema.Column{})
+	}) == truthTrue
+	if reject {
+		return nil, errors.New(cmd.Arg1)
+	}
+	return &ExecutionResult{Message: "✅ Kaayaan TOLAK teu kacumponan"}, nil
+}
@@ -0,0 +1,89 @@
+package executor
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// triggerChain nyieun tabel <prefix>0..<prefix>n; jarambah dina unggal tabel
+// nyimpen barisna ka tabel salajengna, janten SIMPEN ka <prefix>0 micu n
+// jarambah nu nyambung.
+func triggerChain(t *testing.T, prefix string, n int) {
+	t.Helper()
+	for i := 0; i <= n; i++ {
+		run(t, fmt.Sprintf("DAMEL %s%d id:INT:PK", prefix, i))
+	}
+	for i := 0; i < n; i++ {
+		run(t, fmt.Sprintf("DAMEL JARAMBAH %s_j%d WAKTU SANGGEUS SIMPEN PADA %s%d LAKUKAN SIMPEN %s%d ANYAR.id",
+			prefix, i, prefix, i, prefix, i+1))
+	}
+}
+
+func TestTriggerDepth(t *testing.T) {
+	cases := []struct {
+		prefix string
+		depth  int
+		ok     bool
+	}{
+		{"ranté_a", 1, true},
+		{"ranté_b", maxTriggerDepth, true},
+		{"ranté_c", maxTriggerDepth + 1, false},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%d tingkat", c.depth), func(t *testing.T) {
+			triggerChain(t, c.prefix, c.depth)
+			_, err := exec(fmt.Sprintf("SIMPEN %s0 1", c.prefix))
+			if c.ok {
+				if err != nil {
+					t.Fatalf("SIMPEN: %v", err)
+				}
+				if got := rowsOf(run(t, fmt.Sprintf("TINGALI * TI %s%d", c.prefix, c.depth))); len(got) != 1 {
+					t.Errorf("tabel pamungkas = %v, want 1 baris", got)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), "tingkat") {
+				t.Fatalf("err = %v, want wates jero jarambah", err)
+			}
+			if got := rowsOf(run(t, fmt.Sprintf("TINGALI * TI %s0", c.prefix))); len(got) != 0 {
+				t.Errorf("SIMPEN kedah dibatalkeun, kapendak %v", got)
+			}
+		})
+	}
+
+	// Jero jarambah ngiring paréntahna, teu nyésa saatos gagal.
+	run(t, "SIMPEN ranté_b0 2")
+}
+
+// TestAfterTriggerSeesPendingRow: aksi jarambah SANGGEUS ningali baris nu
+// micu éta, sanajan barisna can ditulis ka disk.
+func TestAfterTriggerSeesPendingRow(t *testing.T) {
+	cases := []struct {
+		table, action string
+		want          []string
+	}{
+		{"jp_omean", "OMEAN jp_omean JADI umur = 99 DIMANA id = ANYAR.id", []string{"5|cecep|99"}},
+		{"jp_miceun", "MICEUN TI jp_miceun DIMANA id = ANYAR.id", []string{}},
+	}
+	for _, c := range cases {
+		t.Run(c.table, func(t *testing.T) {
+			run(t,
+				fmt.Sprintf("DAMEL %s id:INT:PK, nama:STRING, umur:INT", c.table),
+				fmt.Sprintf("DAMEL JARAMBAH %s_j WAKTU SANGGEUS SIMPEN PADA %s LAKUKAN %s", c.table, c.table, c.action),
+				fmt.Sprintf("SIMPEN %s 5|cecep|1", c.table))
+			if got := rowsOf(run(t, "TINGALI * TI "+c.table)); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("rows = %v, want %v", got, c.want)
+			}
+		})
+	}
+
+	// Dina transaksi, parobahan jarambah ngiring dijadikeun atanapi
+	// dibatalkeun sareng paréntah nu micuna.
+	run(t, "MIMITIAN", "SIMPEN jp_omean 6|dadang|1", "BATALKEUN")
+	run(t, "MIMITIAN", "SIMPEN jp_omean 7|euis|1", "JADIKEUN")
+	if got, want := sorted(rowsOf(run(t, "TINGALI * TI jp_omean"))), []string{"5|cecep|99", "7|euis|99"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("saatos transaksi = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,86 @@
+package executor
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestUpsertReturning: SIMPEN ... MUN AYA OMEAN/ANTEPKEUN sareng BALIKKEUN
+// ngabalikeun baris nu kapangaruhan.
+func TestUpsertReturning(t *testing.T) {
+	run(t, "DAMEL stok id:INT:PK, barang:STRING, jumlah:INT")
+
+	cases := []struct {
+		query string
+		want  []string
+		table []string
+	}{
+		{
+			"SIMPEN stok (id, barang, jumlah) NILAI (1, 'béas', 10), (2, 'gula', 5) BALIKKEUN id, jumlah",
+			[]string{"1|10", "2|5"},
+			[]string{"1|béas|10", "2|gula|5"},
+		},
+		{
+			"SIMPEN stok NILAI (1, 'béas', 7), (3, 'uyah', 2) MUN AYA (id) OMEAN jumlah=ANYAR.jumlah BALIKKEUN *",
+			[]string{"1|béas|7", "3|uyah|2"},
+			[]string{"1|béas|7", "2|gula|5", "3|uyah|2"},
+		},
+		{
+			"SIMPEN stok NILAI (2, 'gula', 99), (4, 'kopi', 1) MUN AYA ANTEPKEUN RETURNING barang",
+			[]string{"kopi"},
+			[]string{"1|béas|7", "2|gula|5", "3|uyah|2", "4|kopi|1"},
+		},
+		{
+			"INSERT stok VALUES (4, 'kopi', 0) ON CONFLICT (id) DO UPDATE SET jumlah=3, barang=EXCLUDED.barang",
+			nil,
+			[]string{"1|béas|7", "2|gula|5", "3|uyah|2", "4|kopi|3"},
+		},
+		{
+			"OMEAN stok JADI jumlah=0 DIMANA id = 3 BALIKKEUN id, jumlah",
+			[]string{"3|0"},
+			[]string{"1|béas|7", "2|gula|5", "3|uyah|0", "4|kopi|3"},
+		},
+		{
+			"MICEUN TI stok DIMANA jumlah < 5 BALIKKEUN barang",
+			[]string{"kopi", "uyah"},
+			[]string{"1|béas|7", "2|gula|5"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			res := run(t, c.query)
+			if c.want != nil {
+				if got := sorted(rowsOf(res)); !reflect.DeepEqual(got, c.want) {
+					t.Errorf("BALIKKEUN = %v, want %v", got, c.want)
+				}
+			}
+			if got := sorted(rowsOf(run(t, "TINGALI * TI stok"))); !reflect.DeepEqual(got, c.table) {
+				t.Errorf("tabel = %v, want %v", got, c.table)
+			}
+		})
+	}
+}
+
+func TestUpsertErrors(t *testing.T) {
+	run(t, "DAMEL stok_gagal id:INT:PK, jumlah:INT")
+
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{"SIMPEN stok_gagal NILAI (1, 2) BALIKKEUN euweuh", "kolom BALIKKEUN 'euweuh' teu aya"},
+		{"SIMPEN stok_gagal NILAI (1, 2) MUN AYA OMEAN jumlah", "format MUN AYA OMEAN salah"},
+		{"SIMPEN stok_gagal NILAI (1, 2) MUN AYA (id OMEAN jumlah=1", "teu ditutup"},
+		{"SIMPEN stok_gagal NILAI (1, 2) MUN AYA OMEAN euweuh=1", "kolom 'euweuh' teu aya"},
+	}
+	run(t, "SIMPEN stok_gagal NILAI (1, 1)")
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			_, err := exec(c.query)
+			if err == nil || !strings.Contains(err.Error(), c.want) {
+				t.Fatalf("err = %v, want %q", err, c.want)
+			}
+		})
+	}
+}
@@ -1,16 +1,54 @@
 package executor
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/febrd/maungdb/engine/auth"
+	"github.com/febrd/maungdb/engine/parser"
+	"github.com/febrd/maungdb/engine/replication"
 	"github.com/febrd/maungdb/engine/schema"
 	"github.com/febrd/maungdb/engine/storage"
 )
 
 
 func ValidateConstraints(d *schema.Definition, tableName string, rowData string) error {
+	return validateConstraints(d, tableName, rowData, nil)
+}
+
+// uniqueKeys nyimpen nilai PK/UNIQUE hiji tabel (dumasar kolasi) pikeun
+// SIMPEN sababaraha baris: tabel dibaca sakali, sareng baris-baris dina hiji
+// paréntah ogé teu kénging kembar.
+type uniqueKeys struct {
+	seen map[int]map[string]bool
+}
+
+func newUniqueKeys(d *schema.Definition, tableName string) *uniqueKeys {
+	keys := &uniqueKeys{seen: make(map[int]map[string]bool)}
+	for i, col := range d.Columns {
+		if col.IsPrimary || col.IsUnique {
+			keys.seen[i] = make(map[string]bool)
+		}
+	}
+
+	rows, _ := storage.ReadAll(tableName)
+	for _, row := range rows {
+		if row == "" {
+			continue
+		}
+		cols := strings.Split(row, "|")
+		for i, set := range keys.seen {
+			if i < len(cols) {
+				set[schema.CollationKey(d.Columns[i].Collation, strings.TrimSpace(cols[i]))] = true
+			}
+		}
+	}
+	return keys
+}
+
+func validateConstraints(d *schema.Definition, tableName string, rowData string, keys *uniqueKeys) error {
 	newCols := strings.Split(rowData, "|")
 
 	if len(newCols) != len(d.Columns) {
@@ -21,15 +59,23 @@ func ValidateConstraints(d *schema.Definition, tableName string, rowData string)
 
 	for i, col := range d.Columns {
 		val := strings.TrimSpace(newCols[i]) 
+		isNull := schema.IsNullFor(col.Type, val)
 		if col.IsNotNull {
-			if val == "" || strings.ToUpper(val) == "NULL" {
+			if isNull {
 				return fmt.Errorf("kolom '%s' teu kenging kosong (NOT NULL)", col.Name)
 			}
 		}
 
 		if col.IsPrimary || col.IsUnique {
-			if val != "" {
-				isDup, err := checkDuplicate(tableName, i, val)
+			if !isNull {
+				isDup, err := false, error(nil)
+				if keys != nil {
+					key := schema.CollationKey(col.Collation, val)
+					isDup = keys.seen[i][key]
+					keys.seen[i][key] = true
+				} else {
+					isDup, err = checkDuplicate(tableName, i, val, col.Collation)
+				}
 				if err != nil {
 					return fmt.Errorf("gagal cek duplikasi: %v", err)
 				}
@@ -43,7 +89,7 @@ func ValidateConstraints(d *schema.Definition, tableName string, rowData string)
 			}
 		}
 
-		if col.ForeignKey != "" && val != "" && strings.ToUpper(val) != "NULL" {
+		if col.ForeignKey != "" && !isNull {
 			parts := strings.Split(col.ForeignKey, ".")
 			if len(parts) != 2 {
 				return fmt.Errorf("definisi FK salah di kolom %s (format kedah: tabel.kolom)", col.Name)
@@ -61,10 +107,156 @@ func ValidateConstraints(d *schema.Definition, tableName string, rowData string)
 		}
 	}
 
+	return validateChecks(d, newCols)
+}
+
+// validateChecks ngevaluasi konstrain CHECK. Hasil TEU PASTI (aya NULL)
+// dianggap lulus, sapertos SQL; ngan hasil SALAH nu ditolak.
+func validateChecks(d *schema.Definition, values []string) error {
+	for _, col := range d.Columns {
+		if col.Check == "" {
+			continue
+		}
+
+		conds, err := parser.ParseConditionExpr(col.Check)
+		if err != nil {
+			return fmt.Errorf("CHECK kolom '%s' ruksak: %v", col.Name, err)
+		}
+		if conditionsTruth(values, d.Columns, conds) == truthFalse {
+			return fmt.Errorf("pelanggaran CHECK di kolom '%s': %s", col.Name, col.Check)
+		}
+	}
 	return nil
 }
 
-func checkDuplicate(tableName string, colIndex int, value string) (bool, error) {
+// completeRow ngalengkepan baris SIMPEN: kolom AUTO nu dileungitkeun, kosong
+// atanapi NULL dieusi ku sequence, kolom kosong nu gaduh DEFAULT dieusi nilai
+// baku, sésana ditarjamahkeun (schema.InputValue) sareng dinormalisasi.
+func completeRow(db, table string, d *schema.Definition, data string) (string, error) {
+	values := strings.Split(data, "|")
+
+	var autoCols []int
+	for i, col := range d.Columns {
+		if col.AutoIncrement {
+			autoCols = append(autoCols, i)
+		}
+	}
+
+	if len(values) != len(d.Columns) && len(autoCols) > 0 && len(values) == len(d.Columns)-len(autoCols) {
+		full := make([]string, 0, len(d.Columns))
+		next := 0
+		for _, col := range d.Columns {
+			if col.AutoIncrement {
+				full = append(full, "")
+				continue
+			}
+			full = append(full, values[next])
+			next++
+		}
+		values = full
+	}
+
+	if len(values) != len(d.Columns) {
+		return data, nil
+	}
+
+	for i, col := range d.Columns {
+		val := strings.TrimSpace(values[i])
+		if col.AutoIncrement && strings.EqualFold(val, "NULL") {
+			val = ""
+		}
+		if val != "" {
+			values[i] = schema.NormalizeValue(col, schema.InputValue(col, values[i]))
+			if col.AutoIncrement {
+				if n, err := strconv.ParseInt(val, 10, 64); err == nil {
+					if err := storage.BumpSequence(db, table, i, n); err != nil {
+						return "", fmt.Errorf("gagal ngomean sequence: %v", err)
+					}
+				}
+			}
+			continue
+		}
+
+		switch {
+		case col.AutoIncrement:
+			n, err := storage.NextSequence(db, table, i)
+			if err != nil {
+				return "", fmt.Errorf("gagal nyandak sequence: %v", err)
+			}
+			values[i] = strconv.FormatInt(n, 10)
+		case col.HasDefault:
+			val, err := defaultValue(col, col.Default)
+			if err != nil {
+				return "", err
+			}
+			values[i] = val
+		default:
+			values[i] = schema.InputValue(col, values[i])
+		}
+	}
+
+	return strings.Join(values, "|"), nil
+}
+
+// defaultValue mariksa tipe nilai DEFAULT / BAKU raw pikeun col sareng
+// mulihkeun bentuk bakuna (schema.NormalizeValue), mis. 0 jadi 0.00 dina
+// DECIMAL(10,2).
+func defaultValue(col schema.Column, raw string) (string, error) {
+	if strings.Contains(raw, "|") {
+		return "", fmt.Errorf("DEFAULT kolom '%s' teu kenging ngandung '|'", col.Name)
+	}
+	val := schema.InputValue(col, raw)
+	probe := &schema.Definition{Columns: []schema.Column{col}}
+	if err := probe.ValidateRow(val); err != nil {
+		return "", fmt.Errorf("DEFAULT teu valid: %v", err)
+	}
+	return schema.NormalizeValue(col, val), nil
+}
+
+// validateColumnDefinitions mariksa konstrain anyar samemeh schema ditulis.
+// DEFAULT nu valid diganti ku bentuk bakuna dina cols.
+func validateColumnDefinitions(cols []schema.Column) error {
+	autoCount := 0
+	for _, col := range cols {
+		if col.AutoIncrement {
+			autoCount++
+		}
+	}
+	if autoCount > 1 {
+		return errors.New("ngan kenging aya hiji kolom AUTO dina hiji tabel")
+	}
+
+	for i, col := range cols {
+		if !schema.IsValidType(col.Type) {
+			return errors.New("tipe data teu didukung: " + col.Type)
+		}
+		if col.AutoIncrement && col.Type != "INT" {
+			return fmt.Errorf("kolom AUTO '%s' kedah INT", col.Name)
+		}
+		if col.HasDefault {
+			val, err := defaultValue(col, col.Default)
+			if err != nil {
+				return err
+			}
+			if !schema.IsNull(val) && val != "" {
+				cols[i].Default = val
+			}
+		}
+		if col.Check != "" {
+			if strings.Contains(col.Check, "|") {
+				return fmt.Errorf("CHECK kolom '%s' teu kenging ngandung '|'", col.Name)
+			}
+			if _, err := parser.ParseConditionExpr(col.Check); err != nil {
+				return fmt.Errorf("CHECK kolom '%s' teu valid: %v", col.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// checkDuplicate milarian value dina kolom colIndex. Téks dibandingkeun
+// nuturkeun kolasi (kosong = BINARY).
+func checkDuplicate(tableName string, colIndex int, value, collation string) (bool, error) {
 	rows, err := storage.ReadAll(tableName)
 	if err != nil {
 		return false, nil 
@@ -75,7 +267,7 @@ func checkDuplicate(tableName string, colIndex int, value string) (bool, error)
 		cols := strings.Split(row, "|")
 
 		if colIndex < len(cols) {
-			if strings.TrimSpace(cols[colIndex]) == value {
+			if schema.CollationKey(collation, strings.TrimSpace(cols[colIndex])) == schema.CollationKey(collation, value) {
 				return true, nil 
 			}
 		}
@@ -102,10 +294,55 @@ func checkForeignKeyExists(dbName string, targetTable string, targetColName stri
 	if targetIndex == -1 {
 		return false, fmt.Errorf("kolom '%s' teu aya di tabel induk '%s' (pastikeun ejaan leres)", targetColName, targetTable)
 	}
-	found, err := checkDuplicate(targetTable, targetIndex, value)
+	rows, err := readTableRows(targetTable)
 	if err != nil {
 		return false, err
 	}
+	key := fkKey(targetDef.Columns[targetIndex], value)
+	for _, row := range rows {
+		if targetIndex < len(row) && fkKey(targetDef.Columns[targetIndex], row[targetIndex]) == key {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ImportCSV ngimpor file CSV ka tabel. Unggal baris dilengkepan,
+// dinormalisasi sareng divalidasi heula; baris nu teu valid dilewat.
+func ImportCSV(table, filePath string) (int, error) {
+	if err := replication.GlobalReplication.CanWrite(); err != nil {
+		return 0, err
+	}
+	user, err := auth.CurrentUser()
+	if err != nil {
+		return 0, err
+	}
+	d, err := schema.Load(user.Database, table)
+	if err != nil {
+		return 0, fmt.Errorf("tabel '%s' teu kapanggih", table)
+	}
 
-	return found, nil
-}
\ No newline at end of file
+	n, err := storage.ImportCSV(table, filePath, func(record []string) (string, error) {
+		for _, v := range record {
+			if strings.ContainsAny(v, "|\n") {
+				return "", errors.New("nilai teu kenging ngandung '|' atanapi baris anyar")
+			}
+		}
+		row, err := completeRow(user.Database, table, d, strings.Join(record, "|"))
+		if err != nil {
+			return "", err
+		}
+		if err := d.ValidateRow(row); err != nil {
+			return "", err
+		}
+		if err := ValidateConstraints(d, table, row); err != nil {
+			return "", err
+		}
+		return row, nil
+	})
+	if err != nil || n == 0 {
+		return n, err
+	}
+	// Baris impor langsung kapendak ku TANDAIN sareng KOREHAN.
+	return n, rebuildTableIndexes(table, d)
+}
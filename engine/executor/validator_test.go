@@ -0,0 +1,65 @@
+package executor
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/febrd/maungdb/engine/schema"
+)
+
+func TestDefaultValue(t *testing.T) {
+	decimal := schema.Column{Name: "harga", Type: "DECIMAL", Args: []string{"10", "2"}}
+	tests := []struct {
+		name    string
+		col     schema.Column
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{"DECIMAL dieusi skala", decimal, "0", "0.00", false},
+		{"DECIMAL langkung ti skala", decimal, "1.234", "", true},
+		{"INT teu valid", schema.Column{Name: "n", Type: "INT"}, "abc", "", true},
+		{"NULL", decimal, "NULL", schema.NullValue, false},
+		{"UUID hurup leutik", schema.Column{Name: "u", Type: "UUID"}, "A0EEBC99-9C0B-4EF8-BB6D-6BB9BD380A11", "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", false},
+		{"ngandung |", schema.Column{Name: "s", Type: "STRING"}, "a|b", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := defaultValue(tt.col, tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecimalDefaultStored(t *testing.T) {
+	run(t,
+		"DAMEL dompet id:INT:PK, saldo:DECIMAL(10,2):DEFAULT(0)",
+		"SIMPEN dompet 1|",
+		"ROBIH TABEL dompet TAMBAH KOLOM bonus:DECIMAL(10,2) BAKU 5",
+		"ROBIH TABEL dompet TAMBAH KOLOM denda:DECIMAL(10,2):DEFAULT(1.5)",
+		"SIMPEN dompet 2|||",
+	)
+	if got := mustSchema(t, "dompet").Columns[1].Default; got != "0.00" {
+		t.Errorf("DEFAULT disimpen %q, kedahna 0.00", got)
+	}
+	got := rowsOf(run(t, "TINGALI * TI dompet"))
+	want := []string{"1|0.00|5.00|1.50", `2|0.00|\N|1.50`}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	for _, q := range []string{
+		"DAMEL dompet2 id:INT:PK, saldo:DECIMAL(10,2):DEFAULT(abc)",
+		"ROBIH TABEL dompet TAMBAH KOLOM pajak:INT BAKU abc",
+	} {
+		if _, err := exec(q); err == nil || !strings.Contains(err.Error(), "DEFAULT teu valid") {
+			t.Errorf("%s: DEFAULT teu valid teu ditolak: %v", q, err)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package fts
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Analyzer ngarobih téks jadi runtuyan token. Analyzer nu sami dipaké
+// nalika ngawangun indeks sareng nalika ngolah query KOREHAN.
+type Analyzer interface {
+	// Name: spésifikasi nu disimpen dina indeks (mis. "indonesia", "ngram:3").
+	Name() string
+	Analyze(text string) []string
+}
+
+// DefaultAnalyzer dipaké mun DAMEL INDEKS_TEKS teu nyebutkeun NGANGGO,
+// sareng kanggo indeks heubeul nu teu nyimpen analyzer.
+const DefaultAnalyzer = "standar"
+
+const defaultGram = 3
+
+// Analyzers: daptar analyzer nu dirojong (kanggo pesen kasalahan).
+var Analyzers = []string{"standar", "basajan", "kecap", "indonesia", "sunda", "ngram"}
+
+var analyzerAliases = map[string]string{
+	"standard": "standar", "simple": "basajan", "keyword": "kecap",
+	"indonesian": "indonesia", "sundanese": "sunda",
+}
+
+// NewAnalyzer nyiptakeun analyzer tina spésifikasina: ngaran (atanapi
+// alias basa Inggrisna), "ngram:<n>" kanggo n-gram. Kosong = DefaultAnalyzer.
+func NewAnalyzer(spec string) (Analyzer, error) {
+	name, arg, _ := strings.Cut(strings.ToLower(strings.TrimSpace(spec)), ":")
+	if alias, ok := analyzerAliases[name]; ok {
+		name = alias
+	}
+	if name != "ngram" && arg != "" {
+		return nil, fmt.Errorf("analyzer '%s' teu nampi paraméter", name)
+	}
+
+	switch name {
+	case "", "standar":
+		return standardAnalyzer{}, nil
+	case "basajan":
+		return simpleAnalyzer{}, nil
+	case "kecap":
+		return keywordAnalyzer{}, nil
+	case "indonesia":
+		return indonesianAnalyzer{}, nil
+	case "sunda":
+		return sundaneseAnalyzer{}, nil
+	case "ngram":
+		n := defaultGram
+		if arg != "" {
+			v, err := strconv.Atoi(arg)
+			if err != nil || v < 1 || v > 10 {
+				return nil, fmt.Errorf("ukuran n-gram kedah 1 dugi ka 10, sanes '%s'", arg)
+			}
+			n = v
+		}
+		return ngramAnalyzer{n: n}, nil
+	}
+	return nil, fmt.Errorf("analyzer '%s' teu dikenal (pilihan: %s)", spec, strings.Join(Analyzers, ", "))
+}
+
+// words: hurup leutik, dipeulah dina sadaya karakter sanés hurup/angka.
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsNumber(c)
+	})
+}
+
+// standardAnalyzer: paripolah asli KOREHAN, kecap ≤ 2 hurup dipiceun.
+type standardAnalyzer struct{}
+
+func (standardAnalyzer) Name() string { return "standar" }
+
+func (standardAnalyzer) Analyze(text string) []string {
+	var result []string
+	for _, w := range words(text) {
+		if len(w) > 2 {
+			result = append(result, w)
+		}
+	}
+	return result
+}
+
+// simpleAnalyzer: sadaya kecap, kalebet nu pondok.
+type simpleAnalyzer struct{}
+
+func (simpleAnalyzer) Name() string { return "basajan" }
+
+func (simpleAnalyzer) Analyze(text string) []string { return words(text) }
+
+// keywordAnalyzer: sakabéh nilai jadi hiji token (cocog kanggo kode, ngaran).
+type keywordAnalyzer struct{}
+
+func (keywordAnalyzer) Name() string { return "kecap" }
+
+func (keywordAnalyzer) Analyze(text string) []string {
+	text = strings.Join(strings.Fields(strings.ToLower(text)), " ")
+	if text == "" {
+		return nil
+	}
+	return []string{text}
+}
+
+// ngramAnalyzer: unggal kecap dipeulah jadi n-gram karakter, sangkan
+// bagian kecap ogé tiasa kapendak. Kecap nu langkung pondok ti n tetep utuh.
+type ngramAnalyzer struct{ n int }
+
+func (a ngramAnalyzer) Name() string { return "ngram:" + strconv.Itoa(a.n) }
+
+func (a ngramAnalyzer) Analyze(text string) []string {
+	var result []string
+	for _, w := range words(text) {
+		r := []rune(w)
+		if len(r) <= a.n {
+			result = append(result, w)
+			continue
+		}
+		for i := 0; i+a.n <= len(r); i++ {
+			result = append(result, string(r[i:i+a.n]))
+		}
+	}
+	return result
+}
+
+// sundaneseAnalyzer: sadaya kecap tanpa stopword basa Sunda.
+type sundaneseAnalyzer struct{}
+
+func (sundaneseAnalyzer) Name() string { return "sunda" }
+
+func (sundaneseAnalyzer) Analyze(text string) []string {
+	var result []string
+	for _, w := range words(text) {
+		if !sundaneseStopwords[w] {
+			result = append(result, w)
+		}
+	}
+	return result
+}
+
+// indonesianAnalyzer: stopword basa Indonésia dipiceun, sésana di-stem.
+type indonesianAnalyzer struct{}
+
+func (indonesianAnalyzer) Name() string { return "indonesia" }
+
+func (indonesianAnalyzer) Analyze(text string) []string {
+	var result []string
+	for _, w := range words(text) {
+		if !indonesianStopwords[w] {
+			result = append(result, stemIndonesian(w))
+		}
+	}
+	return result
+}
+
+func wordSet(list string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.Fields(list) {
+		set[w] = true
+	}
+	return set
+}
+
+var sundaneseStopwords = wordSet(`
+	abdi anjeun anu atanapi atawa atuh aya bae baé bari deui di dina eta éta
+	geus henteu ieu jeung ka kana kanggo keur kitu kieu ku kuring lain lamun
+	mah manéhna manehna maranéhna maranehna mun ngan nu nya oge ogé parantos
+	pikeun pisan sanes sangkan sareng sok supaya tapi teh téh teu ti tina tos
+	upami urang wae waé yén yen`)
+
+var indonesianStopwords = wordSet(`
+	ada adalah agar akan antara atau bagi bahwa banyak belum bisa dalam dan
+	dapat dari dengan di dia hanya harus ia ini itu juga kami karena ke kita
+	lebih masih mereka oleh pada para saat saja sangat saya secara sebagai
+	sedang sejak serta seperti sudah tanpa telah tentang tetapi tidak untuk
+	yaitu yang`)
+
+// minStem: akar kecap teu dipotong deui mun langkung pondok ti ieu.
+const minStem = 4
+
+// stemIndonesian: stemmer basajan (tanpa kamus) pikeun imbuhan umum basa
+// Indonésia: partikel (-lah, -kah, -pun), kata ganti (-ku, -mu, -nya),
+// akhiran -kan/-an, sareng awalan meN-, peN-, ber-, ter-, per-, di-, ke-.
+func stemIndonesian(word string) string {
+	suffixAn := false
+	for _, suffixes := range [][]string{{"lah", "kah", "tah", "pun"}, {"nya", "ku", "mu"}, {"kan", "an"}} {
+		for _, s := range suffixes {
+			if strings.HasSuffix(word, s) && len(word)-len(s) >= minStem {
+				word = strings.TrimSuffix(word, s)
+				suffixAn = s == "an"
+				break
+			}
+		}
+	}
+
+	// Awalan dipiceun dugi ka dua kali (mis. mem-per-baik).
+	for i := 0; i < 2; i++ {
+		stripped := stripPrefix(word, suffixAn)
+		if stripped == word || len(stripped) < minStem {
+			break
+		}
+		word = stripped
+	}
+	return word
+}
+
+func isVowel(b byte) bool { return strings.IndexByte("aiueo", b) >= 0 }
+
+// stripPrefix miceun hiji awalan, kalebet luluh konsonan meN-/peN-
+// (menulis → tulis, memukul → pukul, menyapu → sapu). ke- ngan dipiceun
+// babarengan jeung -an (kebudayaan), sangkan "kertas" teu kapotong.
+func stripPrefix(w string, suffixAn bool) string {
+	for _, p := range []string{"me", "pe"} {
+		if !strings.HasPrefix(w, p) || len(w) < len(p)+3 {
+			continue
+		}
+		rest := w[len(p):]
+		switch {
+		case strings.HasPrefix(rest, "ny") && isVowel(rest[2]):
+			return "s" + rest[2:]
+		case strings.HasPrefix(rest, "ng") && (isVowel(rest[2]) || strings.IndexByte("ghk", rest[2]) >= 0):
+			return rest[2:]
+		case rest[0] == 'm' && isVowel(rest[1]):
+			return "p" + rest[1:]
+		case rest[0] == 'm' && strings.IndexByte("bfpv", rest[1]) >= 0:
+			return rest[1:]
+		case rest[0] == 'n' && isVowel(rest[1]):
+			return "t" + rest[1:]
+		case rest[0] == 'n' && strings.IndexByte("cdjtsz", rest[1]) >= 0:
+			return rest[1:]
+		case p == "pe" && strings.HasPrefix(rest, "r"):
+			return rest[1:]
+		case strings.IndexByte("lmnrwy", rest[0]) >= 0:
+			return rest
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(w, "ber") && len(w) > 5:
+		return w[3:]
+	case strings.HasPrefix(w, "belajar"):
+		return w[3:]
+	case strings.HasPrefix(w, "be") && len(w) > 5 && !isVowel(w[2]) && w[3:5] == "er":
+		return w[2:]
+	case strings.HasPrefix(w, "ter") && len(w) > 5:
+		return w[3:]
+	case strings.HasPrefix(w, "di") && len(w) > 5:
+		return w[2:]
+	case strings.HasPrefix(w, "ke") && suffixAn:
+		return w[2:]
+	}
+	return w
+}
@@ -0,0 +1,99 @@
+package fts
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewAnalyzer(t *testing.T) {
+	cases := []struct {
+		spec string
+		name string
+		err  string
+	}{
+		{"", "standar", ""},
+		{"standard", "standar", ""},
+		{" Indonesian ", "indonesia", ""},
+		{"sundanese", "sunda", ""},
+		{"keyword", "kecap", ""},
+		{"ngram", "ngram:3", ""},
+		{"ngram:5", "ngram:5", ""},
+		{"ngram:0", "", "1 dugi ka 10"},
+		{"ngram:11", "", "1 dugi ka 10"},
+		{"ngram:x", "", "1 dugi ka 10"},
+		{"sunda:2", "", "teu nampi paraméter"},
+		{"klingon", "", "teu dikenal"},
+	}
+	for _, c := range cases {
+		t.Run(c.spec, func(t *testing.T) {
+			an, err := NewAnalyzer(c.spec)
+			if c.err != "" {
+				if err == nil || !strings.Contains(err.Error(), c.err) {
+					t.Fatalf("err = %v, want %q", err, c.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if an.Name() != c.name {
+				t.Errorf("Name() = %q, want %q", an.Name(), c.name)
+			}
+		})
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	cases := []struct {
+		spec string
+		text string
+		want []string
+	}{
+		{"standar", "Maung di Leuweung, ka-2!", []string{"maung", "leuweung"}},
+		{"basajan", "Maung di Leuweung", []string{"maung", "di", "leuweung"}},
+		{"kecap", "  Kode   ABC-12 ", []string{"kode abc-12"}},
+		{"kecap", "   ", nil},
+		{"ngram:3", "maung ka", []string{"mau", "aun", "ung", "ka"}},
+		{"sunda", "abdi teh resep kana maung", []string{"resep", "maung"}},
+		{"indonesia", "mereka sedang menuliskan buku-bukunya", []string{"tulis", "buku", "buku"}},
+	}
+	for _, c := range cases {
+		t.Run(c.spec+"/"+c.text, func(t *testing.T) {
+			an, err := NewAnalyzer(c.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := an.Analyze(c.text); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("Analyze = %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestStemIndonesian(t *testing.T) {
+	cases := map[string]string{
+		"menulis":    "tulis",
+		"memukul":    "pukul",
+		"menyapu":    "sapu",
+		"mengambil":  "ambil",
+		"membaca":    "baca",
+		"pembaca":    "baca",
+		"bermain":    "main",
+		"belajar":    "ajar",
+		"terbawa":    "bawa",
+		"ditulis":    "tulis",
+		"kebudayaan": "budaya",
+		"kertas":     "kertas",
+		"makanan":    "makan",
+		"bukunya":    "buku",
+		"bacalah":    "baca",
+		"memperluas": "luas",
+		"dia":        "dia",
+	}
+	for word, want := range cases {
+		if got := stemIndonesian(word); got != want {
+			t.Errorf("stemIndonesian(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
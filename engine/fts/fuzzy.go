@@ -0,0 +1,66 @@
+package fts
+
+// maxEdits: jarak édit nu diidinan kanggo hiji kecap. Kecap pondok langkung
+// ketat (≤ 2 hurup kedah pas, 3-5 hurup hiji salah, 6-8 dua, salajengna
+// tilu) sangkan SAMAR teu nyocogkeun ampir sadaya kecap pondok.
+func maxEdits(term string, limit int) int {
+	n := len([]rune(term)) / 3
+	if n < limit {
+		return n
+	}
+	return limit
+}
+
+// fuzzyTerm: sadaya kecap indeks nu jarakna ka term teu langkung ti
+// maxEdits. Skor unggal baris nyaéta skor varian pangsaéna, dikirangan
+// numutkeun jarakna (pas = 1, hiji salah = 1/2, ...).
+func (c *evalContext) fuzzyTerm(term string) map[string]float64 {
+	limit := maxEdits(term, c.fuzzy)
+	target := []rune(term)
+	result := make(map[string]float64)
+	for candidate, docs := range c.idx.Postings {
+		d := editDistance(target, []rune(candidate), limit)
+		if d > limit {
+			continue
+		}
+		c.match(candidate)
+		weight := 1 / float64(1+d)
+		for id, s := range c.idx.score(docs, nil) {
+			if s*weight > result[id] {
+				result[id] = s * weight
+			}
+		}
+	}
+	return result
+}
+
+// editDistance: jarak Levenshtein (sisipan, hapusan, gantian) antara a sareng
+// b. Eureun mimiti sareng mulihkeun limit+1 mun jarakna pasti langkung ti limit.
+func editDistance(a, b []rune, limit int) int {
+	if diff := len(a) - len(b); diff > limit || -diff > limit {
+		return limit + 1
+	}
+
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		best := curr[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			best = min(best, curr[j])
+		}
+		if best > limit {
+			return limit + 1
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,89 @@
+package fts
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEditDistance(t *testing.T) {
+	cases := []struct {
+		a, b  string
+		limit int
+		want  int
+	}{
+		{"maung", "maung", 2, 0},
+		{"maung", "maong", 2, 1},
+		{"maung", "maun", 2, 1},
+		{"maung", "mamaung", 2, 2},
+		{"sejarah", "sajaroh", 2, 2},
+		{"maung", "uncal", 2, 3}, // langkung ti limit: limit+1
+		{"maung", "ma", 1, 2},    // bédana panjang langkung ti limit
+		{"", "abc", 3, 3},
+		{"éndah", "endah", 1, 1}, // rune, sanés bait
+	}
+	for _, c := range cases {
+		t.Run(c.a+"/"+c.b, func(t *testing.T) {
+			if got := editDistance([]rune(c.a), []rune(c.b), c.limit); got != c.want {
+				t.Errorf("editDistance = %d, want %d", got, c.want)
+			}
+		})
+	}
+}
+
+func TestMaxEdits(t *testing.T) {
+	cases := []struct {
+		term  string
+		limit int
+		want  int
+	}{
+		{"ka", 2, 0},
+		{"aki", 2, 1},
+		{"maung", 2, 1},
+		{"leuweung", 2, 2},
+		{"leuweung", 1, 1},
+		{"kabudayaan", 3, 3},
+		{"kabudayaan", 2, 2},
+	}
+	for _, c := range cases {
+		if got := maxEdits(c.term, c.limit); got != c.want {
+			t.Errorf("maxEdits(%q, %d) = %d, want %d", c.term, c.limit, got, c.want)
+		}
+	}
+}
+
+func TestFuzzySearch(t *testing.T) {
+	idx := testIndex(t, "standar", leuweungDocs)
+	cases := []struct {
+		query string
+		fuzzy int
+		want  []string
+	}{
+		{"maong", 0, []string{}},
+		{"maong", 2, []string{"1", "2"}},
+		{"leuwueng", 2, []string{"1", "2", "4"}},
+		{"mong", 2, []string{}}, // 4 hurup: ngan hiji salah
+		{"uncal lumpta", 2, []string{"3"}},
+		{`"maung lumpat"`, 2, []string{"1"}},
+	}
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			r, err := search(idx, c.query, c.fuzzy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hitIDs(r); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("hasil = %v, want %v", got, c.want)
+			}
+		})
+	}
+
+	// Nu pas langkung luhur tibatan nu salah ketik.
+	idx = testIndex(t, "standar", map[string]string{"pas": "maung", "salah": "maong"})
+	r, err := search(idx, "maung", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Hits) != 2 || r.Hits[0].RowID != "pas" {
+		t.Errorf("urutan = %+v, want pas heula", r.Hits)
+	}
+}
@@ -0,0 +1,164 @@
+package fts
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Default cuplikan SOROT.
+const (
+	DefaultFragment  = 80
+	DefaultHighlight = "**"
+)
+
+// Highlight: tanda sorot sareng panjang cuplikan (karakter).
+type Highlight struct {
+	Pre      string
+	Post     string
+	Fragment int
+}
+
+type span struct{ start, end int }
+
+// Snippet mulihkeun cuplikan text di sakitar kecap nu kapendak ku query,
+// unggal kecap nu cocog diapit ku h.Pre/h.Post. Cuplikan dipotong dina
+// wates kecap sareng ditandaan "…" mun aya téks nu dipiceun.
+func (r *Result) Snippet(text string, h Highlight) string {
+	if h.Fragment <= 0 {
+		h.Fragment = DefaultFragment
+	}
+	if h.Pre == "" && h.Post == "" {
+		h.Pre, h.Post = DefaultHighlight, DefaultHighlight
+	}
+
+	runes := []rune(text)
+	spans := wordSpans(runes)
+	var marked []span
+	for _, s := range spans {
+		if r.matches(string(runes[s.start:s.end])) {
+			marked = append(marked, s)
+		}
+	}
+	// Analyzer sapertos "kecap" nganggap sakabéh nilai hiji token.
+	if len(marked) == 0 && len(spans) > 0 && r.matches(text) {
+		marked = []span{{spans[0].start, spans[len(spans)-1].end}}
+	}
+
+	start, end := window(runes, spans, marked, h.Fragment)
+	from, to := start, end
+	for from < to && unicode.IsSpace(runes[from]) {
+		from++
+	}
+	for to > from && unicode.IsSpace(runes[to-1]) {
+		to--
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	pos := from
+	for _, m := range marked {
+		if m.start < from || m.end > to {
+			continue
+		}
+		b.WriteString(string(runes[pos:m.start]))
+		b.WriteString(h.Pre)
+		b.WriteString(string(runes[m.start:m.end]))
+		b.WriteString(h.Post)
+		pos = m.end
+	}
+	b.WriteString(string(runes[pos:to]))
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// matches: leres mun salah sahiji token word kapendak ku query.
+func (r *Result) matches(word string) bool {
+	for _, t := range r.analyzer.Analyze(word) {
+		if r.terms[t] {
+			return true
+		}
+	}
+	return false
+}
+
+// wordSpans: posisi (rune) unggal kecap, dipeulah sapertos words.
+func wordSpans(runes []rune) []span {
+	var spans []span
+	start := -1
+	for i, c := range runes {
+		if unicode.IsLetter(c) || unicode.IsNumber(c) {
+			if start == -1 {
+				start = i
+			}
+			continue
+		}
+		if start != -1 {
+			spans = append(spans, span{start, i})
+			start = -1
+		}
+	}
+	if start != -1 {
+		spans = append(spans, span{start, len(runes)})
+	}
+	return spans
+}
+
+// window milih bagian text sapanjang length nu ngandung paling seueur kecap
+// nu kapendak, dimimitian saeutik saméméh kecap kahiji sangkan aya kontéksna.
+func window(runes []rune, spans, marked []span, length int) (int, int) {
+	if len(runes) <= length {
+		return 0, len(runes)
+	}
+
+	start := 0
+	if len(marked) > 0 {
+		best, count := 0, 0
+		for i, m := range marked {
+			n := 0
+			for _, other := range marked[i:] {
+				if other.end-m.start > length {
+					break
+				}
+				n++
+			}
+			if n > count {
+				best, count = i, n
+			}
+		}
+		start = marked[best].start - length/4
+	}
+	if start+length > len(runes) {
+		start = len(runes) - length
+	}
+	if start < 0 {
+		start = 0
+	}
+	end := start + length
+
+	// Tong motong kecap di tengah.
+	if start > 0 {
+		for _, s := range spans {
+			if s.end > start {
+				if s.start < start {
+					start = s.end
+				}
+				break
+			}
+		}
+	}
+	cut := end
+	for _, s := range spans {
+		if s.start < end && s.end > end {
+			cut = s.start
+			break
+		}
+	}
+	if cut > start {
+		end = cut
+	}
+	return start, end
+}
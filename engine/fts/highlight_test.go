@@ -0,0 +1,44 @@
+package fts
+
+import "testing"
+
+func TestSnippet(t *testing.T) {
+	long := "Di hiji leuweung anu jauh pisan aya sato anu ngaranna uncal, manéhna resep lumpat. " +
+		"Hiji waktos aya maung anu lapar pisan datang ti kulon."
+
+	cases := []struct {
+		name  string
+		spec  string
+		text  string
+		query string
+		h     Highlight
+		want  string
+	}{
+		{"tanda bawaan", "standar", "Maung lumpat ka leuweung", "maung", Highlight{},
+			"**Maung** lumpat ka leuweung"},
+		{"tanda sorangan", "standar", "Maung lumpat ka leuweung", "maung ATAWA leuweung", Highlight{Pre: "<b>", Post: "</b>"},
+			"<b>Maung</b> lumpat ka <b>leuweung</b>"},
+		{"awalan", "standar", "leuweung geledegan", "leuw*", Highlight{},
+			"**leuweung** geledegan"},
+		{"SANES teu disorot", "standar", "maung bodas", "maung -hideung", Highlight{},
+			"**maung** bodas"},
+		{"analyzer kecap", "kecap", "Kode ABC", "'kode abc'", Highlight{},
+			"**Kode ABC**"},
+		{"dipotong dina wates kecap", "standar", long, "maung", Highlight{Fragment: 40},
+			"…aya **maung** anu lapar pisan datang…"},
+		{"teu aya nu cocog", "standar", long, "hayam", Highlight{Fragment: 20},
+			"Di hiji leuweung anu…"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			idx := testIndex(t, c.spec, map[string]string{"1": c.text})
+			r, err := search(idx, c.query, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := r.Snippet(c.text, c.h); got != c.want {
+				t.Errorf("Snippet = %q, want %q", got, c.want)
+			}
+		})
+	}
+}
@@ -7,43 +7,114 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
-	"unicode"
 
 	"github.com/febrd/maungdb/engine/storage"
 )
 
-type InvertedIndex map[string][]string
+// Index: indeks kabalik hiji kolom. Postings nyimpen posisi unggal kecap
+// dina unggal baris (kanggo milarian frasa sareng TF), DocLen jumlah kecap
+// unggal baris (kanggo BM25). Analyzer dicatet sangkan query sareng
+// parobahan saterusna diolah ku cara nu sami.
+type Index struct {
+	Analyzer string                      `json:"analyzer,omitempty"`
+	Postings map[string]map[string][]int `json:"postings"`
+	DocLen   map[string]int              `json:"doc_len"`
 
-type FTSManager struct {
-	mu sync.RWMutex
+	analyzer Analyzer
+	// rowTerms: kecap unik unggal baris, sangkan remove teu kedah nyusud
+	// sakabéh kosakata. Teu disimpen; diwangun deui tina Postings nalika dimuat.
+	rowTerms map[string][]string
 }
 
-var GlobalFTS = &FTSManager{}
+func newIndex(an Analyzer) *Index {
+	return &Index{
+		Analyzer: an.Name(),
+		Postings: make(map[string]map[string][]int),
+		DocLen:   make(map[string]int),
+		analyzer: an,
+		rowTerms: make(map[string][]string),
+	}
+}
+
+// buildRowTerms ngawangun rowTerms tina Postings (saatos dimuat ti file).
+func (idx *Index) buildRowTerms() {
+	idx.rowTerms = make(map[string][]string, len(idx.DocLen))
+	for token, docs := range idx.Postings {
+		for rowID := range docs {
+			idx.rowTerms[rowID] = append(idx.rowTerms[rowID], token)
+		}
+	}
+}
+
+// add ngalebetkeun eusi hiji baris kana indeks. Mun barisna tos aya,
+// eusi lamina diganti.
+func (idx *Index) add(rowID, content string) {
+	if _, ok := idx.DocLen[rowID]; ok {
+		idx.remove(rowID)
+	}
+	tokens := idx.analyzer.Analyze(content)
+	var terms []string
+	for pos, token := range tokens {
+		docs := idx.Postings[token]
+		if docs == nil {
+			docs = make(map[string][]int)
+			idx.Postings[token] = docs
+		}
+		if _, ok := docs[rowID]; !ok {
+			terms = append(terms, token)
+		}
+		docs[rowID] = append(docs[rowID], pos)
+	}
+	idx.DocLen[rowID] = len(tokens)
+	idx.rowTerms[rowID] = terms
+}
 
-func tokenize(text string) []string {
-	text = strings.ToLower(text)
-	
-	cleaner := func(c rune) bool {
-		return !unicode.IsLetter(c) && !unicode.IsNumber(c)
-	}
-	
-	words := strings.FieldsFunc(text, cleaner)
-	uniqueWords := make(map[string]bool)
-	var result []string
-
-	for _, w := range words {
-		if len(w) > 2 && !uniqueWords[w] { 
-			uniqueWords[w] = true
-			result = append(result, w)
+// remove miceun hiji baris tina indeks.
+func (idx *Index) remove(rowID string) {
+	if _, ok := idx.DocLen[rowID]; !ok {
+		return
+	}
+	for _, token := range idx.rowTerms[rowID] {
+		if docs, ok := idx.Postings[token]; ok {
+			delete(docs, rowID)
+			if len(docs) == 0 {
+				delete(idx.Postings, token)
+			}
 		}
 	}
-	return result
+	delete(idx.rowTerms, rowID)
+	delete(idx.DocLen, rowID)
+}
+
+// RowOp: hiji parobahan baris pikeun ApplyRowOps. RemoveID (mun aya)
+// dipiceun heula, teras Row (mun aya) dilebetkeun; OMEAN ngeusian duanana.
+type RowOp struct {
+	RemoveID string
+	Row      string
+}
+
+type FTSManager struct {
+	mu sync.RWMutex
 }
 
-func (fm *FTSManager) BuildIndex(tableName, colName string, schemaCols []string) error {
+var GlobalFTS = &FTSManager{}
+
+// BuildIndex ngawangun (deui) indeks teks hiji kolom. analyzer kosong
+// hartosna analyzer indeks nu tos aya dipertahankeun (atanapi DefaultAnalyzer).
+func (fm *FTSManager) BuildIndex(tableName, colName string, schemaCols []string, analyzer string) error {
 	fm.mu.Lock()
 	defer fm.mu.Unlock()
 
+	if analyzer == "" {
+		if old, err := fm.loadFromFile(tableName, colName); err == nil {
+			analyzer = old.Analyzer
+		}
+	}
+	an, err := NewAnalyzer(analyzer)
+	if err != nil {
+		return err
+	}
+
 	rows, err := storage.ReadAll(tableName)
 	if err != nil {
 		return err
@@ -60,41 +131,62 @@ func (fm *FTSManager) BuildIndex(tableName, colName string, schemaCols []string)
 		return fmt.Errorf("kolom %s teu kapendak", colName)
 	}
 
-	index := make(InvertedIndex)
+	index := newIndex(an)
 
 	for _, row := range rows {
 		if row == "" { continue }
 		parts := strings.Split(row, "|")
 		if len(parts) <= colIdx { continue }
 
-		rowID := parts[0]
-		content := parts[colIdx]
-		tokens := tokenize(content)
-
-		for _, token := range tokens {
-			index[token] = append(index[token], rowID)
-		}
+		index.add(parts[0], parts[colIdx])
 	}
 
 	return fm.saveToFile(tableName, colName, index)
 }
 
-func (fm *FTSManager) Search(tableName, colName, keyword string) ([]string, error) {
+// Options: pilihan pilarian. Fuzzy = jarak édit maksimal unggal kecap
+// (0 = kedah pas).
+type Options struct {
+	Fuzzy int
+}
+
+// Result: hasil Search, diruntuykeun dumasar skor BM25, sareng kecap indeks
+// nu kapendak (kanggo Snippet).
+type Result struct {
+	Hits     []Hit
+	terms    map[string]bool
+	analyzer Analyzer
+}
+
+// Search ngajalankeun query KOREHAN (kecap, "frasa", awalan*, SARENG/AND,
+// ATAWA/OR, SANES/NOT/-kecap, kurung) sareng mulihkeun baris nu cocog.
+func (fm *FTSManager) Search(tableName, colName, query string, opts Options) (*Result, error) {
 	fm.mu.RLock()
 	defer fm.mu.RUnlock()
 
 	index, err := fm.loadFromFile(tableName, colName)
 	if err != nil {
-		return nil, fmt.Errorf("index teks teu acan didamel (mangga jalankeun: DAMEL INDEKS_TEKS %s %s)", tableName, colName)
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("index teks teu acan didamel (mangga jalankeun: DAMEL INDEKS_TEKS %s DINA %s)", tableName, colName)
+		}
+		return nil, err
 	}
 
-	keyword = strings.ToLower(strings.TrimSpace(keyword))
-	rowIDs, found := index[keyword]
-	if !found {
-		return []string{}, nil 
+	q, err := parseQuery(query, index.analyzer)
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{Hits: []Hit{}, terms: make(map[string]bool), analyzer: index.analyzer}
+	if q == nil {
+		return result, nil
 	}
 
-	return rowIDs, nil
+	scores, err := q.eval(&evalContext{idx: index, fuzzy: opts.Fuzzy, matched: result.terms})
+	if err != nil {
+		return nil, err
+	}
+	result.Hits = rank(scores)
+	return result, nil
 }
 
 func (fm *FTSManager) getPath(tableName, colName string) string {
@@ -103,7 +195,7 @@ func (fm *FTSManager) getPath(tableName, colName string) string {
 	return filepath.Join(dbPath, fmt.Sprintf("%s_%s.fts", tableName, colName))
 }
 
-func (fm *FTSManager) saveToFile(table, col string, data InvertedIndex) error {
+func (fm *FTSManager) saveToFile(table, col string, data *Index) error {
 	path := fm.getPath(table, col)
 	f, err := os.Create(path)
 	if err != nil { return err }
@@ -111,13 +203,125 @@ func (fm *FTSManager) saveToFile(table, col string, data InvertedIndex) error {
 	return json.NewEncoder(f).Encode(data)
 }
 
-func (fm *FTSManager) loadFromFile(table, col string) (InvertedIndex, error) {
+func (fm *FTSManager) loadFromFile(table, col string) (*Index, error) {
 	path := fm.getPath(table, col)
-	f, err := os.Open(path)
+	raw, err := os.ReadFile(path)
 	if err != nil { return nil, err }
-	defer f.Close()
-	
-	var data InvertedIndex
-	if err := json.NewDecoder(f).Decode(&data); err != nil { return nil, err }
+
+	data := &Index{}
+	if err := json.Unmarshal(raw, data); err == nil && data.Postings != nil {
+		if data.analyzer, err = NewAnalyzer(data.Analyzer); err != nil {
+			return nil, fmt.Errorf("indeks teks %s.%s: %v", table, col, err)
+		}
+		if data.DocLen == nil {
+			data.DocLen = make(map[string]int)
+		}
+		data.buildRowTerms()
+		return data, nil
+	}
+
+	// Format heubeul: kecap -> daptar ID baris, tanpa posisi.
+	var legacy map[string][]string
+	if err := json.Unmarshal(raw, &legacy); err != nil {
+		return nil, fmt.Errorf("indeks teks %s.%s ruksak: %v", table, col, err)
+	}
+	data = newIndex(standardAnalyzer{})
+	for token, ids := range legacy {
+		docs := make(map[string][]int)
+		for _, id := range ids {
+			docs[id] = nil
+			data.DocLen[id]++
+		}
+		data.Postings[token] = docs
+	}
+	data.buildRowTerms()
 	return data, nil
-}
\ No newline at end of file
+}
+
+// ApplyRowOps nerapkeun parobahan baris ka sadaya indeks teks tabel, dina
+// runtuyan aslina, sangkan KOREHAN langsung ningali data nu anyar disimpen.
+// Unggal indeks dimuat sareng disimpen sakali pikeun sakumna ops, janten
+// nu nyauran kedah ngempelkeun ops hiji komit (tingali refreshRowIndexes).
+func (fm *FTSManager) ApplyRowOps(tableName string, schemaCols []string, ops []RowOp) error {
+	if len(ops) == 0 {
+		return nil
+	}
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	for colIdx, col := range schemaCols {
+		index, err := fm.loadFromFile(tableName, col)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, op := range ops {
+			if op.RemoveID != "" {
+				index.remove(op.RemoveID)
+			}
+			if op.Row == "" {
+				continue
+			}
+			parts := strings.Split(op.Row, "|")
+			if len(parts) > colIdx {
+				index.add(parts[0], parts[colIdx])
+			}
+		}
+
+		if err := fm.saveToFile(tableName, col, index); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AnalyzerOf mulihkeun analyzer nu dicatet dina file indeks teks (kanggo katalog).
+func AnalyzerOf(path string) (string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	var header struct {
+		Analyzer string `json:"analyzer"`
+	}
+	json.Unmarshal(raw, &header)
+	if header.Analyzer == "" {
+		return DefaultAnalyzer, nil
+	}
+	return header.Analyzer, nil
+}
+
+// IndexedColumns: daptar kolom tabel nu boga indeks teks (.fts)
+func (fm *FTSManager) IndexedColumns(tableName string, schemaCols []string) []string {
+	fm.mu.RLock()
+	defer fm.mu.RUnlock()
+
+	var cols []string
+	for _, c := range schemaCols {
+		if _, err := os.Stat(fm.getPath(tableName, c)); err == nil {
+			cols = append(cols, c)
+		}
+	}
+	return cols
+}
+
+func (fm *FTSManager) RenameIndex(oldTable, oldCol, newTable, newCol string) error {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	return os.Rename(fm.getPath(oldTable, oldCol), fm.getPath(newTable, newCol))
+}
+
+func (fm *FTSManager) DropIndex(tableName, colName string) error {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	err := os.Remove(fm.getPath(tableName, colName))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
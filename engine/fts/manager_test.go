@@ -0,0 +1,63 @@
+package fts
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// TestIndexRemove: remove ngan keuna kana kecap barisna, add kana baris nu
+// tos aya ngaganti eusina, sareng rowTerms diwangun deui saatos dimuat.
+func TestIndexRemove(t *testing.T) {
+	idx := testIndex(t, "standar", map[string]string{
+		"1": "maung lumpat maung",
+		"2": "maung sare",
+	})
+
+	steps := []struct {
+		name string
+		do   func(idx *Index)
+		want map[string]map[string][]int
+	}{
+		{"awal", func(*Index) {}, map[string]map[string][]int{
+			"maung":  {"1": {0, 2}, "2": {0}},
+			"lumpat": {"1": {1}},
+			"sare":   {"2": {1}},
+		}},
+		{"ganti eusi", func(idx *Index) { idx.add("1", "uncal lumpat") }, map[string]map[string][]int{
+			"maung":  {"2": {0}},
+			"uncal":  {"1": {0}},
+			"lumpat": {"1": {1}},
+			"sare":   {"2": {1}},
+		}},
+		{"dimuat deui", func(idx *Index) {
+			raw, err := json.Marshal(idx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			*idx = Index{analyzer: idx.analyzer}
+			if err := json.Unmarshal(raw, idx); err != nil {
+				t.Fatal(err)
+			}
+			idx.buildRowTerms()
+			idx.remove("2")
+		}, map[string]map[string][]int{
+			"uncal":  {"1": {0}},
+			"lumpat": {"1": {1}},
+		}},
+		{"baris teu aya", func(idx *Index) { idx.remove("9") }, map[string]map[string][]int{
+			"uncal":  {"1": {0}},
+			"lumpat": {"1": {1}},
+		}},
+		{"baris terakhir", func(idx *Index) { idx.remove("1") }, map[string]map[string][]int{}},
+	}
+	for _, s := range steps {
+		s.do(idx)
+		if !reflect.DeepEqual(idx.Postings, s.want) {
+			t.Errorf("%s: postings = %v, want %v", s.name, idx.Postings, s.want)
+		}
+		if len(idx.rowTerms) != len(idx.DocLen) {
+			t.Errorf("%s: rowTerms %v teu saluyu sareng DocLen %v", s.name, idx.rowTerms, idx.DocLen)
+		}
+	}
+}
@@ -0,0 +1,409 @@
+package fts
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Hit: hiji baris hasil KOREHAN sareng skor relevansina.
+type Hit struct {
+	RowID string
+	Score float64
+}
+
+// Parameter BM25.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+type nodeKind int
+
+const (
+	nodeTerm nodeKind = iota
+	nodePrefix
+	nodePhrase
+	nodeAnd
+	nodeOr
+	nodeNot
+)
+
+// queryNode: hiji titik tangkal query KOREHAN.
+type queryNode struct {
+	kind     nodeKind
+	terms    []string
+	children []*queryNode
+}
+
+// lexQuery meulah query jadi kecap, frasa (dina "..." atanapi '...') sareng kurung.
+func lexQuery(query string) ([]string, error) {
+	var tokens []string
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, string(c))
+			i++
+		case c == '"' || c == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != c {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, errors.New("tanda kutip frasa teu ditutup")
+			}
+			tokens = append(tokens, string(runes[i:end+1]))
+			i = end + 1
+		default:
+			end := i
+			for end < len(runes) && !strings.ContainsRune(" \t\n()\"'", runes[end]) {
+				end++
+			}
+			tokens = append(tokens, string(runes[i:end]))
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+type queryParser struct {
+	tokens   []string
+	pos      int
+	analyzer Analyzer
+}
+
+func (p *queryParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func isOr(tok string) bool  { u := strings.ToUpper(tok); return u == "ATAWA" || u == "OR" }
+func isAnd(tok string) bool { u := strings.ToUpper(tok); return u == "SARENG" || u == "AND" }
+func isNot(tok string) bool { u := strings.ToUpper(tok); return u == "SANES" || u == "NOT" }
+
+// parseQuery: or := and (ATAWA and)*; and := unary ([SARENG] unary)*;
+// unary := SANES unary | -kecap | ( or ) | "frasa" | kecap | awalan*.
+// Mulihkeun nil mun query teu ngandung kecap nu tiasa dipilarian.
+func parseQuery(query string, an Analyzer) (*queryNode, error) {
+	tokens, err := lexQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	p := &queryParser{tokens: tokens, analyzer: an}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("query KOREHAN teu valid dekat '%s'", p.peek())
+	}
+	return node, nil
+}
+
+func (p *queryParser) parseOr() (*queryNode, error) {
+	var children []*queryNode
+	for {
+		node, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if node != nil {
+			children = append(children, node)
+		}
+		if !isOr(p.peek()) {
+			break
+		}
+		p.pos++
+	}
+	return combine(nodeOr, children), nil
+}
+
+func (p *queryParser) parseAnd() (*queryNode, error) {
+	var children []*queryNode
+	for {
+		tok := p.peek()
+		if tok == "" || tok == ")" || isOr(tok) {
+			break
+		}
+		if isAnd(tok) {
+			p.pos++
+			continue
+		}
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if node != nil {
+			children = append(children, node)
+		}
+	}
+	return combine(nodeAnd, children), nil
+}
+
+func (p *queryParser) parseUnary() (*queryNode, error) {
+	tok := p.peek()
+	p.pos++
+
+	switch {
+	case isNot(tok):
+		if p.peek() == "" {
+			return nil, errors.New("SANES/NOT kedah dituturkeun ku kecap")
+		}
+		child, err := p.parseUnary()
+		if err != nil || child == nil {
+			return nil, err
+		}
+		return &queryNode{kind: nodeNot, children: []*queryNode{child}}, nil
+
+	case tok == "(":
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, errors.New("kurung query KOREHAN teu ditutup")
+		}
+		p.pos++
+		return node, nil
+
+	case tok == ")":
+		return nil, errors.New("kurung tutup kaleuleuwihi dina query KOREHAN")
+
+	case strings.HasPrefix(tok, "\"") || strings.HasPrefix(tok, "'"):
+		return p.termNode(tok[1:len(tok)-1], true), nil
+
+	case strings.HasPrefix(tok, "-") && len(tok) > 1:
+		child := p.termNode(tok[1:], false)
+		if child == nil {
+			return nil, nil
+		}
+		return &queryNode{kind: nodeNot, children: []*queryNode{child}}, nil
+	}
+	return p.termNode(tok, false), nil
+}
+
+// termNode ngarobih kecap (atanapi frasa) janten titik nganggo analyzer nu
+// sami sareng indeks. Kecap nu kabeulah (mis. "sejarah-sunda") dianggap frasa.
+func (p *queryParser) termNode(text string, phrase bool) *queryNode {
+	prefix := !phrase && strings.HasSuffix(text, "*")
+	terms := p.analyzer.Analyze(strings.TrimSuffix(text, "*"))
+	switch {
+	case len(terms) == 0:
+		return nil
+	case len(terms) > 1:
+		return &queryNode{kind: nodePhrase, terms: terms}
+	case prefix:
+		return &queryNode{kind: nodePrefix, terms: terms}
+	}
+	return &queryNode{kind: nodeTerm, terms: terms}
+}
+
+func combine(kind nodeKind, children []*queryNode) *queryNode {
+	switch len(children) {
+	case 0:
+		return nil
+	case 1:
+		return children[0]
+	}
+	return &queryNode{kind: kind, children: children}
+}
+
+// evalContext: kaayaan hiji pilarian. matched ngumpulkeun kecap indeks nu
+// kapendak (sanés di jero SANES) kanggo nyorot cuplikan.
+type evalContext struct {
+	idx     *Index
+	fuzzy   int
+	matched map[string]bool
+	negated bool
+}
+
+func (c *evalContext) match(term string) {
+	if !c.negated {
+		c.matched[term] = true
+	}
+}
+
+// eval mulihkeun baris nu cocog sareng skorna.
+func (n *queryNode) eval(c *evalContext) (map[string]float64, error) {
+	idx := c.idx
+	switch n.kind {
+	case nodeTerm:
+		if c.fuzzy > 0 {
+			return c.fuzzyTerm(n.terms[0]), nil
+		}
+		if _, ok := idx.Postings[n.terms[0]]; ok {
+			c.match(n.terms[0])
+		}
+		return idx.score(idx.Postings[n.terms[0]], nil), nil
+
+	case nodePrefix:
+		result := make(map[string]float64)
+		for term, docs := range idx.Postings {
+			if strings.HasPrefix(term, n.terms[0]) {
+				c.match(term)
+				for id, s := range idx.score(docs, nil) {
+					result[id] += s
+				}
+			}
+		}
+		return result, nil
+
+	case nodePhrase:
+		freqs, err := idx.phrase(n.terms)
+		if err != nil {
+			return nil, err
+		}
+		if len(freqs) > 0 {
+			for _, t := range n.terms {
+				c.match(t)
+			}
+		}
+		return idx.score(nil, freqs), nil
+
+	case nodeNot:
+		negated := c.negated
+		c.negated = true
+		inner, err := n.children[0].eval(c)
+		c.negated = negated
+		if err != nil {
+			return nil, err
+		}
+		result := make(map[string]float64)
+		for id := range idx.DocLen {
+			if _, ok := inner[id]; !ok {
+				result[id] = 0
+			}
+		}
+		return result, nil
+
+	case nodeAnd:
+		var result map[string]float64
+		for _, child := range n.children {
+			scores, err := child.eval(c)
+			if err != nil {
+				return nil, err
+			}
+			if result == nil {
+				result = scores
+				continue
+			}
+			for id := range result {
+				if s, ok := scores[id]; ok {
+					result[id] += s
+				} else {
+					delete(result, id)
+				}
+			}
+		}
+		return result, nil
+
+	case nodeOr:
+		result := make(map[string]float64)
+		for _, child := range n.children {
+			scores, err := child.eval(c)
+			if err != nil {
+				return nil, err
+			}
+			for id, s := range scores {
+				result[id] += s
+			}
+		}
+		return result, nil
+	}
+	return nil, fmt.Errorf("titik query teu dikenal: %d", n.kind)
+}
+
+// phrase ngitung sabaraha kali runtuyan terms muncul dina unggal baris.
+func (idx *Index) phrase(terms []string) (map[string]int, error) {
+	freqs := make(map[string]int)
+	for id, first := range idx.Postings[terms[0]] {
+		if first == nil {
+			return nil, errors.New("indeks teks ieu format heubeul (tanpa posisi); damel deui ku DAMEL INDEKS_TEKS pikeun milarian frasa")
+		}
+		count := 0
+		for _, start := range first {
+			match := true
+			for k := 1; k < len(terms); k++ {
+				if !containsPos(idx.Postings[terms[k]][id], start+k) {
+					match = false
+					break
+				}
+			}
+			if match {
+				count++
+			}
+		}
+		if count > 0 {
+			freqs[id] = count
+		}
+	}
+	return freqs, nil
+}
+
+func containsPos(positions []int, pos int) bool {
+	for _, p := range positions {
+		if p == pos {
+			return true
+		}
+	}
+	return false
+}
+
+// score ngitung BM25 tina posting hiji kecap (docs) atanapi tina frekuensi
+// frasa (freqs).
+func (idx *Index) score(docs map[string][]int, freqs map[string]int) map[string]float64 {
+	if freqs == nil {
+		freqs = make(map[string]int, len(docs))
+		for id, positions := range docs {
+			freqs[id] = len(positions)
+			if positions == nil {
+				freqs[id] = 1
+			}
+		}
+	}
+
+	n := float64(len(idx.DocLen))
+	total := 0
+	for _, l := range idx.DocLen {
+		total += l
+	}
+	avgLen := 1.0
+	if n > 0 && total > 0 {
+		avgLen = float64(total) / n
+	}
+
+	df := float64(len(freqs))
+	idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+
+	result := make(map[string]float64, len(freqs))
+	for id, f := range freqs {
+		tf := float64(f)
+		norm := 1 - bm25B + bm25B*float64(idx.DocLen[id])/avgLen
+		result[id] = idf * tf * (bm25K1 + 1) / (tf + bm25K1*norm)
+	}
+	return result
+}
+
+// rank ngaruntuykeun hasil ti skor pangluhurna; skor sami diruntuykeun
+// dumasar ID baris sangkan hasilna tetep.
+func rank(scores map[string]float64) []Hit {
+	hits := make([]Hit, 0, len(scores))
+	for id, s := range scores {
+		hits = append(hits, Hit{RowID: id, Score: s})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].RowID < hits[j].RowID
+	})
+	return hits
+}
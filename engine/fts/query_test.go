@@ -0,0 +1,133 @@
+package fts
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// testIndex ngawangun indeks dina mémori tina docs (rowID → eusi).
+func testIndex(t *testing.T, spec string, docs map[string]string) *Index {
+	t.Helper()
+	an, err := NewAnalyzer(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx := newIndex(an)
+	for id, text := range docs {
+		idx.add(id, text)
+	}
+	return idx
+}
+
+// search ngajalankeun query sapertos FTSManager.Search, tanpa file.
+func search(idx *Index, query string, fuzzy int) (*Result, error) {
+	q, err := parseQuery(query, idx.analyzer)
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{Hits: []Hit{}, terms: make(map[string]bool), analyzer: idx.analyzer}
+	if q == nil {
+		return result, nil
+	}
+	scores, err := q.eval(&evalContext{idx: idx, fuzzy: fuzzy, matched: result.terms})
+	if err != nil {
+		return nil, err
+	}
+	result.Hits = rank(scores)
+	return result, nil
+}
+
+func hitIDs(r *Result) []string {
+	ids := []string{}
+	for _, h := range r.Hits {
+		ids = append(ids, h.RowID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+var leuweungDocs = map[string]string{
+	"1": "maung lumpat ka leuweung",
+	"2": "maung bodas sare di leuweung",
+	"3": "uncal lumpat gancang pisan",
+	"4": "leuweung geledegan",
+}
+
+func TestQuery(t *testing.T) {
+	idx := testIndex(t, "standar", leuweungDocs)
+	cases := []struct {
+		query string
+		want  []string
+	}{
+		{"maung", []string{"1", "2"}},
+		{"MAUNG", []string{"1", "2"}},
+		{"maung lumpat", []string{"1"}},
+		{"maung SARENG lumpat", []string{"1"}},
+		{"maung ATAWA uncal", []string{"1", "2", "3"}},
+		{"leuweung SANES maung", []string{"4"}},
+		{"leuweung -bodas", []string{"1", "4"}},
+		{"NOT maung", []string{"3", "4"}},
+		{`"maung lumpat"`, []string{"1"}},
+		{`"lumpat maung"`, []string{}},
+		{"'sare di leuweung'", []string{"2"}},
+		{"leuw*", []string{"1", "2", "4"}},
+		{"(maung ATAWA uncal) lumpat", []string{"1", "3"}},
+		{"hayam", []string{}},
+		{"ka", []string{}}, // kecap ≤ 2 hurup dipiceun ku analyzer standar
+	}
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			r, err := search(idx, c.query, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hitIDs(r); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("hasil = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	idx := testIndex(t, "standar", leuweungDocs)
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{`"maung lumpat`, "kutip"},
+		{"(maung lumpat", "kurung"},
+		{"maung)", "teu valid"},
+		{"maung SANES", "SANES"},
+	}
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			_, err := search(idx, c.query, 0)
+			if err == nil || !strings.Contains(err.Error(), c.want) {
+				t.Errorf("err = %v, want ngandung %q", err, c.want)
+			}
+		})
+	}
+}
+
+// TestRanking: BM25 ngutamakeun baris nu kecapna langkung sering sareng
+// barisna langkung pondok.
+func TestRanking(t *testing.T) {
+	idx := testIndex(t, "standar", map[string]string{
+		"panjang": "maung aya di tengah leuweung anu jero pisan sareng poek",
+		"pondok":  "maung leuweung",
+		"sering":  "maung maung maung leuweung",
+	})
+	r, err := search(idx, "maung", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, h := range r.Hits {
+		got = append(got, h.RowID)
+	}
+	if want := []string{"sering", "pondok", "panjang"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("urutan = %v, want %v", got, want)
+	}
+}
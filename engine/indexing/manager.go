@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 
@@ -83,6 +84,38 @@ func (im *IndexManager) Lookup(tableName, colName, value string) ([]string, erro
 	return pks, nil
 }
 
+// LoadIndex: Maca sakabéh peta index kolom (dipaké ku optimizer JOIN)
+func (im *IndexManager) LoadIndex(tableName, colName string) (IndexMap, error) {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	return im.loadIndexFile(tableName, colName)
+}
+
+// SortedKeys: sadaya nilai index dirunut per bait, pikeun scan rentang.
+func (m IndexMap) SortedKeys() []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// PrefixLookup: PK sadaya nilai nu dimimitian ku prefix (JIGA 'abc%').
+// Konci dirunut heula, awal rentang dipilarian ku binary search.
+func (m IndexMap) PrefixLookup(prefix string) []string {
+	keys := m.SortedKeys()
+	var pks []string
+	for _, k := range keys[sort.SearchStrings(keys, prefix):] {
+		if !strings.HasPrefix(k, prefix) {
+			break
+		}
+		pks = append(pks, m[k]...)
+	}
+	return pks
+}
+
 func (im *IndexManager) UpdateIndexOnInsert(tableName string, rowData string, schemaCols []string) {
 	dbPath := storage.GetDBPath()
 	if dbPath == "" { return }
@@ -206,4 +239,40 @@ func (im *IndexManager) loadIndexFile(tableName, colName string) (IndexMap, erro
 		return nil, err
 	}
 	return data, nil
-}
\ No newline at end of file
+}
+
+// ==========================================
+// 2. MAINTENANCE (ROBIH TABEL / PICEUN)
+// ==========================================
+
+// IndexedColumns: daptar kolom tabel nu boga file .idx
+func (im *IndexManager) IndexedColumns(tableName string, schemaCols []string) []string {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	var cols []string
+	for _, c := range schemaCols {
+		if _, err := os.Stat(getIndexPath(tableName, c)); err == nil {
+			cols = append(cols, c)
+		}
+	}
+	return cols
+}
+
+func (im *IndexManager) RenameIndex(oldTable, oldCol, newTable, newCol string) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	return os.Rename(getIndexPath(oldTable, oldCol), getIndexPath(newTable, newCol))
+}
+
+func (im *IndexManager) DropIndex(tableName, colName string) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	err := os.Remove(getIndexPath(tableName, colName))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
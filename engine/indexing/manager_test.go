@@ -0,0 +1,95 @@
+package indexing
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/febrd/maungdb/engine/storage"
+	"github.com/febrd/maungdb/internal/config"
+)
+
+func TestPrefixLookup(t *testing.T) {
+	idx := IndexMap{
+		"Bandung":  {"1", "4"},
+		"Bandung%": {"7"},
+		"Banjar":   {"2"},
+		"Bogor":    {"3"},
+		"bandung":  {"5"},
+		"":         {"6"},
+	}
+
+	if got, want := idx.SortedKeys(), []string{"", "Bandung", "Bandung%", "Banjar", "Bogor", "bandung"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SortedKeys = %q, want %q", got, want)
+	}
+
+	cases := []struct {
+		prefix string
+		want   []string
+	}{
+		{"Band", []string{"1", "4", "7"}},
+		{"Ban", []string{"1", "4", "7", "2"}},
+		{"Bandung%", []string{"7"}},
+		{"B", []string{"1", "4", "7", "2", "3"}},
+		{"band", []string{"5"}},
+		{"Cianjur", nil},
+		{"Z", nil},
+		{"", []string{"6", "1", "4", "7", "2", "3", "5"}},
+	}
+	for _, c := range cases {
+		if got := idx.PrefixLookup(c.prefix); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("PrefixLookup(%q) = %q, want %q", c.prefix, got, c.want)
+		}
+	}
+}
+
+// TestIndexFiles: file .idx ngiring ROBIH TABEL (ganti ngaran) sareng PICEUN.
+func TestIndexFiles(t *testing.T) {
+	dataDir, activeDB := config.DataDir, storage.ActiveDB
+	config.DataDir, storage.ActiveDB = t.TempDir(), "uji"
+	defer func() { config.DataDir, storage.ActiveDB = dataDir, activeDB }()
+	if err := os.MkdirAll(storage.GetDBPath(), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	im := &IndexManager{}
+	cols := []string{"id", "ngaran", "kota"}
+	for _, c := range []string{"ngaran", "kota"} {
+		if err := im.saveIndexFile("warga", c, IndexMap{"x": {"1"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	steps := []struct {
+		name  string
+		apply func() error
+		table string
+		cols  []string
+		want  []string
+	}{
+		{"awal", nil, "warga", cols, []string{"ngaran", "kota"}},
+		{"ganti ngaran kolom", func() error { return im.RenameIndex("warga", "kota", "warga", "dayeuh") },
+			"warga", []string{"id", "ngaran", "dayeuh"}, []string{"ngaran", "dayeuh"}},
+		{"ganti ngaran tabel", func() error { return im.RenameIndex("warga", "ngaran", "penduduk", "ngaran") },
+			"penduduk", cols, []string{"ngaran"}},
+		{"piceun", func() error { return im.DropIndex("penduduk", "ngaran") }, "penduduk", cols, nil},
+		{"piceun nu teu aya", func() error { return im.DropIndex("penduduk", "ngaran") }, "penduduk", cols, nil},
+	}
+	for _, s := range steps {
+		t.Run(s.name, func(t *testing.T) {
+			if s.apply != nil {
+				if err := s.apply(); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := im.IndexedColumns(s.table, s.cols); !reflect.DeepEqual(got, s.want) {
+				t.Errorf("IndexedColumns(%s) = %v, want %v", s.table, got, s.want)
+			}
+		})
+	}
+
+	idx, err := im.LoadIndex("warga", "dayeuh")
+	if err != nil || !reflect.DeepEqual(idx, IndexMap{"x": {"1"}}) {
+		t.Errorf("eusi index saatos ganti ngaran = %v, %v", idx, err)
+	}
+}
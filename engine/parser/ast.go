@@ -13,6 +13,13 @@ const (
 	CmdCreateView CommandType = "CREATE_VIEW"
 	CmdShowDB 	CommandType = "SHOW_DB"
 	CmdCreateTrigger CommandType = "CREATE_TRIGGER"
+	CmdAlterTable CommandType = "ALTER_TABLE"
+	CmdDrop       CommandType = "DROP"
+	CmdTruncate   CommandType = "TRUNCATE"
+	CmdCheckFK    CommandType = "CHECK_FK"
+	CmdShowReplication CommandType = "SHOW_REPLICATION"
+	CmdSetSync         CommandType = "SET_SYNC"
+	CmdReject          CommandType = "REJECT"
 )
 
 type JoinClause struct {
@@ -32,6 +39,8 @@ type Command struct {
 	Joins 	[]JoinClause
 	OrderBy   string 
 	OrderDesc bool   
+	Distinct  bool   // TINGALI BEDA / DISTINCT
+	Collation string // KOLASI / COLLATE <x>: nimpah kolasi kolom dina query ieu
 	Limit     int   
 	Offset    int  
 	
@@ -42,6 +51,14 @@ type Command struct {
 
 	ViewQuery string
 	TriggerDef TriggerDefinition
+	Alter      AlterDefinition
+	Drop       DropDefinition
+	Insert     InsertDefinition
+	Returning  []string // BALIKKEUN / RETURNING: kolom baris nu kapangaruhan ("*" = sadaya)
+	Sync       *SyncDefinition // ATUR SINKRON / MIMITIAN SINKRON
+	Search     *SearchOptions  // pilihan KOREHAN (SAMAR, SOROT)
+	System     bool            // dijalankeun ku runner internal (maung migrate), sanés tina query
+	TriggerDepth int           // jero jarambah nu micu paréntah ieu (0 = ti pangguna)
 
 	Column string
 }
@@ -51,11 +68,115 @@ type Condition struct {
 	Operator string
 	Value    string
 	LogicOp  string
+	Escape   string // karakter ESCAPE pikeun JIGA (default: \)
 }
 
+// Operator tés NULL (IS NULL / KOSONG, IS NOT NULL / TEU KOSONG).
+const (
+	OpIsNull    = "IS NULL"
+	OpIsNotNull = "IS NOT NULL"
+)
+
+// OpSearch: MILARI(kolom, 'query') dina DIMANA. Field = kolom, Value = query
+// KOREHAN (kecap, 'frasa', ATAWA, SANES, awalan*).
+const OpSearch = "MILARI"
+
+// SearchOptions: pilihan KOREHAN. Fuzzy = jarak édit maksimal kanggo unggal
+// kecap (0 = kedah pas). Highlight nambihan kolom cuplikan téks nu cocog,
+// kecap nu kapendak diapit ku Pre/Post, panjangna Fragment karakter
+// (kosong = default).
+type SearchOptions struct {
+	Fuzzy     int
+	Highlight bool
+	Pre       string
+	Post      string
+	Fragment  int
+}
+
+// Jarak SAMAR: default mun teu disebatkeun, sareng maksimalna.
+const (
+	DefaultFuzzy = 2
+	MaxFuzzy     = 3
+)
+
 type TriggerDefinition struct {
     Name     string
     Event    string
     Table    string
+    Timing   string // BEFORE / AFTER (kosong = AFTER)
     ActionQL string
 }
+
+// Waktos jarambah dina DAMEL JARAMBAH ... WAKTU [SAMEMEH|SANGGEUS] <event>.
+var triggerTimings = map[string]string{
+	"SAMEMEH": "BEFORE", "SATEUACAN": "BEFORE", "BEFORE": "BEFORE",
+	"SANGGEUS": "AFTER", "SAATOS": "AFTER", "AFTER": "AFTER",
+}
+
+
+// AlterDefinition: hasil parse ROBIH TABEL (ALTER TABLE)
+type AlterDefinition struct {
+	Action     string
+	Column     string
+	NewName    string
+	Definition string
+	Default    string
+	HasDefault bool
+	Constraint string
+}
+
+const (
+	AlterAddColumn      = "ADD_COLUMN"
+	AlterDropColumn     = "DROP_COLUMN"
+	AlterRenameColumn   = "RENAME_COLUMN"
+	AlterRenameTable    = "RENAME_TABLE"
+	AlterRetypeColumn   = "RETYPE_COLUMN"
+	AlterAddConstraint  = "ADD_CONSTRAINT"
+	AlterDropConstraint = "DROP_CONSTRAINT"
+)
+// DropDefinition: hasil parse PICEUN TABEL/PANGKAL/KACA/JARAMBAH
+type DropDefinition struct {
+	Object   string
+	Name     string
+	Table    string // ngan pikeun JARAMBAH: ... PADA <tabel>
+	IfExists bool
+}
+
+const (
+	DropTable    = "TABLE"
+	DropDatabase = "DATABASE"
+	DropView     = "VIEW"
+	DropTrigger  = "TRIGGER"
+)
+
+// InsertDefinition: hasil parse SIMPEN nu nganggo daptar kolom, NILAI
+// (sababaraha baris) atanapi SIMPEN ... TINGALI. Mun Rows sareng Select
+// kosong, Command.Data (format a|b|c) nu dipaké.
+type InsertDefinition struct {
+	Columns    []string
+	Rows       [][]string
+	Select     *Command
+	OnConflict *ConflictClause
+}
+
+// SyncDefinition: réplikasi sinkron. Commit ngantosan dugi ka Replicas
+// ANAK nampi parobahanana, paling lami Timeout detik (0 = default).
+// OnTimeout: SyncContinue (teraskeun asinkron) atanapi SyncFail.
+type SyncDefinition struct {
+	Replicas  int
+	Timeout   float64
+	OnTimeout string
+}
+
+const (
+	SyncContinue = "TULUY"
+	SyncFail     = "GAGAL"
+)
+
+// ConflictClause: SIMPEN ... MUN AYA (ON CONFLICT). Nilai "ANYAR.kolom"
+// (EXCLUDED.kolom) dina Updates nyandak nilai tina baris nu badé disimpen.
+type ConflictClause struct {
+	Columns   []string // kosong = sadaya kolom PK/UNIQUE
+	DoNothing bool
+	Updates   map[string]string
+}
@@ -0,0 +1,65 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseDrop(t *testing.T) {
+	tests := []struct {
+		query string
+		want  DropDefinition
+	}{
+		{"PICEUN TABEL warga", DropDefinition{Object: DropTable, Name: "warga"}},
+		{"DROP TABLE IF EXISTS warga", DropDefinition{Object: DropTable, Name: "warga", IfExists: true}},
+		{"PICEUN PANGKAL MUN AYA arsip", DropDefinition{Object: DropDatabase, Name: "arsip", IfExists: true}},
+		{"PICEUN KACA warga_aktif", DropDefinition{Object: DropView, Name: "warga_aktif"}},
+		{"PICEUN JARAMBAH audit PADA warga", DropDefinition{Object: DropTrigger, Name: "audit", Table: "warga"}},
+		{"DROP TRIGGER audit ON warga", DropDefinition{Object: DropTrigger, Name: "audit", Table: "warga"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			cmd, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.query, err)
+			}
+			if cmd.Type != CmdDrop || cmd.Drop != tt.want {
+				t.Errorf("Parse(%q) = %v %+v, hoyong DROP %+v", tt.query, cmd.Type, cmd.Drop, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTruncate(t *testing.T) {
+	for _, q := range []string{"KOSONGKEUN warga", "KOSONGKEUN TABEL warga", "TRUNCATE TABLE warga"} {
+		cmd, err := Parse(q)
+		if err != nil || cmd.Type != CmdTruncate || cmd.Table != "warga" {
+			t.Errorf("Parse(%q) = %+v, %v", q, cmd, err)
+		}
+	}
+}
+
+func TestParseDropErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"PICEUN TABEL", "format: PICEUN"},
+		{"PICEUN TABEL MUN AYA", "format: PICEUN"},
+		{"PICEUN TABEL warga PADA x", "format: PICEUN"},
+		{"PICEUN JARAMBAH audit DINA warga", "PADA <tabel>"},
+		{"DROP INDEX warga", "format: DROP"},
+		{"KOSONGKEUN", "format: KOSONGKEUN"},
+		{"KOSONGKEUN warga arsip", "format: KOSONGKEUN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := Parse(tt.query)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Parse(%q) err = %v, hoyong nu ngandung %q", tt.query, err, tt.want)
+			}
+		})
+	}
+}
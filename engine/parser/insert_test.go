@@ -0,0 +1,107 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseInsert(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		table   string
+		columns []string
+		rows    [][]string
+		data    string
+		sel     bool
+	}{
+		{
+			name:  "hiji baris",
+			query: "SIMPEN warga NILAI (1, 'Asep')",
+			table: "warga",
+			rows:  [][]string{{"1", "Asep"}},
+		},
+		{
+			name:    "sababaraha baris sareng kolom",
+			query:   "SIMPEN warga (id, nama) NILAI (1, 'Asep'), (2, 'Ujang, Jr.')",
+			table:   "warga",
+			columns: []string{"id", "nama"},
+			rows:    [][]string{{"1", "Asep"}, {"2", "Ujang, Jr."}},
+		},
+		{
+			name:  "VALUES sareng petik ganda",
+			query: "INSERT warga VALUES (1, 'Jang''s'), (2, \"x)y\")",
+			table: "warga",
+			rows:  [][]string{{"1", "Jang's"}, {"2", "x)y"}},
+		},
+		{
+			name:  "NULL sareng string kosong diantep",
+			query: "SIMPEN warga NILAI (1, NULL, '')",
+			table: "warga",
+			rows:  [][]string{{"1", "NULL", "''"}},
+		},
+		{
+			name:    "tina TINGALI",
+			query:   "SIMPEN arsip (id, nama) TINGALI id, nama TI warga",
+			table:   "arsip",
+			columns: []string{"id", "nama"},
+			sel:     true,
+		},
+		{
+			name:  "format pipa heubeul",
+			query: "SIMPEN warga 1|Asep",
+			table: "warga",
+			data:  "1|Asep",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.query, err)
+			}
+			if cmd.Type != CmdInsert || cmd.Table != tt.table {
+				t.Fatalf("type/table = %v/%q, hoyong INSERT/%q", cmd.Type, cmd.Table, tt.table)
+			}
+			if !reflect.DeepEqual(cmd.Insert.Columns, tt.columns) {
+				t.Errorf("columns = %q, hoyong %q", cmd.Insert.Columns, tt.columns)
+			}
+			if !reflect.DeepEqual(cmd.Insert.Rows, tt.rows) {
+				t.Errorf("rows = %q, hoyong %q", cmd.Insert.Rows, tt.rows)
+			}
+			if cmd.Data != tt.data {
+				t.Errorf("data = %q, hoyong %q", cmd.Data, tt.data)
+			}
+			if got := cmd.Insert.Select != nil; got != tt.sel {
+				t.Errorf("select = %v, hoyong %v", got, tt.sel)
+			} else if got && cmd.Insert.Select.Type != CmdSelect {
+				t.Errorf("select type = %v", cmd.Insert.Select.Type)
+			}
+		})
+	}
+}
+
+func TestParseInsertErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"SIMPEN warga (id, id) NILAI (1, 2)", "dua kali"},
+		{"SIMPEN warga (id, ) NILAI (1, 2)", "kolom"},
+		{"SIMPEN warga NILAI (1, 'Asep'", "teu ditutup"},
+		{"SIMPEN warga NILAI (1) x (2)", "format NILAI salah"},
+		{"SIMPEN arsip TINGALI * TI", "sanggeus TI"},
+		{"SIMPEN warga", "format simpen salah"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := Parse(tt.query)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Parse(%q) err = %v, hoyong nu ngandung %q", tt.query, err, tt.want)
+			}
+		})
+	}
+}
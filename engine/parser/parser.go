@@ -2,23 +2,60 @@ package parser
 
 import (
 	"errors"
+	"fmt"
 	"strconv"
 	"strings"
 	"regexp"
+
+	"github.com/febrd/maungdb/engine/schema"
 )
 
 var (
   
-    ReIndung = regexp.MustCompile(`(?i)^JADI\s+INDUNG`)
+    ReIndung = regexp.MustCompile(`(?i)^JADI\s+INDUNG(?:\s+(PAKSA|FORCE)\b)?`)
     ReAnak   = regexp.MustCompile(`(?i)^JADI\s+ANAK\s+NGINTIL\s+(.+)`)
 
-    ReCreateFTS = regexp.MustCompile(`(?i)^DAMEL\s+INDEKS_TEKS\s+(\w+)\s+DINA\s+(\w+)`)
-    ReFTS = regexp.MustCompile(`(?i)^KOREHAN\s+(\w+)\s+DINA\s+(\w+)\s+MILARI\s+"(.+)"`)
+    ReCreateFTS = regexp.MustCompile(`(?i)^DAMEL\s+INDEKS_TEKS\s+(\w+)\s+DINA\s+(\w+)(?:\s+(?:NGANGGO|USING)\s+(\w+)(?:\s*\(?\s*(\d+)\s*\)?)?)?\s*$`)
+    ReFTS = regexp.MustCompile(`(?is)^KOREHAN\s+(\w+)\s+DINA\s+(\w+)\s+MILARI\s+"(.+)"(.*)$`)
+    reSearch = regexp.MustCompile(`(?i)\b(?:MILARI|MATCH)\s*\(\s*([\w.]+)\s*,\s*(?:'([^']*)'|"([^"]*)")\s*\)`)
+
+    reInsert = regexp.MustCompile(`(?s)^\S+\s+(\S+)\s+(.+)$`)
+    reInsertValues = regexp.MustCompile(`(?is)^\S+\s+([^\s(]+)\s*(?:\(([^)]*)\))?\s*(?:NILAI|VALUES)\s*(\(.*)$`)
+    reInsertSelect = regexp.MustCompile(`(?is)^\S+\s+([^\s(]+)\s*(?:\(([^)]*)\))?\s*((?:TINGALI|TENJO|SELECT)\s.*)$`)
 )
 
+// returningVerbs: paréntah nu tiasa ditungtungan ku BALIKKEUN / RETURNING.
+var returningVerbs = map[string]bool{
+    "SIMPEN": true, "TENDEUN": true, "INSERT": true,
+    "OMEAN": true, "ROBIH": true, "UPDATE": true,
+    "MICEUN": true, "PICEUN": true, "DELETE": true,
+}
+
 func Parse(query string) (*Command, error) {
+    query = strings.TrimSuffix(strings.TrimSpace(query), ";")
+
+    var returning []string
+    if fields := strings.Fields(query); len(fields) > 0 && returningVerbs[strings.ToUpper(fields[0])] {
+        var err error
+        if query, returning, err = splitReturning(query); err != nil {
+            return nil, err
+        }
+    }
+
+    cmd, err := parseQuery(query)
+    if err != nil || returning == nil {
+        return cmd, err
+    }
+    if cmd.Type != CmdInsert && cmd.Type != CmdUpdate && cmd.Type != CmdDelete {
+        return nil, errors.New("BALIKKEUN ngan kanggo SIMPEN, OMEAN sareng MICEUN")
+    }
+    cmd.Returning = returning
+    return cmd, nil
+}
+
+func parseQuery(query string) (*Command, error) {
     query = strings.TrimSpace(query)
-    query = strings.TrimSuffix(query, ";")
+    raw := query
 
       query = normalizeQuery(query)
 
@@ -31,10 +68,28 @@ func Parse(query string) (*Command, error) {
 
     switch verb {
     case "MIMITIAN", "BEGIN", "JADIKEUN", "COMMIT", "BATALKEUN", "ROLLBACK":
-        return &Command{
+        cmd := &Command{
             Type: CmdTransaction,
             Arg1: verb,
-        }, nil
+        }
+        if (verb == "MIMITIAN" || verb == "BEGIN") && len(tokens) > 1 {
+            sync, err := parseSync(tokens[1:])
+            if err != nil {
+                return nil, err
+            }
+            cmd.Sync = sync
+        }
+        return cmd, nil
+
+    case "ATUR", "SET":
+        if len(tokens) < 2 {
+            return nil, errors.New("format: ATUR SINKRON <n> [TUNGGU <detik>] [TULUY|GAGAL]")
+        }
+        sync, err := parseSync(tokens[1:])
+        if err != nil {
+            return nil, err
+        }
+        return &Command{Type: CmdSetSync, Sync: sync}, nil
 
     case "DAMEL", "BIKIN", "NYIEUN", "SCHEMA":
 
@@ -47,9 +102,9 @@ func Parse(query string) (*Command, error) {
 
 		if len(tokens) > 1 && strings.ToUpper(tokens[1]) == "INDEKS_TEKS" {
             if matches := ReCreateFTS.FindStringSubmatch(query); len(matches) > 2 {
-                return &Command{Type: "CREATE_FTS", Table: matches[1], Column: matches[2]}, nil
+                return createFTSCommand(matches), nil
             }
-            return nil, errors.New("format DAMEL INDEKS_TEKS salah. Conto: DAMEL INDEKS_TEKS buku DINA judul")
+            return nil, errors.New("format DAMEL INDEKS_TEKS salah. Conto: DAMEL INDEKS_TEKS buku DINA judul [NGANGGO indonesia|sunda|basajan|kecap|ngram 3]")
         }
 
         if len(tokens) > 1 && strings.ToUpper(tokens[1]) == "CREATE" {
@@ -58,7 +113,7 @@ func Parse(query string) (*Command, error) {
         return parseCreate(tokens[1:])
         
     case "SIMPEN", "TENDEUN", "INSERT":
-        return parseInsert(tokens)
+        return parseInsert(raw)
         
     case "TINGALI", "TENJO", "SELECT":
 		if len(tokens) > 1 {
@@ -66,19 +121,47 @@ func Parse(query string) (*Command, error) {
 			if token2 == "PANGKAL" || token2 == "DATABASES" {
 				return &Command{Type: CmdShowDB}, nil
 			}
+			if len(tokens) == 2 && (token2 == "REPLIKASI" || token2 == "REPLICATION") {
+				return &Command{Type: CmdShowReplication}, nil
+			}
 		}
-        return parseSelect(tokens)
+        return parseSelectWithSearch(raw, tokens)
         
-    case "OMEAN", "ROBIH", "UPDATE":
+    case "OMEAN", "ROBIH", "UPDATE", "ALTER":
+        if len(tokens) > 1 && (strings.ToUpper(tokens[1]) == "TABEL" || strings.ToUpper(tokens[1]) == "TABLE") {
+            return parseAlterTable(tokens)
+        }
         return parseUpdate(tokens)
         
-    case "MICEUN", "PICEUN", "DELETE":
+    case "MICEUN", "PICEUN", "DELETE", "DROP":
+        if len(tokens) > 1 && dropObjects[strings.ToUpper(tokens[1])] != "" {
+            return parseDrop(tokens)
+        }
+        if verb == "DROP" {
+            return nil, errors.New("format: DROP TABLE|DATABASE|VIEW|TRIGGER [IF EXISTS] <ngaran>")
+        }
         return parseDelete(tokens)
+
+    case "KOSONGKEUN", "TRUNCATE":
+        return parseTruncate(tokens)
+
+    case "TOLAK", "REJECT":
+        return parseReject(raw)
+
+    case "PARIKSA", "CHECK":
+        if len(tokens) < 2 || len(tokens) > 3 || strings.ToUpper(tokens[1]) != "FK" {
+            return nil, errors.New("format: PARIKSA FK [tabel]")
+        }
+        cmd := &Command{Type: CmdCheckFK}
+        if len(tokens) == 3 {
+            cmd.Table = tokens[2]
+        }
+        return cmd, nil
 	case "TANDAIN", "TANDAAN", "TAWISAN":
 		return parseIndex(tokens)
 
 	case "KOREHAN", "JADI", "JANTEN":
-		return ParseCommand(query)
+		return ParseCommand(raw)
     default:
         return nil, errors.New("paréntah teu dikenal: " + verb)
     }
@@ -104,8 +187,12 @@ func normalizeQuery(query string) string {
 func ParseCommand(input string) (*Command, error) {
     input = strings.TrimSpace(input)
 
-    if ReIndung.MatchString(input) {
-        return &Command{Type: "JADI_INDUNG"}, nil
+    if matches := ReIndung.FindStringSubmatch(input); matches != nil {
+        cmd := &Command{Type: "JADI_INDUNG"}
+        if matches[1] != "" {
+            cmd.Arg1 = "PAKSA"
+        }
+        return cmd, nil
     }
 
     if matches := ReAnak.FindStringSubmatch(input); len(matches) > 1 {
@@ -113,29 +200,330 @@ func ParseCommand(input string) (*Command, error) {
     }
 
     if matches := ReCreateFTS.FindStringSubmatch(input); len(matches) > 2 {
-        return &Command{Type: "CREATE_FTS", Table: matches[1], Column: matches[2]}, nil
+        return createFTSCommand(matches), nil
     }
 
-    if matches := ReFTS.FindStringSubmatch(input); len(matches) > 3 {
+    if matches := ReFTS.FindStringSubmatch(input); len(matches) > 4 {
+        opts, err := parseSearchOptions(matches[4])
+        if err != nil {
+            return nil, err
+        }
         return &Command{
             Type: "KOREHAN", 
             Table: matches[1], 
             Column: matches[2], 
             Arg1: matches[3],
+            Search: opts,
         }, nil
     }
 
     return nil, nil
 }
+
+// createFTSCommand: DAMEL INDEKS_TEKS <tabel> DINA <kolom> [NGANGGO <analyzer> [n]].
+// Analyzer disimpen dina Arg1 (mis. "ngram:3"), kosong = standar.
+func createFTSCommand(matches []string) *Command {
+    cmd := &Command{Type: "CREATE_FTS", Table: matches[1], Column: matches[2], Arg1: matches[3]}
+    if matches[4] != "" {
+        cmd.Arg1 += ":" + matches[4]
+    }
+    return cmd
+}
+
+// parseSearchOptions: pilihan saatos query KOREHAN,
+// [SAMAR|FUZZY [n]] [SOROT|HIGHLIGHT ['awal' 'tungtung']] [PANJANG|LENGTH n].
+// Tanda sorot kedah dina petik tunggal. Mulihkeun nil mun teu aya pilihan.
+func parseSearchOptions(tail string) (*SearchOptions, error) {
+	var tokens []string
+	for _, t := range splitQuoted(strings.TrimSpace(tail), ' ') {
+		if t != "" {
+			tokens = append(tokens, t)
+		}
+	}
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	isQuoted := func(i int) bool {
+		return i < len(tokens) && len(tokens[i]) >= 2 && strings.HasPrefix(tokens[i], "'") && strings.HasSuffix(tokens[i], "'")
+	}
+	number := func(i int) (int, bool) {
+		if i >= len(tokens) {
+			return 0, false
+		}
+		n, err := strconv.Atoi(tokens[i])
+		return n, err == nil
+	}
+
+	opts := &SearchOptions{}
+	for i := 0; i < len(tokens); i++ {
+		switch strings.ToUpper(tokens[i]) {
+		case "SAMAR", "FUZZY":
+			opts.Fuzzy = DefaultFuzzy
+			if n, ok := number(i + 1); ok {
+				if n < 1 || n > MaxFuzzy {
+					return nil, fmt.Errorf("jarak SAMAR kedah 1 dugi ka %d", MaxFuzzy)
+				}
+				opts.Fuzzy = n
+				i++
+			}
+		case "SOROT", "HIGHLIGHT":
+			opts.Highlight = true
+			if isQuoted(i + 1) {
+				if !isQuoted(i + 2) {
+					return nil, errors.New("SOROT butuh dua tanda: SOROT '<awal>' '<tungtung>'")
+				}
+				opts.Pre = tokens[i+1][1 : len(tokens[i+1])-1]
+				opts.Post = tokens[i+2][1 : len(tokens[i+2])-1]
+				i += 2
+			}
+		case "PANJANG", "LENGTH":
+			n, ok := number(i + 1)
+			if !ok || n < 1 {
+				return nil, errors.New("PANJANG cuplikan kedah angka positip")
+			}
+			opts.Highlight = true
+			opts.Fragment = n
+			i++
+		default:
+			return nil, fmt.Errorf("pilihan KOREHAN '%s' teu dikenal (SAMAR, SOROT, PANJANG)", tokens[i])
+		}
+	}
+	return opts, nil
+}
+
+// parseSync: SINKRON|SYNC <n> [TUNGGU|TIMEOUT <detik>] [TULUY|CONTINUE|GAGAL|FAIL]
+func parseSync(tokens []string) (*SyncDefinition, error) {
+	usage := errors.New("format: SINKRON <n> [TUNGGU <detik>] [TULUY|GAGAL]")
+	if len(tokens) < 2 {
+		return nil, usage
+	}
+	if kw := strings.ToUpper(tokens[0]); kw != "SINKRON" && kw != "SYNC" {
+		return nil, usage
+	}
+
+	n, err := strconv.Atoi(tokens[1])
+	if err != nil || n < 0 {
+		return nil, errors.New("jumlah ANAK SINKRON kedah angka 0 atanapi langkung")
+	}
+	def := &SyncDefinition{Replicas: n}
+
+	for i := 2; i < len(tokens); i++ {
+		switch strings.ToUpper(tokens[i]) {
+		case "TUNGGU", "TIMEOUT":
+			if i+1 >= len(tokens) {
+				return nil, usage
+			}
+			i++
+			secs, err := strconv.ParseFloat(tokens[i], 64)
+			if err != nil || secs <= 0 {
+				return nil, errors.New("TUNGGU kedah jumlah detik nu positip")
+			}
+			def.Timeout = secs
+		case "TULUY", "CONTINUE":
+			def.OnTimeout = SyncContinue
+		case "GAGAL", "FAIL":
+			def.OnTimeout = SyncFail
+		default:
+			return nil, fmt.Errorf("kecap '%s' teu dikenal dina SINKRON", tokens[i])
+		}
+	}
+	return def, nil
+}
+
+var dropObjects = map[string]string{
+	"TABEL": DropTable, "TABLE": DropTable,
+	"PANGKAL": DropDatabase, "DATABASE": DropDatabase,
+	"KACA": DropView, "VIEW": DropView,
+	"JARAMBAH": DropTrigger, "TRIGGER": DropTrigger,
+}
+
+// parseDrop: PICEUN <TABEL|PANGKAL|KACA|JARAMBAH> [MUN AYA] <ngaran> [PADA <tabel>]
+func parseDrop(tokens []string) (*Command, error) {
+	usage := errors.New("format: PICEUN TABEL|PANGKAL|KACA|JARAMBAH [MUN AYA] <ngaran>")
+
+	def := DropDefinition{Object: dropObjects[strings.ToUpper(tokens[1])]}
+	rest := tokens[2:]
+
+	if len(rest) >= 2 {
+		first, second := strings.ToUpper(rest[0]), strings.ToUpper(rest[1])
+		if (first == "MUN" && second == "AYA") || (first == "IF" && second == "EXISTS") {
+			def.IfExists = true
+			rest = rest[2:]
+		}
+	}
+	if len(rest) == 0 {
+		return nil, usage
+	}
+	def.Name = rest[0]
+	rest = rest[1:]
+
+	if len(rest) > 0 {
+		if def.Object != DropTrigger || len(rest) != 2 {
+			return nil, usage
+		}
+		if u := strings.ToUpper(rest[0]); u != "PADA" && u != "ON" {
+			return nil, errors.New("format: PICEUN JARAMBAH <ngaran> PADA <tabel>")
+		}
+		def.Table = rest[1]
+	}
+
+	return &Command{Type: CmdDrop, Table: def.Name, Drop: def}, nil
+}
+
+// parseTruncate: KOSONGKEUN [TABEL] <tabel>
+func parseTruncate(tokens []string) (*Command, error) {
+	rest := tokens[1:]
+	if len(rest) > 0 {
+		if u := strings.ToUpper(rest[0]); u == "TABEL" || u == "TABLE" {
+			rest = rest[1:]
+		}
+	}
+	if len(rest) != 1 {
+		return nil, errors.New("format: KOSONGKEUN [TABEL] <tabel>")
+	}
+	return &Command{Type: CmdTruncate, Table: rest[0]}, nil
+}
+
+func parseAlterTable(tokens []string) (*Command, error) {
+	usage := errors.New("format ROBIH TABEL salah. Conto: ROBIH TABEL <tabel> TAMBAH KOLOM umur:INT BAKU 0")
+	if len(tokens) < 5 {
+		return nil, usage
+	}
+
+	cmd := &Command{Type: CmdAlterTable, Table: tokens[2]}
+	rest := tokens[3:]
+	upper := func(i int) string {
+		if i < len(rest) {
+			return strings.ToUpper(rest[i])
+		}
+		return ""
+	}
+
+	switch upper(0) {
+	case "TAMBAH", "ADD":
+		if upper(1) == "KONSTRAIN" || upper(1) == "CONSTRAINT" {
+			if len(rest) < 4 {
+				return nil, errors.New("format: ROBIH TABEL <tabel> TAMBAH KONSTRAIN <kolom> <PK|UNIQUE|NOT NULL|FK(tabel.kolom)>")
+			}
+			cmd.Alter = AlterDefinition{Action: AlterAddConstraint, Column: rest[2], Constraint: strings.Join(rest[3:], " ")}
+			return cmd, nil
+		}
+
+		defIdx := 1
+		if upper(1) == "KOLOM" || upper(1) == "COLUMN" {
+			defIdx = 2
+		}
+		if defIdx >= len(rest) {
+			return nil, usage
+		}
+
+		defEnd := len(rest)
+		for i := defIdx; i < len(rest); i++ {
+			if u := strings.ToUpper(rest[i]); u == "BAKU" || u == "DEFAULT" {
+				defEnd = i
+				break
+			}
+		}
+
+		cmd.Alter = AlterDefinition{Action: AlterAddColumn, Definition: strings.Join(rest[defIdx:defEnd], " ")}
+		if defEnd < len(rest) {
+			if defEnd+1 >= len(rest) {
+				return nil, errors.New("BAKU / DEFAULT butuh nilai")
+			}
+			cmd.Alter.HasDefault = true
+			cmd.Alter.Default = strings.Trim(strings.Join(rest[defEnd+1:], " "), "'\"")
+		}
+		return cmd, nil
+
+	case "PICEUN", "DROP":
+		if upper(1) == "KONSTRAIN" || upper(1) == "CONSTRAINT" {
+			if len(rest) < 4 {
+				return nil, errors.New("format: ROBIH TABEL <tabel> PICEUN KONSTRAIN <kolom> <PK|UNIQUE|NOT NULL|FK>")
+			}
+			cmd.Alter = AlterDefinition{Action: AlterDropConstraint, Column: rest[2], Constraint: strings.Join(rest[3:], " ")}
+			return cmd, nil
+		}
+
+		colIdx := 1
+		if upper(1) == "KOLOM" || upper(1) == "COLUMN" {
+			colIdx = 2
+		}
+		if colIdx >= len(rest) {
+			return nil, usage
+		}
+		cmd.Alter = AlterDefinition{Action: AlterDropColumn, Column: rest[colIdx]}
+		return cmd, nil
+
+	case "GANTI", "RENAME":
+		idx := 1
+		if upper(0) == "GANTI" {
+			if upper(1) == "TIPE" || upper(1) == "TYPE" {
+				if len(rest) < 5 || (upper(3) != "JADI" && upper(3) != "TO") {
+					return nil, errors.New("format: ROBIH TABEL <tabel> GANTI TIPE <kolom> JADI <tipe>")
+				}
+				cmd.Alter = AlterDefinition{Action: AlterRetypeColumn, Column: rest[2], Definition: strings.Join(rest[4:], "")}
+				return cmd, nil
+			}
+			if upper(1) != "NGARAN" {
+				return nil, errors.New("sanggeus GANTI kedah NGARAN atanapi TIPE")
+			}
+			idx = 2
+		}
+
+		if u := upper(idx); u == "KOLOM" || u == "COLUMN" {
+			if idx+3 >= len(rest) || (upper(idx+2) != "JADI" && upper(idx+2) != "TO") {
+				return nil, errors.New("format: ROBIH TABEL <tabel> GANTI NGARAN KOLOM <heubeul> JADI <anyar>")
+			}
+			cmd.Alter = AlterDefinition{Action: AlterRenameColumn, Column: rest[idx+1], NewName: rest[idx+3]}
+			return cmd, nil
+		}
+
+		if (upper(idx) != "JADI" && upper(idx) != "TO") || idx+1 >= len(rest) {
+			return nil, errors.New("format: ROBIH TABEL <tabel> GANTI NGARAN JADI <ngaran_anyar>")
+		}
+		cmd.Alter = AlterDefinition{Action: AlterRenameTable, NewName: rest[idx+1]}
+		return cmd, nil
+
+	case "MODIFY", "ALTER":
+		colIdx := 1
+		if upper(1) == "KOLOM" || upper(1) == "COLUMN" {
+			colIdx = 2
+		}
+		typeIdx := colIdx + 1
+		if u := upper(typeIdx); u == "TYPE" || u == "TIPE" {
+			typeIdx++
+		}
+		if typeIdx >= len(rest) {
+			return nil, errors.New("format: ALTER TABLE <tabel> ALTER COLUMN <kolom> TYPE <tipe>")
+		}
+		cmd.Alter = AlterDefinition{Action: AlterRetypeColumn, Column: rest[colIdx], Definition: strings.Join(rest[typeIdx:], "")}
+		return cmd, nil
+	}
+
+	return nil, usage
+}
+
 func parseCreateTrigger(tokens []string) (*Command, error) {
+    usage := errors.New("syntax salah. Gunakeun: DAMEL JARAMBAH <nama> WAKTU [SAMEMEH|SANGGEUS] <event> PADA <tabel> LAKUKAN <query>")
     if len(tokens) < 8 {
-        return nil, errors.New("syntax salah. Gunakeun: DAMEL JARAMBAH <nama> WAKTU <event> PADA <tabel> LAKUKAN <query>")
+        return nil, usage
     }
 
     name := tokens[2]    
     if strings.ToUpper(tokens[3]) != "WAKTU" && strings.ToUpper(tokens[3]) != "WHEN" {
         return nil, errors.New("kedah nganggo kecap WAKTU sateuacan event")
     }
+
+    // Waktos (SAMEMEH/SANGGEUS) opsional, default SANGGEUS.
+    timing := triggerTimings[strings.ToUpper(tokens[4])]
+    if timing != "" {
+        tokens = append(tokens[:4:4], tokens[5:]...)
+        if len(tokens) < 8 {
+            return nil, usage
+        }
+    }
+
     event := strings.ToUpper(tokens[4]) 
     if strings.ToUpper(tokens[5]) != "PADA" && strings.ToUpper(tokens[5]) != "ON" {
         return nil, errors.New("kedah nganggo kecap PADA sateuacan nama tabel")
@@ -155,11 +543,32 @@ func parseCreateTrigger(tokens []string) (*Command, error) {
             Name:     name,
             Event:    event,
             Table:    table,
+            Timing:   timing,
             ActionQL: actionQL,
         },
     }, nil
 }
 
+var reReject = regexp.MustCompile(`(?is)^(?:TOLAK|REJECT)\s+'([^']*)'(?:\s+(?:LAMUN|IF)\s+(.+))?$`)
+
+// parseReject: TOLAK|REJECT '<pesen>' [LAMUN|IF <kaayaan>]. Dipaké dina
+// jarambah SAMEMEH pikeun nolak parobahan.
+func parseReject(raw string) (*Command, error) {
+    m := reReject.FindStringSubmatch(strings.TrimSpace(raw))
+    if m == nil {
+        return nil, errors.New("format: TOLAK '<pesen>' [LAMUN <kaayaan>]")
+    }
+    cmd := &Command{Type: CmdReject, Arg1: m[1]}
+    if m[2] != "" {
+        conds, err := ParseConditionExpr(m[2])
+        if err != nil {
+            return nil, err
+        }
+        cmd.Where = conds
+    }
+    return cmd, nil
+}
+
 func parseCreateView(tokens []string) (*Command, error) {
 	if len(tokens) < 5 {
 		return nil, errors.New("format salah: DAMEL KACA <nama> TINA <query>")
@@ -224,20 +633,298 @@ func parseCreate(tokens []string) (*Command, error) {
 	}, nil
 }
 
-func parseInsert(tokens []string) (*Command, error) {
-	if len(tokens) < 3 {
-		return nil, errors.New("format simpen salah: SIMPEN <table> <data>")
+// parseInsert nyandak data SIMPEN tina query atah (teu dinormalisasi),
+// sangkan nilai sapertos base64 ("...=") atanapi JSON teu robah.
+// wordPos: hiji kecap di luar tanda petik sareng posisina dina query.
+type wordPos struct {
+	text       string
+	start, end int
+}
+
+// unquotedWords mulihkeun kecap-kecap (dipisah spasi) nu teu aya di jero
+// tanda petik.
+func unquotedWords(query string) []wordPos {
+	var words []wordPos
+	var quote rune
+	start := -1
+	for i, r := range query {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+			continue
+		case r == '\'' || r == '"':
+			quote = r
+			start = -1
+			continue
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if start != -1 {
+				words = append(words, wordPos{query[start:i], start, i})
+				start = -1
+			}
+			continue
+		}
+		if start == -1 {
+			start = i
+		}
+	}
+	if start != -1 && quote == 0 {
+		words = append(words, wordPos{query[start:], start, len(query)})
+	}
+	return words
+}
+
+// splitQuoted meulah s dumasar sep di luar tanda petik.
+func splitQuoted(s string, sep rune) []string {
+	var parts []string
+	var current strings.Builder
+	var quote rune
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == sep:
+			parts = append(parts, current.String())
+			current.Reset()
+			continue
+		}
+		current.WriteRune(r)
+	}
+	return append(parts, current.String())
+}
+
+// splitReturning misahkeun "... BALIKKEUN a, b" (RETURNING) ti tungtung query.
+func splitReturning(query string) (string, []string, error) {
+	words := unquotedWords(query)
+	for i := len(words) - 1; i > 0; i-- {
+		kw := strings.ToUpper(words[i].text)
+		if kw != "BALIKKEUN" && kw != "RETURNING" {
+			continue
+		}
+
+		var cols []string
+		for _, c := range strings.Split(query[words[i].end:], ",") {
+			c = strings.TrimSpace(c)
+			if c == "" || strings.ContainsAny(c, " '\"") {
+				return "", nil, errors.New("format BALIKKEUN salah: ... BALIKKEUN * atanapi BALIKKEUN kolom1, kolom2")
+			}
+			cols = append(cols, c)
+		}
+		return strings.TrimSpace(query[:words[i].start]), cols, nil
+	}
+	return query, nil, nil
+}
+
+// splitConflict misahkeun klausa "MUN AYA [(kolom)] OMEAN k=v, ..." / "MUN AYA
+// ANTEPKEUN" (ON CONFLICT DO UPDATE SET / DO NOTHING). Mun saatos MUN AYA
+// teu aya aksi nu valid, query dianggap teu gaduh klausa (bisa waé data).
+func splitConflict(query string) (string, *ConflictClause, error) {
+	words := unquotedWords(query)
+	for i := 2; i+1 < len(words); i++ {
+		first, second := strings.ToUpper(words[i].text), strings.ToUpper(words[i+1].text)
+		if !(first == "MUN" && second == "AYA") && !(first == "ON" && second == "CONFLICT") {
+			continue
+		}
+
+		clause := &ConflictClause{}
+		rest := strings.TrimSpace(query[words[i+1].end:])
+		if strings.HasPrefix(rest, "(") {
+			end := strings.Index(rest, ")")
+			if end == -1 {
+				return "", nil, errors.New("daptar kolom MUN AYA teu ditutup")
+			}
+			cols, err := parseInsertColumns(rest[1:end])
+			if err != nil {
+				return "", nil, err
+			}
+			clause.Columns = cols
+			rest = strings.TrimSpace(rest[end+1:])
+		}
+
+		action := strings.Fields(rest)
+		upper := make([]string, len(action))
+		for j, w := range action {
+			upper[j] = strings.ToUpper(w)
+		}
+		skip := 0
+		switch {
+		case len(upper) == 1 && (upper[0] == "ANTEPKEUN" || upper[0] == "LIWATKEUN"):
+			clause.DoNothing = true
+		case len(upper) == 2 && upper[0] == "DO" && upper[1] == "NOTHING":
+			clause.DoNothing = true
+		case len(upper) > 1 && (upper[0] == "OMEAN" || upper[0] == "UPDATE"):
+			skip = 1
+		case len(upper) > 2 && upper[0] == "DO" && upper[1] == "UPDATE":
+			skip = 2
+		default:
+			continue
+		}
+
+		if !clause.DoNothing {
+			if kw := upper[skip]; kw == "JADI" || kw == "JANTEN" || kw == "SET" {
+				skip++
+			}
+			updates := strings.Join(action[skip:], " ")
+			clause.Updates = make(map[string]string)
+			for _, pair := range splitQuoted(updates, ',') {
+				k, v, ok := strings.Cut(pair, "=")
+				if !ok || strings.TrimSpace(k) == "" {
+					return "", nil, errors.New("format MUN AYA OMEAN salah: MUN AYA OMEAN kolom=nilai, kolom2=ANYAR.kolom2")
+				}
+				clause.Updates[strings.TrimSpace(k)] = tupleValue(v)
+			}
+		}
+		return strings.TrimSpace(query[:words[i].start]), clause, nil
 	}
+	return query, nil, nil
+}
 
-	dataPart := strings.Join(tokens[2:], " ")
+func parseInsert(query string) (*Command, error) {
+	query, conflict, err := splitConflict(query)
+	if err != nil {
+		return nil, err
+	}
+	cmd, err := parseInsertBody(query)
+	if err != nil {
+		return nil, err
+	}
+	cmd.Insert.OnConflict = conflict
+	return cmd, nil
+}
+
+func parseInsertBody(query string) (*Command, error) {
+	if m := reInsertValues.FindStringSubmatch(query); m != nil {
+		cols, err := parseInsertColumns(m[2])
+		if err != nil {
+			return nil, err
+		}
+		rows, err := parseValueTuples(m[3])
+		if err != nil {
+			return nil, err
+		}
+		return &Command{Type: CmdInsert, Table: m[1], Insert: InsertDefinition{Columns: cols, Rows: rows}}, nil
+	}
+
+	if m := reInsertSelect.FindStringSubmatch(query); m != nil {
+		cols, err := parseInsertColumns(m[2])
+		if err != nil {
+			return nil, err
+		}
+		sel, err := Parse(m[3])
+		if err != nil {
+			return nil, err
+		}
+		if sel.Type != CmdSelect {
+			return nil, errors.New("SIMPEN ... TINGALI ngan nampi query TINGALI biasa")
+		}
+		return &Command{Type: CmdInsert, Table: m[1], Insert: InsertDefinition{Columns: cols, Select: sel}}, nil
+	}
+
+	m := reInsert.FindStringSubmatch(query)
+	if m == nil {
+		return nil, errors.New("format simpen salah: SIMPEN <table> <data>")
+	}
 
 	return &Command{
 		Type:  CmdInsert,
-		Table: tokens[1],
-		Data:  dataPart,
+		Table: m[1],
+		Data:  strings.TrimSpace(m[2]),
 	}, nil
 }
 
+// parseInsertColumns: "id, nama" -> [id nama]. Kosong mun teu aya daptar kolom.
+func parseInsertColumns(raw string) ([]string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	var cols []string
+	seen := make(map[string]bool)
+	for _, c := range strings.Split(raw, ",") {
+		c = strings.TrimSpace(c)
+		if c == "" || strings.ContainsAny(c, " '\"") {
+			return nil, fmt.Errorf("daptar kolom SIMPEN teu valid: (%s)", raw)
+		}
+		if seen[c] {
+			return nil, fmt.Errorf("kolom '%s' disebut dua kali", c)
+		}
+		seen[c] = true
+		cols = append(cols, c)
+	}
+	return cols, nil
+}
+
+// parseValueTuples maca "(1,'Asep'), (2,'Budi')". Koma sareng kurung di jero
+// tanda petik teu diitung.
+func parseValueTuples(raw string) ([][]string, error) {
+	var rows [][]string
+	var row []string
+	var current strings.Builder
+	var quote rune
+	inTuple, afterTuple := false, false
+
+	for _, r := range raw {
+		switch {
+		case quote != 0:
+			current.WriteRune(r)
+			if r == quote {
+				quote = 0
+			}
+		case !inTuple:
+			switch {
+			case r == '(' && !afterTuple:
+				inTuple = true
+			case r == ',' && afterTuple:
+				afterTuple = false
+			case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			default:
+				return nil, fmt.Errorf("format NILAI salah caket '%c': NILAI (a,b), (c,d)", r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			current.WriteRune(r)
+		case r == ',':
+			row = append(row, tupleValue(current.String()))
+			current.Reset()
+		case r == ')':
+			row = append(row, tupleValue(current.String()))
+			current.Reset()
+			rows = append(rows, row)
+			row = nil
+			inTuple, afterTuple = false, true
+		default:
+			current.WriteRune(r)
+		}
+	}
+
+	if quote != 0 || inTuple {
+		return nil, errors.New("NILAI teu ditutup (tanda petik atanapi kurung)")
+	}
+	if len(rows) == 0 || !afterTuple {
+		return nil, errors.New("format NILAI salah: NILAI (a,b), (c,d)")
+	}
+	return rows, nil
+}
+
+// tupleValue ngaleungitkeun tanda petik tina nilai dina NILAI (...). NULL,
+// '' sareng 'NULL' diantep sangkan schema.InputValue tiasa ngabédakeunana.
+func tupleValue(raw string) string {
+	val := strings.TrimSpace(raw)
+	if len(val) < 2 || (val[0] != '\'' && val[0] != '"') || val[len(val)-1] != val[0] {
+		return val
+	}
+	inner := val[1 : len(val)-1]
+	if inner == "" || strings.EqualFold(inner, "NULL") {
+		return val
+	}
+	q := string(val[0])
+	return strings.ReplaceAll(inner, q+q, q)
+}
+
 func parseSelect(tokens []string) (*Command, error) {
 	cmd := &Command{
 		Type:   CmdSelect,
@@ -255,13 +942,21 @@ func parseSelect(tokens []string) (*Command, error) {
 		}
 	}
 
+	first := 1
+	if len(tokens) > 1 {
+		if t := strings.ToUpper(tokens[1]); t == "BEDA" || t == "DISTINCT" {
+			cmd.Distinct = true
+			first = 2
+		}
+	}
+
 	idx := 0
 	if tiIndex != -1 {
-		if tiIndex < 1 {
+		if tiIndex <= first {
 			return nil, errors.New("kolom teu disebutkeun samemeh TI")
 		}
 
-		colsPart := strings.Join(tokens[1:tiIndex], " ")
+		colsPart := strings.Join(tokens[first:tiIndex], " ")
 		rawFields := strings.Split(colsPart, ",")
 		for _, f := range rawFields {
 			cmd.Fields = append(cmd.Fields, strings.TrimSpace(f))
@@ -275,12 +970,12 @@ func parseSelect(tokens []string) (*Command, error) {
 		idx = tiIndex + 2
 
 	} else {
-		if len(tokens) < 2 {
+		if len(tokens) <= first {
 			return nil, errors.New("format TINGALI salah, minimal: TINGALI <tabel>")
 		}
-		cmd.Table = tokens[1]
+		cmd.Table = tokens[first]
 		cmd.Fields = []string{"*"}
-		idx = 2
+		idx = first + 1
 	}
 
 	for idx < len(tokens) {
@@ -402,6 +1097,11 @@ func parseSelect(tokens []string) (*Command, error) {
 
 			cmd.OrderBy = tokens[targetIdx]
 			idx = targetIdx + 1
+
+			// RELEVANSI(kolom) dirunut ti nu pangrelevanna mun teu disebutkeun.
+			if _, ok := RelevanceTarget(cmd.OrderBy); ok {
+				cmd.OrderDesc = true
+			}
 			
 			if idx < len(tokens) {
 				mode := strings.ToUpper(tokens[idx])
@@ -414,6 +1114,16 @@ func parseSelect(tokens []string) (*Command, error) {
 				}
 			}
 
+		case "KOLASI", "COLLATE":
+			if idx+1 >= len(tokens) {
+				return nil, errors.New("KOLASI butuh ngaran kolasi (BINARY, NOCASE, ID)")
+			}
+			cmd.Collation = schema.NormalizeCollation(tokens[idx+1])
+			if cmd.Collation == "" {
+				return nil, errors.New("kolasi teu dikenal: " + tokens[idx+1])
+			}
+			idx += 2
+
 		case "SAKADAR", "LIMIT":
 			if idx+1 >= len(tokens) {
 				return nil, errors.New("SAKADAR butuh angka")
@@ -444,6 +1154,48 @@ func parseSelect(tokens []string) (*Command, error) {
 	return cmd, nil
 }
 
+// RelevanceTarget: "RELEVANSI(judul)" (atanapi RELEVANCE) -> "judul", true.
+func RelevanceTarget(expr string) (string, bool) {
+    open := strings.Index(expr, "(")
+    if open == -1 || !strings.HasSuffix(expr, ")") {
+        return "", false
+    }
+    switch strings.ToUpper(strings.TrimSpace(expr[:open])) {
+    case "RELEVANSI", "RELEVANCE":
+        target := strings.TrimSpace(expr[open+1 : len(expr)-1])
+        return target, target != ""
+    }
+    return "", false
+}
+
+// parseSelectWithSearch: MILARI(kolom, 'query') dina DIMANA dirobih heula
+// jadi "kolom MILARI <panyiri>" (sabab query-na tiasa ngandung spasi sareng
+// '='), teras panyirina dipulihkeun saatos TINGALI di-parse.
+func parseSelectWithSearch(raw string, tokens []string) (*Command, error) {
+    var searches []string
+    rewritten := reSearch.ReplaceAllStringFunc(raw, func(m string) string {
+        sub := reSearch.FindStringSubmatch(m)
+        searches = append(searches, sub[2]+sub[3])
+        return fmt.Sprintf(" %s %s __MILARI_%d__ ", sub[1], OpSearch, len(searches)-1)
+    })
+    if searches == nil {
+        return parseSelect(tokens)
+    }
+
+    cmd, err := parseSelect(strings.Fields(normalizeQuery(rewritten)))
+    if err != nil {
+        return nil, err
+    }
+    for i := range cmd.Where {
+        var n int
+        if _, err := fmt.Sscanf(cmd.Where[i].Value, "__MILARI_%d__", &n); err == nil && n < len(searches) {
+            cmd.Where[i].Operator = OpSearch
+            cmd.Where[i].Value = searches[n]
+        }
+    }
+    return cmd, nil
+}
+
 func findNextKeyword(tokens []string, start int) int {
     for i := start; i < len(tokens); i++ {
         t := strings.ToUpper(tokens[i])
@@ -453,6 +1205,7 @@ func findNextKeyword(tokens []string, start int) int {
            t == "LIWATAN" || t == "OFFSET" || 
            t == "KUMPULKEUN" || t == "GROUP" || 
            t == "MUN" || t == "HAVING" ||     
+           t == "KOLASI" || t == "COLLATE" ||
            isJoinKeyword(t) {
             return i
         }
@@ -464,7 +1217,8 @@ func isJoinKeyword(t string) bool {
 	return t == "GABUNG" || t == "JOIN" || 
 	       t == "INNER" ||  t == "HIJIKEUN" || 
 	       t == "LEFT" || t == "KENCA" || 
-	       t == "RIGHT" || t == "KATUHU"
+	       t == "RIGHT" || t == "KATUHU" ||
+	       t == "FULL" || t == "PINUH"
 }
 
 func parseUpdate(tokens []string) (*Command, error) {
@@ -549,32 +1303,95 @@ func parseDelete(tokens []string) (*Command, error) {
 	return cmd, nil
 }
 
+// ParseConditionExpr meulah ekspresi syarat (conto: "umur >= 17 SARENG umur < 60"),
+// dipaké ku konstrain CHECK.
+func ParseConditionExpr(expr string) ([]Condition, error) {
+	tokens := strings.Fields(normalizeQuery(strings.TrimSpace(expr)))
+	if len(tokens) < 2 {
+		return nil, errors.New("ekspresi syarat teu lengkep: " + expr)
+	}
+	return parseConditionsList(tokens)
+}
+
 func parseConditionsList(tokens []string) ([]Condition, error) {
 	var conditions []Condition
 	i := 0
-	for i < len(tokens) {
-		if i+2 >= len(tokens) {
-			break 
-		}
-		field := tokens[i]
-		op := tokens[i+1]
-		val := tokens[i+2]
-		val = strings.Trim(val, "'\"")
-		cond := Condition{
-			Field:    field,
-			Operator: op,
-			Value:    val,
-		}
-		if i+3 < len(tokens) {
-			logic := strings.ToUpper(tokens[i+3])
+	for i+1 < len(tokens) {
+		cond := Condition{Field: tokens[i]}
+		next := i + 3
+
+		if op, n := nullTest(tokens[i+1:]); n > 0 {
+			cond.Operator = op
+			next = i + 1 + n
+		} else {
+			if i+2 >= len(tokens) {
+				break
+			}
+			cond.Operator = tokens[i+1]
+			cond.Value = conditionValue(tokens[i+2])
+
+			if next+1 < len(tokens) {
+				if kw := strings.ToUpper(tokens[next]); kw == "ESCAPE" || kw == "KABUR" {
+					cond.Escape = strings.Trim(tokens[next+1], "'\"")
+					next += 2
+				}
+			}
+
+			switch strings.ToUpper(cond.Operator) {
+			case "COCOK", "REGEXP":
+				if _, err := regexp.Compile(cond.Value); err != nil {
+					return nil, fmt.Errorf("pola COCOK teu valid: %v", err)
+				}
+			}
+		}
+
+		if next < len(tokens) {
+			logic := strings.ToUpper(tokens[next])
 			if logic == "SARENG" || logic == "AND" || logic == "ATAWA" || logic == "OR" {
 				cond.LogicOp = logic
-				i++
+				next++
 			}
 		}
 		
 		conditions = append(conditions, cond)
-		i += 3 
+		i = next
 	}
 	return conditions, nil
-}
\ No newline at end of file
+}
+
+// nullTest ngenalan "IS NULL" / "KOSONG" sareng "IS NOT NULL" / "TEU KOSONG".
+// Mulihkeun operator sareng jumlah token nu dipaké (0 mun sanés).
+func nullTest(tokens []string) (string, int) {
+	upper := func(i int) string {
+		if i < len(tokens) {
+			return strings.ToUpper(tokens[i])
+		}
+		return ""
+	}
+
+	switch upper(0) {
+	case "KOSONG":
+		return OpIsNull, 1
+	case "TEU", "HENTEU":
+		if upper(1) == "KOSONG" {
+			return OpIsNotNull, 2
+		}
+	case "IS":
+		if upper(1) == "NULL" {
+			return OpIsNull, 2
+		}
+		if upper(1) == "NOT" && upper(2) == "NULL" {
+			return OpIsNotNull, 3
+		}
+	}
+	return "", 0
+}
+
+// conditionValue: NULL tanpa tanda petik jadi schema.NullValue (hasilna
+// TEU PASTI dina babandingan), sésana tanda petikna dipiceun.
+func conditionValue(raw string) string {
+	if strings.EqualFold(raw, "NULL") {
+		return schema.NullValue
+	}
+	return strings.Trim(raw, "'\"")
+}
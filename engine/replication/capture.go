@@ -0,0 +1,193 @@
+package replication
+
+import (
+	"encoding/json"
+	"hash/crc32"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/febrd/maungdb/internal/config"
+)
+
+// fileState: kaayaan hiji file dina capture pamungkas. CRC dipaké pikeun
+// mastikeun file ngan ditambihan (bagian heubeulna teu robih).
+type fileState struct {
+	Dir     bool      `json:"dir,omitempty"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	CRC     uint32    `json:"crc"`
+}
+
+type manifest map[string]fileState
+
+// replicated: file nu teu kaasup réplikasi nyaéta sési login, peran node,
+// log transaksi lokal, file samentawis, sareng diréktori réplikasi sorangan.
+func replicated(rel string) bool {
+	switch {
+	case rel == config.ReplicationDir || strings.HasPrefix(rel, config.ReplicationDir+"/"):
+		return false
+	case rel == config.SystemDir+"/"+config.SessionFile, rel == config.SystemDir+"/"+config.RoleFile:
+		return false
+	case rel == "wal.log":
+		return false
+	case strings.HasSuffix(rel, ".tmp"):
+		return false
+	}
+	return true
+}
+
+// scan maca kaayaan sadaya file nu diréplikasi dina config.DataDir. Eusi
+// file ngan dibaca deui mun ukuran atanapi waktosna robih ti prev.
+func scan(prev manifest) (manifest, map[string][]byte, error) {
+	current := make(manifest)
+	contents := make(map[string][]byte)
+
+	err := filepath.WalkDir(config.DataDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		rel, err := filepath.Rel(config.DataDir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if !replicated(rel) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if d.IsDir() {
+			current[rel] = fileState{Dir: true}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+
+		old, ok := prev[rel]
+		if ok && !old.Dir && old.Size == info.Size() && old.ModTime.Equal(info.ModTime()) {
+			current[rel] = old
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		current[rel] = fileState{Size: int64(len(data)), ModTime: info.ModTime(), CRC: crc32.ChecksumIEEE(data)}
+		contents[rel] = data
+		return nil
+	})
+	return current, contents, err
+}
+
+// diff nyusun entri log tina bédana dua manifest: diréktori anyar heula,
+// teras file, teras nu dipiceun (anak heula samemeh kolotna).
+func diff(prev, current manifest, contents map[string][]byte) []Entry {
+	var entries []Entry
+
+	var paths []string
+	for rel := range current {
+		paths = append(paths, rel)
+	}
+	sort.Strings(paths)
+
+	for _, rel := range paths {
+		if current[rel].Dir {
+			if old, ok := prev[rel]; !ok || !old.Dir {
+				entries = append(entries, Entry{Op: OpMkdir, Path: rel})
+			}
+		}
+	}
+
+	for _, rel := range paths {
+		data, changed := contents[rel]
+		if current[rel].Dir || !changed {
+			continue
+		}
+		old, ok := prev[rel]
+		if ok && !old.Dir && int64(len(data)) > old.Size && crc32.ChecksumIEEE(data[:old.Size]) == old.CRC {
+			entries = append(entries, Entry{Op: OpAppend, Path: rel, Offset: old.Size, Data: data[old.Size:]})
+			continue
+		}
+		if ok && !old.Dir && old.Size == current[rel].Size && old.CRC == current[rel].CRC {
+			continue
+		}
+		entries = append(entries, Entry{Op: OpWrite, Path: rel, Data: data})
+	}
+
+	var removed []string
+	for rel := range prev {
+		if _, ok := current[rel]; !ok {
+			removed = append(removed, rel)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(removed)))
+	for _, rel := range removed {
+		entries = append(entries, Entry{Op: OpRemove, Path: rel})
+	}
+	return entries
+}
+
+func loadManifest(path string) (manifest, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	m := make(manifest)
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func saveManifest(path string, m manifest) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// writeFileAtomic nyerat ka file samentawis heula teras rename, sangkan
+// nu maca teu kantos ningali file satengah ditulis.
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,85 @@
+package replication
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/febrd/maungdb/internal/config"
+)
+
+func TestReplicated(t *testing.T) {
+	cases := map[string]bool{
+		"db_toko/barang.maung":                      true,
+		config.SystemDir + "/users.maung":           true,
+		config.SystemDir + "/" + config.SessionFile: false,
+		config.SystemDir + "/" + config.RoleFile:    false,
+		config.ReplicationDir:                       false,
+		config.ReplicationDir + "/log.maung":        false,
+		"wal.log":                                   false,
+		"db_toko/barang.maung.tmp":                  false,
+	}
+	for rel, want := range cases {
+		if got := replicated(rel); got != want {
+			t.Errorf("replicated(%q) = %v, want %v", rel, got, want)
+		}
+	}
+}
+
+// TestCaptureDiff: unggal léngkah ngarobih DataDir teras mariksa entri nu
+// dihasilkeun scan+diff ti manifest léngkah samemehna.
+func TestCaptureDiff(t *testing.T) {
+	old := config.DataDir
+	config.DataDir = t.TempDir()
+	defer func() { config.DataDir = old }()
+
+	path := func(rel string) string { return filepath.Join(config.DataDir, filepath.FromSlash(rel)) }
+	write := func(rel, data string) func() error {
+		return func() error { return os.WriteFile(path(rel), []byte(data), 0644) }
+	}
+	appendTo := func(rel, data string) func() error {
+		return func() error {
+			f, err := os.OpenFile(path(rel), os.O_APPEND|os.O_WRONLY, 0644)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			_, err = f.WriteString(data)
+			return err
+		}
+	}
+
+	steps := []struct {
+		name   string
+		change func() error
+		want   []Entry
+	}{
+		{"diréktori anyar", func() error { return os.Mkdir(path("db_a"), 0755) },
+			[]Entry{{Op: OpMkdir, Path: "db_a"}}},
+		{"file anyar", write("db_a/t.maung", "1|a\n"),
+			[]Entry{{Op: OpWrite, Path: "db_a/t.maung", Data: []byte("1|a\n")}}},
+		{"ditambihan", appendTo("db_a/t.maung", "2|b\n"),
+			[]Entry{{Op: OpAppend, Path: "db_a/t.maung", Offset: 4, Data: []byte("2|b\n")}}},
+		{"ditulis deui", write("db_a/t.maung", "9|z\n"),
+			[]Entry{{Op: OpWrite, Path: "db_a/t.maung", Data: []byte("9|z\n")}}},
+		{"file lokal teu diréplikasi", write("wal.log", "x"), nil},
+		{"dipiceun", func() error { return os.RemoveAll(path("db_a")) },
+			[]Entry{{Op: OpRemove, Path: "db_a/t.maung"}, {Op: OpRemove, Path: "db_a"}}},
+	}
+
+	var prev manifest
+	for _, s := range steps {
+		if err := s.change(); err != nil {
+			t.Fatalf("%s: %v", s.name, err)
+		}
+		current, contents, err := scan(prev)
+		if err != nil {
+			t.Fatalf("%s: scan: %v", s.name, err)
+		}
+		if got := diff(prev, current, contents); !reflect.DeepEqual(got, s.want) {
+			t.Errorf("%s: entri = %+v, want %+v", s.name, got, s.want)
+		}
+		prev = current
+	}
+}
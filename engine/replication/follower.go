@@ -0,0 +1,397 @@
+package replication
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/febrd/maungdb/internal/config"
+)
+
+const (
+	retryMin = time.Second
+	retryMax = 30 * time.Second
+
+	ackTimeout = 3 * time.Second
+)
+
+var errResnapshot = errors.New("indung mundut snapshot deui")
+
+// followerState disimpen dina _replikasi/anak.json sangkan anak tiasa
+// neruskeun ti LSN pamungkas saatos restart.
+type followerState struct {
+	Master string `json:"master"`
+	LSN    uint64 `json:"lsn"`
+}
+
+type follower struct {
+	rm     *ReplicationManager
+	master string
+	base   string
+	client *http.Client
+	done   chan struct{}
+
+	mu sync.Mutex
+	followerStatus
+}
+
+// followerStatus: kaayaan ANAK nu katingal tina waleran indung pamungkas.
+type followerStatus struct {
+	contacted    bool
+	connected    bool
+	appliedLSN   uint64
+	masterLSN    uint64
+	lagBytes     int64
+	pendingSince int64
+	lastContact  time.Time
+	lastError    string
+}
+
+func newFollower(rm *ReplicationManager, master string) *follower {
+	return &follower{
+		rm:     rm,
+		master: master,
+		base:   masterURL(master),
+		client: &http.Client{Timeout: walPollTimeout + 20*time.Second},
+		done:   make(chan struct{}),
+	}
+}
+
+func (f *follower) status() followerStatus {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.followerStatus
+}
+
+func (f *follower) update(fn func(s *followerStatus)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	fn(&f.followerStatus)
+}
+
+// run ngintil indung dugi ka ctx dibatalkeun. Mun sambungan pegat, dicoba
+// deui kalayan jeda nu ningkat (1 detik dugi ka 30 detik).
+func (f *follower) run(ctx context.Context) {
+	defer close(f.done)
+
+	delay := retryMin
+	for ctx.Err() == nil {
+		started := time.Now()
+		err := f.sync(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		f.update(func(s *followerStatus) {
+			s.connected = false
+			s.lastError = err.Error()
+		})
+		// Sambungan nu parantos lami lancar: mimitian deui ti jeda pangleutikna.
+		if time.Since(started) > retryMax {
+			delay = retryMin
+		}
+		fmt.Printf("⚠️ Réplikasi: sambungan ka indung %s pegat: %v (dicoba deui saatos %v)\n", f.master, err, delay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		if delay *= 2; delay > retryMax {
+			delay = retryMax
+		}
+	}
+}
+
+// sync snapshot heula mun perlu, teras nerapkeun log terus-terusan. Ngan
+// mulang mun aya kasalahan.
+func (f *follower) sync(ctx context.Context) error {
+	state, err := loadFollowerState()
+	if err != nil {
+		return err
+	}
+	if state == nil || state.Master != f.master {
+		if state, err = f.snapshot(ctx); err != nil {
+			return err
+		}
+	}
+	f.update(func(s *followerStatus) { s.appliedLSN = state.LSN })
+	f.confirm(ctx, state.LSN)
+
+	wait := false
+	for {
+		resp, err := f.fetchWAL(ctx, state.LSN, wait)
+		wait = true
+		if errors.Is(err, errResnapshot) {
+			fmt.Println("📦 Réplikasi: LSN anak teu aya di indung, snapshot deui")
+			if state, err = f.snapshot(ctx); err != nil {
+				return err
+			}
+			f.confirm(ctx, state.LSN)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, e := range resp.Entries {
+			if e.LSN <= state.LSN {
+				continue
+			}
+			if err := applyEntry(e); err != nil {
+				return fmt.Errorf("gagal nerapkeun LSN %d (%s %s): %v", e.LSN, e.Op, e.Path, err)
+			}
+			state.LSN = e.LSN
+		}
+		if len(resp.Entries) > 0 {
+			if err := saveFollowerState(state); err != nil {
+				return err
+			}
+			f.confirm(ctx, state.LSN)
+		}
+		f.update(func(s *followerStatus) {
+			s.contacted, s.connected = true, true
+			s.appliedLSN, s.masterLSN = state.LSN, resp.LSN
+			s.lagBytes, s.pendingSince = resp.LagBytes, resp.PendingSince
+			s.lastContact = time.Now()
+			s.lastError = ""
+		})
+	}
+}
+
+func (f *follower) request(ctx context.Context, method, path string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, f.base+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token := os.Getenv(tokenEnv); token != "" {
+		req.Header.Set(tokenHeader, token)
+	}
+	f.rm.nodeMu.Lock()
+	req.Header.Set(nodeHeader, f.rm.node().ID)
+	req.Header.Set(generationHeader, strconv.FormatUint(f.rm.node().Generation, 10))
+	f.rm.nodeMu.Unlock()
+	return req, nil
+}
+
+func (f *follower) get(ctx context.Context, path string, out interface{}) error {
+	req, err := f.request(ctx, http.MethodGet, path)
+	if err != nil {
+		return err
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		gen, _ := strconv.ParseUint(resp.Header.Get(generationHeader), 10, 64)
+		if err := f.rm.observeMaster(gen); err != nil {
+			return err
+		}
+		return json.NewDecoder(resp.Body).Decode(out)
+	case http.StatusConflict:
+		return errResnapshot
+	}
+	var msg [256]byte
+	n, _ := resp.Body.Read(msg[:])
+	return fmt.Errorf("indung ngawaler %d: %s", resp.StatusCode, strings.TrimSpace(string(msg[:n])))
+}
+
+// confirm ngirim ack; gagalna teu ngeureunkeun réplikasi, commit sinkron di
+// indung ngan ukur teu kabéjaan.
+func (f *follower) confirm(ctx context.Context, lsn uint64) {
+	if err := f.ack(ctx, lsn); err != nil && ctx.Err() == nil {
+		fmt.Printf("⚠️ Réplikasi: gagal ngirim konfirmasi LSN %d: %v\n", lsn, err)
+	}
+}
+
+// ack ngabéjaan indung yén log dugi ka lsn parantos disimpen ka disk.
+func (f *follower) ack(ctx context.Context, lsn uint64) error {
+	ctx, cancel := context.WithTimeout(ctx, ackTimeout)
+	defer cancel()
+	req, err := f.request(ctx, http.MethodPost, fmt.Sprintf("/replikasi/ack?lsn=%d", lsn))
+	if err != nil {
+		return err
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("indung ngawaler %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// fetchWAL nyandak entri saatos lsn. Mun !wait, indung langsung ngawaler
+// sanajan can aya entri anyar.
+func (f *follower) fetchWAL(ctx context.Context, lsn uint64, wait bool) (*walResponse, error) {
+	path := fmt.Sprintf("/replikasi/wal?dari=%d", lsn)
+	if !wait {
+		path += "&tunggu=0"
+	}
+	var resp walResponse
+	if err := f.get(ctx, path, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// snapshot ngaganti sadaya data lokal nu diréplikasi ku data indung.
+func (f *follower) snapshot(ctx context.Context) (*followerState, error) {
+	var snap snapshotResponse
+	if err := f.get(ctx, "/replikasi/snapshot", &snap); err != nil {
+		return nil, err
+	}
+
+	keep := make(map[string]bool)
+	for _, file := range snap.Files {
+		keep[file.Path] = true
+		e := Entry{Op: OpWrite, Path: file.Path, Data: file.Data}
+		if file.Dir {
+			e = Entry{Op: OpMkdir, Path: file.Path}
+		}
+		if err := applyEntry(e); err != nil {
+			return nil, fmt.Errorf("gagal nerapkeun snapshot %s: %v", file.Path, err)
+		}
+	}
+
+	// File lokal nu teu aya di indung dipiceun.
+	var stale []string
+	filepath.WalkDir(config.DataDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, relErr := filepath.Rel(config.DataDir, path)
+		if relErr != nil || rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		if !replicated(rel) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !keep[rel] {
+			stale = append(stale, rel)
+		}
+		return nil
+	})
+	sort.Sort(sort.Reverse(sort.StringSlice(stale)))
+	for _, rel := range stale {
+		if err := applyEntry(Entry{Op: OpRemove, Path: rel}); err != nil {
+			return nil, err
+		}
+	}
+
+	state := &followerState{Master: f.master, LSN: snap.LSN}
+	if err := saveFollowerState(state); err != nil {
+		return nil, err
+	}
+	f.update(func(s *followerStatus) {
+		s.contacted, s.connected = true, true
+		s.appliedLSN, s.masterLSN = snap.LSN, snap.LSN
+		s.lagBytes, s.pendingSince = 0, 0
+		s.lastContact = time.Now()
+		s.lastError = ""
+	})
+	fmt.Printf("📦 Réplikasi: snapshot ti %s (LSN %d, %d file)\n", f.master, snap.LSN, len(snap.Files))
+	return state, nil
+}
+
+// localPath narjamahkeun path relatif ti indung, nolak path nu kaluar
+// tina config.DataDir atanapi nu teu kaasup réplikasi.
+func localPath(rel string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(rel))
+	if rel == "" || filepath.IsAbs(clean) || clean == "." || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path réplikasi teu valid: %q", rel)
+	}
+	if !replicated(filepath.ToSlash(clean)) {
+		return "", fmt.Errorf("path réplikasi teu kénging: %q", rel)
+	}
+	return filepath.Join(config.DataDir, clean), nil
+}
+
+// applyEntry nerapkeun hiji entri. Sadaya operasi idempotent, janten
+// entri nu parantos kaeusi ku snapshot tiasa diterapkeun deui.
+func applyEntry(e Entry) error {
+	path, err := localPath(e.Path)
+	if err != nil {
+		return err
+	}
+
+	switch e.Op {
+	case OpMkdir:
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			os.Remove(path)
+		}
+		return os.MkdirAll(path, 0755)
+
+	case OpWrite:
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		return writeFileAtomic(path, e.Data)
+
+	case OpAppend:
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0644)
+		if err != nil {
+			return err
+		}
+		if err := f.Truncate(e.Offset); err != nil {
+			f.Close()
+			return err
+		}
+		if _, err := f.WriteAt(e.Data, e.Offset); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Sync(); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+
+	case OpRemove:
+		return os.RemoveAll(path)
+	}
+	return fmt.Errorf("operasi réplikasi teu dikenal: %s", e.Op)
+}
+
+func loadFollowerState() (*followerState, error) {
+	data, err := os.ReadFile(replicationPath("anak.json"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state followerState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, nil
+	}
+	return &state, nil
+}
+
+func saveFollowerState(state *followerState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(replicationPath("anak.json"), data)
+}
@@ -0,0 +1,267 @@
+package replication
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Jinis parobahan file dina log réplikasi.
+const (
+	OpWrite  = "tulis"     // eusi file diganti sadayana
+	OpAppend = "tambih"    // file dipotong ka Offset, teras Data ditambihkeun
+	OpRemove = "hapus"     // file/diréktori dipiceun
+	OpMkdir  = "direktori" // diréktori dijieun
+	opBase   = "mulai"     // panyiri awal log saatos dirotasi, teu dikirim
+)
+
+var (
+	// ErrTooOld: LSN nu dipénta parantos kapiceun ku rotasi log.
+	ErrTooOld = errors.New("LSN parantos teu aya dina log, kedah snapshot deui")
+	// ErrAhead: LSN nu dipénta langkung énggal ti batan log indung.
+	ErrAhead = errors.New("LSN langkung énggal ti batan indung, kedah snapshot deui")
+)
+
+// Entry: hiji parobahan file nu parantos di-commit. Path relatif kana
+// config.DataDir, nganggo "/".
+type Entry struct {
+	LSN    uint64 `json:"lsn"`
+	Op     string `json:"op"`
+	Path   string `json:"path"`
+	Data   []byte `json:"data,omitempty"`
+	Offset int64  `json:"offset,omitempty"`
+	Time   int64  `json:"time,omitempty"`
+}
+
+// Log: file JSON per baris nu ngan ditambihan. offsets[i] nyaéta posisi
+// bait entri LSN first+i, sangkan maca ti LSN mana waé teu kedah ti awal;
+// times[i] waktos entri éta di-commit (UnixNano).
+type Log struct {
+	mu      sync.Mutex
+	path    string
+	limit   int64
+	first   uint64
+	last    uint64
+	size    int64
+	offsets []int64
+	times   []int64
+	notify  chan struct{}
+}
+
+// OpenLog muka (atanapi nyieun) log sareng mulihkeun LSN pamungkas.
+func OpenLog(path string, limit int64) (*Log, error) {
+	l := &Log{path: path, limit: limit, first: 1, notify: make(chan struct{})}
+
+	f, err := os.OpenFile(path, os.O_RDONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	var pos int64
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(line) > 0 && line[len(line)-1] == '\n' {
+			var e Entry
+			if jsonErr := json.Unmarshal(line, &e); jsonErr != nil {
+				return nil, fmt.Errorf("log réplikasi ruksak dina bait %d: %v", pos, jsonErr)
+			}
+			if e.Op == opBase {
+				l.first, l.last = e.LSN+1, e.LSN
+			} else {
+				l.offsets = append(l.offsets, pos)
+				l.times = append(l.times, e.Time)
+				l.last = e.LSN
+			}
+			pos += int64(len(line))
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Baris pamungkas nu teu lengkep (crash di tengah nulis) dipiceun.
+	if err := os.Truncate(path, pos); err != nil {
+		return nil, err
+	}
+	l.size = pos
+	return l, nil
+}
+
+// LastLSN mulihkeun LSN entri pamungkas.
+func (l *Log) LastLSN() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.last
+}
+
+// Append masihan LSN ka unggal entri, nyerat sareng nga-fsync log, teras
+// ngahudangkeun anak nu nuju ngantosan.
+func (l *Log) Append(entries []Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	var offsets []int64
+	now := time.Now().UnixNano()
+	pos := l.size
+	w := bufio.NewWriter(f)
+	for i := range entries {
+		entries[i].LSN = l.last + uint64(i) + 1
+		entries[i].Time = now
+		line, err := json.Marshal(entries[i])
+		if err != nil {
+			f.Close()
+			return err
+		}
+		offsets = append(offsets, pos)
+		w.Write(line)
+		w.WriteByte('\n')
+		pos += int64(len(line)) + 1
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	l.offsets = append(l.offsets, offsets...)
+	for range offsets {
+		l.times = append(l.times, now)
+	}
+	l.last += uint64(len(entries))
+	l.size = pos
+	close(l.notify)
+	l.notify = make(chan struct{})
+
+	if l.limit > 0 && l.size > l.limit {
+		return l.rotate()
+	}
+	return nil
+}
+
+// rotate mindahkeun log ka "<path>.1" sareng ngamimitian log anyar. Anak
+// nu katinggaleun langkung ti hiji rotasi kedah snapshot deui.
+func (l *Log) rotate() error {
+	if err := os.Rename(l.path, l.path+".1"); err != nil {
+		return err
+	}
+	line, _ := json.Marshal(Entry{LSN: l.last, Op: opBase})
+	line = append(line, '\n')
+	if err := os.WriteFile(l.path, line, 0644); err != nil {
+		return err
+	}
+	l.first = l.last + 1
+	l.offsets = nil
+	l.times = nil
+	l.size = int64(len(line))
+	return nil
+}
+
+// Lag mulihkeun jumlah bait log saatos LSN lsn sareng waktos commit entri
+// munggaran nu can katampi (0 mun parantos sinkron).
+func (l *Log) Lag(lsn uint64) (int64, int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	switch {
+	case lsn >= l.last:
+		return 0, 0
+	case lsn+1 < l.first:
+		if len(l.times) == 0 {
+			return l.size, 0
+		}
+		return l.size, l.times[0]
+	}
+	i := lsn + 1 - l.first
+	return l.size - l.offsets[i], l.times[i]
+}
+
+// Read mulihkeun entri saatos LSN from, dugi ka max entri atanapi
+// maxBytes bait data.
+func (l *Log) Read(from uint64, max int, maxBytes int) ([]Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	switch {
+	case from > l.last:
+		return nil, ErrAhead
+	case from+1 < l.first:
+		return nil, ErrTooOld
+	case from == l.last:
+		return nil, nil
+	}
+	start := l.offsets[from+1-l.first]
+
+	f, err := os.Open(l.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(io.NewSectionReader(f, start, l.size-start))
+	var entries []Entry
+	size := 0
+	for len(entries) < max {
+		line, err := r.ReadBytes('\n')
+		if len(line) > 0 {
+			var e Entry
+			if err := json.Unmarshal(line, &e); err != nil {
+				return nil, err
+			}
+			entries = append(entries, e)
+			size += len(e.Data)
+			if size >= maxBytes {
+				break
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// Wait ngantosan dugi ka aya entri saatos LSN from, atanapi timeout. Mun
+// from sanés LSN pamungkas, langsung mulang.
+func (l *Log) Wait(from uint64, timeout time.Duration, done <-chan struct{}) {
+	l.mu.Lock()
+	if l.last != from {
+		l.mu.Unlock()
+		return
+	}
+	ch := l.notify
+	l.mu.Unlock()
+
+	select {
+	case <-ch:
+	case <-time.After(timeout):
+	case <-done:
+	}
+}
@@ -1,43 +1,334 @@
 package replication
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
+	"time"
+
+	"github.com/febrd/maungdb/internal/config"
 )
 
 type NodeRole int
 
 const (
-	RoleMaster NodeRole = iota 
-	RoleSlave            
+	RoleMaster NodeRole = iota
+	RoleSlave
 )
 
 type ReplicationManager struct {
-	mu         sync.RWMutex
+	mu          sync.RWMutex
 	CurrentRole NodeRole
 	MasterHost  string
+	// roleErr: file peran teu kabaca/ruksak. Node tetep Read-Only dugi ka
+	// peranna disetél deui (JADI INDUNG PAKSA atanapi JADI ANAK).
+	roleErr error
+
+	// Ngan aktip dina prosés server (Start). Prosés CLI sakali-jalan teu
+	// nyatet log sareng teu ngintil indung.
+	started   bool
+	log       *Log
+	captureMu sync.Mutex
+	manifest  manifest
+	stopTail  context.CancelFunc
+	follower  *follower
+
+	nodeMu    sync.Mutex
+	nodeState *nodeState
+	peersMu   sync.Mutex
+	peers     map[string]*peer
+	ackNotify chan struct{}
 }
 
 var GlobalReplication = &ReplicationManager{
 	CurrentRole: RoleMaster,
 }
 
-func (rm *ReplicationManager) SetMaster() {
+func replicationPath(name string) string {
+	return filepath.Join(config.DataDir, config.ReplicationDir, name)
+}
+
+// roleFile: peran node disimpen dina diréktori sistem (teu diréplikasi)
+// sangkan ANAK tetep ANAK saatos restart.
+type roleFile struct {
+	Role   string `json:"role"`
+	Master string `json:"master,omitempty"`
+}
+
+func rolePath() string {
+	return filepath.Join(config.DataDir, config.SystemDir, config.RoleFile)
+}
+
+// Load maca peran node nu disimpen. Disauran sakali nalika prosés dimimitian.
+// Mun filena teu kabaca atanapi ruksak, node dimimitian Read-Only (sabab
+// meureun ANAK) sareng errorna dipulangkeun.
+func (rm *ReplicationManager) Load() error {
+	rf, err := readRoleFile()
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	if err != nil {
+		rm.roleErr = err
+		return fmt.Errorf("%v; node ieu Read-Only dugi ka peranna disetél deui (JADI INDUNG PAKSA / JADI ANAK <host>)", err)
+	}
+
+	rm.roleErr = nil
+	if rf.Role == "ANAK" {
+		rm.CurrentRole = RoleSlave
+		rm.MasterHost = rf.Master
+	} else {
+		rm.CurrentRole = RoleMaster
+		rm.MasterHost = ""
+	}
+	return nil
+}
+
+func readRoleFile() (roleFile, error) {
+	rf := roleFile{Role: "INDUNG"}
+	data, err := os.ReadFile(rolePath())
+	if os.IsNotExist(err) {
+		return rf, nil
+	}
+	if err != nil {
+		return rf, fmt.Errorf("file peran node teu kabaca: %v", err)
+	}
+	if err := json.Unmarshal(data, &rf); err != nil {
+		return rf, fmt.Errorf("file peran node ruksak: %v", err)
+	}
+	if rf.Role != "INDUNG" && (rf.Role != "ANAK" || rf.Master == "") {
+		return rf, fmt.Errorf("file peran node ruksak: peran %q", rf.Role)
+	}
+	return rf, nil
+}
+
+// saveRole kedah disauran bari nyepeng rm.mu.
+func (rm *ReplicationManager) saveRole() error {
+	rf := roleFile{Role: "INDUNG"}
+	if rm.CurrentRole == RoleSlave {
+		rf = roleFile{Role: "ANAK", Master: rm.MasterHost}
+	}
+	data, err := json.Marshal(rf)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(rolePath()), 0755); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(rolePath(), data); err != nil {
+		return err
+	}
+	rm.roleErr = nil
+	return nil
+}
+
+// Start ngaktipkeun réplikasi pikeun prosés server: muka log, nyieun
+// baseline manifest, teras nyatet parobahan unggal interval. Mun node ieu
+// ANAK, langsung ngintil indungna.
+func (rm *ReplicationManager) Start(interval time.Duration) error {
+	if err := os.MkdirAll(filepath.Join(config.DataDir, config.ReplicationDir), 0755); err != nil {
+		return err
+	}
+	log, err := OpenLog(replicationPath("wal.log"), config.ReplicationLogLimit)
+	if err != nil {
+		return err
+	}
+	m, err := loadManifest(replicationPath("manifest.json"))
+	if err != nil {
+		return fmt.Errorf("manifest réplikasi ruksak: %v", err)
+	}
+
+	rm.mu.Lock()
+	rm.started = true
+	rm.log = log
+	rm.mu.Unlock()
+
+	rm.captureMu.Lock()
+	rm.manifest = m
+	rm.captureMu.Unlock()
+
+	// Manifest can aya: kaayaan ayeuna janten baseline, parobahan samemeh
+	// ieu dikintunkeun ngaliwatan snapshot.
+	if m == nil {
+		if err := rm.rebaseline(); err != nil {
+			return err
+		}
+	} else if err := rm.Capture(); err != nil {
+		return err
+	}
+
+	rm.mu.Lock()
+	if rm.CurrentRole == RoleSlave {
+		rm.startFollower()
+	}
+	rm.mu.Unlock()
+
+	go func() {
+		for range time.Tick(interval) {
+			if err := rm.Capture(); err != nil {
+				fmt.Println("⚠️ Réplikasi: gagal nyatet parobahan:", err)
+			}
+		}
+	}()
+	return nil
+}
+
+// rebaseline nganggap kaayaan file ayeuna geus kacatet, tanpa nyerat log.
+func (rm *ReplicationManager) rebaseline() error {
+	rm.captureMu.Lock()
+	defer rm.captureMu.Unlock()
+
+	current, _, err := scan(rm.manifest)
+	if err != nil {
+		return err
+	}
+	rm.manifest = current
+	return saveManifest(replicationPath("manifest.json"), current)
+}
+
+// Capture nyatet parobahan file ti saprak capture pamungkas kana log.
+// Teu ngalakukeun nanaon mun réplikasi teu aktip atanapi node ieu ANAK.
+func (rm *ReplicationManager) Capture() error {
+	rm.mu.RLock()
+	active := rm.started && rm.CurrentRole == RoleMaster
+	rm.mu.RUnlock()
+	if !active {
+		return nil
+	}
+
+	rm.captureMu.Lock()
+	defer rm.captureMu.Unlock()
+
+	current, contents, err := scan(rm.manifest)
+	if err != nil {
+		return err
+	}
+	entries := diff(rm.manifest, current, contents)
+	if len(entries) == 0 {
+		return nil
+	}
+	if err := rm.log.Append(entries); err != nil {
+		return err
+	}
+	rm.manifest = current
+	return saveManifest(replicationPath("manifest.json"), current)
+}
+
+// Promote ngajadikeun node ieu INDUNG. ANAK nu katinggaleun (atanapi nu
+// teu acan kantos nyambung ka indungna) ditolak kecuali force. Generasina
+// dinaékkeun sareng indung heubeul dipager mun tiasa dihontal. Hasilna
+// katerangan tambahan pikeun pangguna.
+func (rm *ReplicationManager) Promote(force bool) (string, error) {
 	rm.mu.Lock()
 	defer rm.mu.Unlock()
+
+	rm.nodeMu.Lock()
+	fenced := rm.node().Fenced
+	rm.nodeMu.Unlock()
+
+	if rm.roleErr != nil && !force {
+		return "", fmt.Errorf("⛔ %v; node ieu meureun ANAK. Anggo JADI INDUNG PAKSA mun yakin.", rm.roleErr)
+	}
+	if rm.CurrentRole == RoleMaster && !fenced && rm.roleErr == nil {
+		return "", nil
+	}
+	if fenced && !force {
+		return "", errors.New("⛔ Node ieu parantos dipager ku INDUNG generasi anyar. Anggo JADI INDUNG PAKSA mun yakin.")
+	}
+
+	oldMaster := rm.MasterHost
+	if rm.CurrentRole == RoleSlave && rm.follower != nil && !force {
+		fs := rm.follower.status()
+		switch {
+		case !fs.contacted:
+			return "", errors.New("⛔ ANAK can kantos nyambung ka indung ti saprak dimimitian, teu tiasa mastikeun datana lengkep. Anggo JADI INDUNG PAKSA mun yakin.")
+		case fs.masterLSN > fs.appliedLSN:
+			return "", fmt.Errorf("⛔ ANAK katinggaleun %d LSN (%d bait) ti indung. Antosan dugi ka sinkron atanapi anggo JADI INDUNG PAKSA.", fs.masterLSN-fs.appliedLSN, fs.lagBytes)
+		}
+	}
+
+	wasSlave := rm.CurrentRole == RoleSlave
 	rm.CurrentRole = RoleMaster
 	rm.MasterHost = ""
-	fmt.Println("👑 Node ieu ayeuna janten INDUNG (Read/Write Mode)")
+	rm.stopFollower()
+	if err := rm.saveRole(); err != nil {
+		return "", fmt.Errorf("gagal nyimpen peran node: %v", err)
+	}
+
+	rm.nodeMu.Lock()
+	n := rm.node()
+	n.Generation++
+	n.Fenced = false
+	gen := n.Generation
+	err := rm.saveNode()
+	rm.nodeMu.Unlock()
+	if err != nil {
+		return "", err
+	}
+	fmt.Printf("👑 Node ieu ayeuna janten INDUNG generasi %d (Read/Write Mode)\n", gen)
+
+	// Data ti indung heubeul janten baseline, teu dikintun deui salaku log.
+	if wasSlave && rm.started {
+		if err := rm.rebaseline(); err != nil {
+			fmt.Println("⚠️ Réplikasi: gagal nyieun baseline:", err)
+		}
+	}
+
+	if oldMaster == "" {
+		return fmt.Sprintf("Generasi %d.", gen), nil
+	}
+	if err := fence(oldMaster, gen); err != nil {
+		return fmt.Sprintf("Generasi %d. ⚠️ Indung heubeul %s teu tiasa dipager (%v); pastikeun éta dipareuman.", gen, oldMaster, err), nil
+	}
+	return fmt.Sprintf("Generasi %d. Indung heubeul %s parantos dipager.", gen, oldMaster), nil
 }
 
-func (rm *ReplicationManager) SetSlave(masterHost string) {
+func (rm *ReplicationManager) SetSlave(masterHost string) error {
+	if !TokenConfigured() {
+		return errors.New("⛔ Setel " + tokenEnv + " heula (sami sareng di INDUNG) samemeh JADI ANAK")
+	}
 	rm.mu.Lock()
 	defer rm.mu.Unlock()
 	rm.CurrentRole = RoleSlave
 	rm.MasterHost = masterHost
+	if err := rm.saveRole(); err != nil {
+		return fmt.Errorf("gagal nyimpen peran node: %v", err)
+	}
 	fmt.Println("👶 Node ieu ayeuna janten ANAK (Read Only Mode)")
+
+	// Node nu dipager tiasa gabung deui salaku ANAK.
+	rm.nodeMu.Lock()
+	if n := rm.node(); n.Fenced {
+		n.Fenced = false
+		rm.saveNode()
+	}
+	rm.nodeMu.Unlock()
 	fmt.Printf("📡 Ngintil ka Indung di: %s\n", masterHost)
+
+	rm.stopFollower()
+	if rm.started {
+		rm.startFollower()
+	}
+	return nil
+}
+
+// startFollower sareng stopFollower kedah disauran bari nyepeng rm.mu.
+func (rm *ReplicationManager) startFollower() {
+	ctx, cancel := context.WithCancel(context.Background())
+	rm.stopTail = cancel
+	rm.follower = newFollower(rm, rm.MasterHost)
+	go rm.follower.run(ctx)
+}
+
+func (rm *ReplicationManager) stopFollower() {
+	if rm.stopTail != nil {
+		rm.stopTail()
+		<-rm.follower.done
+		rm.stopTail = nil
+		rm.follower = nil
+	}
 }
 
 func (rm *ReplicationManager) CanWrite() error {
@@ -47,6 +338,15 @@ func (rm *ReplicationManager) CanWrite() error {
 	if rm.CurrentRole == RoleSlave {
 		return errors.New("⛔ AKSES DITOLAK: Node ieu mangrupikeun ANAK (Slave). Ngan tiasa maca (Read-Only).")
 	}
+	if rm.roleErr != nil {
+		return fmt.Errorf("⛔ AKSES DITOLAK: %v. Ngan tiasa maca (Read-Only) dugi ka peranna disetél deui.", rm.roleErr)
+	}
+
+	rm.nodeMu.Lock()
+	defer rm.nodeMu.Unlock()
+	if rm.node().Fenced {
+		return errors.New("⛔ AKSES DITOLAK: INDUNG ieu parantos dipager (aya INDUNG generasi anyar). Ngan tiasa maca (Read-Only).")
+	}
 	return nil
 }
 
@@ -54,4 +354,4 @@ func (rm *ReplicationManager) IsSlave() bool {
 	rm.mu.RLock()
 	defer rm.mu.RUnlock()
 	return rm.CurrentRole == RoleSlave
-}
\ No newline at end of file
+}
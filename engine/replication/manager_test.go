@@ -0,0 +1,126 @@
+package replication
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/febrd/maungdb/internal/config"
+)
+
+// TestRoleFile: peran nu disimpen saveRole dimuat deui ku Load saatos
+// restart; file nu teu aya dianggap INDUNG, file nu ruksak ngajantenkeun
+// node Read-Only.
+func TestRoleFile(t *testing.T) {
+	old := config.DataDir
+	config.DataDir = t.TempDir()
+	defer func() { config.DataDir = old }()
+
+	cases := []struct {
+		name     string
+		content  string // "" = file teu aya
+		role     NodeRole
+		master   string
+		wantErr  string
+		writable bool
+	}{
+		{"teu aya file", "", RoleMaster, "", "", true},
+		{"ANAK", `{"role":"ANAK","master":"indung:7070"}`, RoleSlave, "indung:7070", "", false},
+		{"INDUNG", `{"role":"INDUNG"}`, RoleMaster, "", "", true},
+		{"ANAK tanpa indung", `{"role":"ANAK"}`, RoleMaster, "", "ruksak", false},
+		{"peran teu dikenal", `{"role":"BAPA"}`, RoleMaster, "", "ruksak", false},
+		{"ruksak", `{"role":`, RoleMaster, "", "ruksak", false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			os.Remove(rolePath())
+			if c.content != "" {
+				os.MkdirAll(filepath.Dir(rolePath()), 0755)
+				if err := os.WriteFile(rolePath(), []byte(c.content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			rm := &ReplicationManager{CurrentRole: RoleMaster, nodeState: &nodeState{ID: "a", Generation: 1}}
+			err := rm.Load()
+			if c.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), c.wantErr) {
+					t.Fatalf("err = %v, want %q", err, c.wantErr)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if rm.CurrentRole != c.role || rm.MasterHost != c.master {
+				t.Errorf("peran = %v %q, want %v %q", rm.CurrentRole, rm.MasterHost, c.role, c.master)
+			}
+			if err := rm.CanWrite(); (err == nil) != c.writable {
+				t.Errorf("CanWrite = %v, want tiasa nyerat = %v", err, c.writable)
+			}
+		})
+	}
+
+	saved := &ReplicationManager{CurrentRole: RoleSlave, MasterHost: "10.0.0.1:7070"}
+	if err := saved.saveRole(); err != nil {
+		t.Fatal(err)
+	}
+	loaded := &ReplicationManager{CurrentRole: RoleMaster}
+	if err := loaded.Load(); err != nil || !loaded.IsSlave() || loaded.MasterHost != "10.0.0.1:7070" {
+		t.Errorf("Load saatos saveRole = %v %q, %v", loaded.CurrentRole, loaded.MasterHost, err)
+	}
+}
+
+func TestCanWrite(t *testing.T) {
+	cases := []struct {
+		name string
+		rm   *ReplicationManager
+		want string // "" = kénging nyerat
+	}{
+		{"INDUNG", &ReplicationManager{CurrentRole: RoleMaster, nodeState: &nodeState{ID: "a", Generation: 1}}, ""},
+		{"ANAK", &ReplicationManager{CurrentRole: RoleSlave, nodeState: &nodeState{ID: "a", Generation: 1}}, "ANAK"},
+		{"INDUNG dipager", &ReplicationManager{CurrentRole: RoleMaster, nodeState: &nodeState{ID: "a", Generation: 2, Fenced: true}}, "dipager"},
+	}
+	for _, c := range cases {
+		err := c.rm.CanWrite()
+		if (c.want == "") != (err == nil) || (err != nil && !strings.Contains(err.Error(), c.want)) {
+			t.Errorf("%s: CanWrite = %v, want %q", c.name, err, c.want)
+		}
+	}
+}
+
+// TestCorruptRoleFileRecovery: node nu file peranna ruksak tetep Read-Only
+// dugi ka peranna disetél deui sacara eksplisit.
+func TestCorruptRoleFileRecovery(t *testing.T) {
+	old := config.DataDir
+	config.DataDir = t.TempDir()
+	defer func() { config.DataDir = old }()
+
+	// Diréktori di tempat file: teu kabaca.
+	if err := os.MkdirAll(rolePath(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	rm := &ReplicationManager{CurrentRole: RoleMaster, nodeState: &nodeState{ID: "a", Generation: 1}}
+	if err := rm.Load(); err == nil || !strings.Contains(err.Error(), "teu kabaca") {
+		t.Fatalf("Load = %v, want teu kabaca", err)
+	}
+	if err := os.Remove(rolePath()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := rm.Promote(false); err == nil || !strings.Contains(err.Error(), "PAKSA") {
+		t.Fatalf("Promote tanpa PAKSA = %v, want ditolak", err)
+	}
+	if rm.CanWrite() == nil {
+		t.Fatal("node kedah tetep Read-Only")
+	}
+	if _, err := rm.Promote(true); err != nil {
+		t.Fatal(err)
+	}
+	if err := rm.CanWrite(); err != nil {
+		t.Errorf("saatos JADI INDUNG PAKSA: CanWrite = %v", err)
+	}
+	loaded := &ReplicationManager{CurrentRole: RoleSlave}
+	if err := loaded.Load(); err != nil || loaded.IsSlave() {
+		t.Errorf("file peran saatos PAKSA = %v, %v", loaded.CurrentRole, err)
+	}
+}
@@ -0,0 +1,98 @@
+package replication
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/febrd/maungdb/internal/config"
+)
+
+// nodeState disimpen dina _replikasi/node.json (teu diréplikasi).
+// Generation naék unggal aya ANAK nu dipromosikeun; INDUNG nu
+// generasina kalangkungan dipager (Fenced) sangkan teu nampi tulisan deui.
+type nodeState struct {
+	ID         string `json:"id"`
+	Generation uint64 `json:"generation"`
+	Fenced     bool   `json:"fenced,omitempty"`
+}
+
+// node ngamuat kaayaan node sakali ti disk. Kedah disauran bari nyepeng
+// rm.nodeMu.
+func (rm *ReplicationManager) node() *nodeState {
+	if rm.nodeState != nil {
+		return rm.nodeState
+	}
+
+	state := &nodeState{}
+	if data, err := os.ReadFile(replicationPath("node.json")); err == nil {
+		json.Unmarshal(data, state)
+	}
+	if state.Generation == 0 {
+		state.Generation = 1
+	}
+	rm.nodeState = state
+
+	if state.ID == "" {
+		buf := make([]byte, 6)
+		rand.Read(buf)
+		state.ID = hex.EncodeToString(buf)
+		rm.saveNode()
+	}
+	return state
+}
+
+// saveNode kedah disauran bari nyepeng rm.nodeMu.
+func (rm *ReplicationManager) saveNode() error {
+	data, err := json.Marshal(rm.node())
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Join(config.DataDir, config.ReplicationDir), 0755); err != nil {
+		return err
+	}
+	return writeFileAtomic(replicationPath("node.json"), data)
+}
+
+func (rm *ReplicationManager) generation() uint64 {
+	rm.nodeMu.Lock()
+	defer rm.nodeMu.Unlock()
+	return rm.node().Generation
+}
+
+// observeMaster dipanggil ANAK unggal nampi waleran indung. Generasi indung
+// nu langkung heubeul ti nu kantos katingal hartosna indung éta parantos
+// diganti, janten teu kénging diintil.
+func (rm *ReplicationManager) observeMaster(gen uint64) error {
+	rm.nodeMu.Lock()
+	defer rm.nodeMu.Unlock()
+
+	n := rm.node()
+	switch {
+	case gen < n.Generation:
+		return fmt.Errorf("indung generasi %d langkung heubeul ti generasi %d; indung éta parantos diganti", gen, n.Generation)
+	case gen > n.Generation:
+		n.Generation = gen
+		return rm.saveNode()
+	}
+	return nil
+}
+
+// observePeer dipanggil INDUNG nalika nampi pamundut ti node sanés. Mun
+// node éta terang generasi nu langkung énggal, INDUNG ieu dipager.
+func (rm *ReplicationManager) observePeer(gen uint64) bool {
+	rm.nodeMu.Lock()
+	defer rm.nodeMu.Unlock()
+
+	n := rm.node()
+	if gen > n.Generation {
+		n.Generation = gen
+		n.Fenced = true
+		rm.saveNode()
+		fmt.Printf("🚧 Node ieu dipager: aya INDUNG generasi %d\n", gen)
+	}
+	return n.Fenced
+}
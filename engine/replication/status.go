@@ -0,0 +1,102 @@
+package replication
+
+import (
+	"sort"
+	"time"
+)
+
+// Status: kaayaan réplikasi hiji node pikeun TINGALI REPLIKASI sareng
+// GET /replikasi/status. Dina INDUNG, LSN nyaéta LSN log pamungkas sareng
+// Replicas ngeusi ANAK nu nuju ngintil; dina ANAK, LSN nu parantos
+// diterapkeun sareng telatna ti indung.
+type Status struct {
+	Node        string          `json:"node"`
+	Role        string          `json:"role"`
+	Generation  uint64          `json:"generation"`
+	Fenced      bool            `json:"fenced"`
+	Master      string          `json:"master,omitempty"`
+	LSN         uint64          `json:"lsn"`
+	MasterLSN   uint64          `json:"master_lsn,omitempty"`
+	LagBytes    int64           `json:"lag_bytes"`
+	LagSeconds  float64         `json:"lag_seconds"`
+	Connected   bool            `json:"connected"`
+	LastContact *time.Time      `json:"last_contact,omitempty"`
+	LastError   string          `json:"last_error,omitempty"`
+	Replicas    []ReplicaStatus `json:"replicas,omitempty"`
+}
+
+type ReplicaStatus struct {
+	Node       string    `json:"node"`
+	Addr       string    `json:"addr"`
+	LSN        uint64    `json:"lsn"`
+	AckedLSN   uint64    `json:"acked_lsn"`
+	LagBytes   int64     `json:"lag_bytes"`
+	LagSeconds float64   `json:"lag_seconds"`
+	Connected  bool      `json:"connected"`
+	LastSeen   time.Time `json:"last_seen"`
+}
+
+// peerTimeout: ANAK nu teu nuju long-poll dianggap pegat mun teu aya
+// pamundut deui salami ieu.
+const peerTimeout = 5 * time.Second
+
+func lagSeconds(pendingSince int64, now time.Time) float64 {
+	if pendingSince == 0 {
+		return 0
+	}
+	return now.Sub(time.Unix(0, pendingSince)).Seconds()
+}
+
+// Status ngumpulkeun kaayaan réplikasi node ieu.
+func (rm *ReplicationManager) Status() Status {
+	now := time.Now()
+
+	rm.mu.RLock()
+	role, master, started, log, f := rm.CurrentRole, rm.MasterHost, rm.started, rm.log, rm.follower
+	rm.mu.RUnlock()
+
+	rm.nodeMu.Lock()
+	n := *rm.node()
+	rm.nodeMu.Unlock()
+
+	st := Status{Node: n.ID, Role: "INDUNG", Generation: n.Generation, Fenced: n.Fenced}
+
+	if role == RoleSlave {
+		st.Role = "ANAK"
+		st.Master = master
+		if f != nil {
+			fs := f.status()
+			st.LSN, st.MasterLSN = fs.appliedLSN, fs.masterLSN
+			st.LagBytes, st.LagSeconds = fs.lagBytes, lagSeconds(fs.pendingSince, now)
+			st.Connected, st.LastError = fs.connected, fs.lastError
+			if fs.contacted {
+				st.LastContact = &fs.lastContact
+			}
+		}
+		return st
+	}
+
+	if !started {
+		return st
+	}
+	st.LSN = log.LastLSN()
+	st.Connected = true
+
+	rm.peersMu.Lock()
+	for _, p := range rm.peers {
+		if now.Sub(p.LastSeen) > time.Hour {
+			delete(rm.peers, p.ID)
+			continue
+		}
+		bytes, since := log.Lag(p.LSN)
+		st.Replicas = append(st.Replicas, ReplicaStatus{
+			Node: p.ID, Addr: p.Addr, LSN: p.LSN, AckedLSN: p.Acked,
+			LagBytes: bytes, LagSeconds: lagSeconds(since, now),
+			Connected: p.Polling || now.Sub(p.LastSeen) <= peerTimeout, LastSeen: p.LastSeen,
+		})
+	}
+	rm.peersMu.Unlock()
+
+	sort.Slice(st.Replicas, func(i, j int) bool { return st.Replicas[i].Node < st.Replicas[j].Node })
+	return st
+}
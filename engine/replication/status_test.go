@@ -0,0 +1,191 @@
+package replication
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/febrd/maungdb/internal/config"
+)
+
+// newTestMaster: INDUNG nu parantos jalan kalayan log ngeusi n entri.
+func newTestMaster(t *testing.T, n int) *ReplicationManager {
+	t.Helper()
+	t.Setenv(tokenEnv, "rusiah")
+	log, err := OpenLog(filepath.Join(t.TempDir(), "wal.log"), 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < n; i++ {
+		if err := log.Append([]Entry{{Op: OpWrite, Path: fmt.Sprintf("f%d", i)}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return &ReplicationManager{
+		CurrentRole: RoleMaster,
+		started:     true,
+		log:         log,
+		nodeState:   &nodeState{ID: "indung", Generation: 1},
+	}
+}
+
+func TestLagSeconds(t *testing.T) {
+	now := time.Unix(100, 0)
+	cases := []struct {
+		since int64
+		want  float64
+	}{
+		{0, 0},
+		{time.Unix(100, 0).UnixNano(), 0},
+		{time.Unix(98, 500000000).UnixNano(), 1.5},
+	}
+	for _, c := range cases {
+		if got := lagSeconds(c.since, now); got != c.want {
+			t.Errorf("lagSeconds(%d) = %v, want %v", c.since, got, c.want)
+		}
+	}
+}
+
+// TestStatusMaster: INDUNG ngalaporkeun unggal ANAK (diurutkeun), telatna,
+// sareng miceun ANAK nu tos sajam teu katingal.
+func TestStatusMaster(t *testing.T) {
+	rm := newTestMaster(t, 3)
+	now := time.Now()
+	rm.peers = map[string]*peer{
+		"b": {ID: "b", Addr: "10.0.0.2", LSN: 1, Acked: 1, LastSeen: now.Add(-10 * time.Second)},
+		"a": {ID: "a", Addr: "10.0.0.1", LSN: 3, Acked: 3, Polling: true, LastSeen: now},
+		"c": {ID: "c", Addr: "10.0.0.3", LSN: 0, LastSeen: now.Add(-2 * time.Hour)},
+	}
+
+	st := rm.Status()
+	if st.Role != "INDUNG" || st.LSN != 3 || !st.Connected || st.Generation != 1 {
+		t.Fatalf("status = %+v", st)
+	}
+	if len(st.Replicas) != 2 {
+		t.Fatalf("replicas = %+v, want a sareng b", st.Replicas)
+	}
+	if _, ok := rm.peers["c"]; ok {
+		t.Error("ANAK c kedahna dipiceun")
+	}
+
+	cases := []struct {
+		node      string
+		lsn       uint64
+		lagging   bool
+		connected bool
+	}{
+		{"a", 3, false, true},
+		{"b", 1, true, false},
+	}
+	for i, c := range cases {
+		r := st.Replicas[i]
+		if r.Node != c.node || r.LSN != c.lsn || r.AckedLSN != c.lsn || r.Connected != c.connected {
+			t.Errorf("replica %d = %+v, want %+v", i, r, c)
+		}
+		if lagging := r.LagBytes > 0 && r.LagSeconds >= 0; lagging != c.lagging {
+			t.Errorf("%s: lag = %d bait, %v detik", r.Node, r.LagBytes, r.LagSeconds)
+		}
+	}
+}
+
+func TestStatusSlave(t *testing.T) {
+	f := newFollower(nil, "indung:7070")
+	f.followerStatus = followerStatus{
+		contacted: true, connected: true, appliedLSN: 5, masterLSN: 8,
+		lagBytes: 120, pendingSince: time.Now().Add(-2 * time.Second).UnixNano(),
+		lastContact: time.Now(),
+	}
+	rm := &ReplicationManager{
+		CurrentRole: RoleSlave,
+		MasterHost:  "indung:7070",
+		follower:    f,
+		nodeState:   &nodeState{ID: "anak", Generation: 2},
+	}
+
+	st := rm.Status()
+	if st.Role != "ANAK" || st.Master != "indung:7070" || st.LSN != 5 || st.MasterLSN != 8 ||
+		st.LagBytes != 120 || st.LagSeconds < 2 || !st.Connected || st.LastContact == nil || st.Generation != 2 {
+		t.Errorf("status = %+v", st)
+	}
+}
+
+// TestPromote: ANAK nu katinggaleun atanapi can kantos nyambung ditolak
+// kecuali PAKSA, sareng promosi naékkeun generasi sarta magar indung heubeul.
+func TestPromote(t *testing.T) {
+	t.Setenv(tokenEnv, "rusiah")
+	dataDir := config.DataDir
+	config.DataDir = t.TempDir()
+	defer func() { config.DataDir = dataDir }()
+
+	old := &ReplicationManager{CurrentRole: RoleMaster, nodeState: &nodeState{ID: "heubeul", Generation: 1}}
+	srv := httptest.NewServer(http.HandlerFunc(old.HandleFence))
+	defer srv.Close()
+
+	slave := func(fs followerStatus) *ReplicationManager {
+		f := newFollower(nil, srv.URL)
+		f.followerStatus = fs
+		return &ReplicationManager{
+			CurrentRole: RoleSlave,
+			MasterHost:  srv.URL,
+			follower:    f,
+			nodeState:   &nodeState{ID: "anak", Generation: 1},
+		}
+	}
+	synced := followerStatus{contacted: true, appliedLSN: 4, masterLSN: 4}
+
+	cases := []struct {
+		name    string
+		rm      *ReplicationManager
+		force   bool
+		want    string // potongan pesen atanapi error
+		wantErr bool
+		gen     uint64
+	}{
+		{"INDUNG biasa", &ReplicationManager{CurrentRole: RoleMaster, nodeState: &nodeState{ID: "x", Generation: 1}}, false, "", false, 1},
+		{"INDUNG dipager", &ReplicationManager{CurrentRole: RoleMaster, nodeState: &nodeState{ID: "x", Generation: 3, Fenced: true}}, false, "dipager", true, 3},
+		{"INDUNG dipager PAKSA", &ReplicationManager{CurrentRole: RoleMaster, nodeState: &nodeState{ID: "x", Generation: 3, Fenced: true}}, true, "Generasi 4.", false, 4},
+		{"ANAK can nyambung", slave(followerStatus{}), false, "can kantos nyambung", true, 1},
+		{"ANAK katinggaleun", slave(followerStatus{contacted: true, appliedLSN: 2, masterLSN: 4, lagBytes: 64}), false, "katinggaleun 2 LSN (64 bait)", true, 1},
+		{"ANAK katinggaleun PAKSA", slave(followerStatus{contacted: true, appliedLSN: 2, masterLSN: 4}), true, "parantos dipager", false, 2},
+		{"ANAK sinkron", slave(synced), false, "parantos dipager", false, 2},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			old.nodeState = &nodeState{ID: "heubeul", Generation: 1}
+			role := c.rm.CurrentRole
+
+			msg, err := c.rm.Promote(c.force)
+			if c.wantErr {
+				if err == nil || !strings.Contains(err.Error(), c.want) {
+					t.Fatalf("err = %v, want %q", err, c.want)
+				}
+				if c.rm.CurrentRole != role {
+					t.Error("peran robih sanajan ditolak")
+				}
+			} else {
+				if err != nil || !strings.Contains(msg, c.want) {
+					t.Fatalf("Promote = %q, %v; want %q", msg, err, c.want)
+				}
+				if c.rm.IsSlave() || c.rm.CanWrite() != nil {
+					t.Error("node teu janten INDUNG nu tiasa nyerat")
+				}
+			}
+			if gen := c.rm.generation(); gen != c.gen {
+				t.Errorf("generasi = %d, want %d", gen, c.gen)
+			}
+			if fenced := old.nodeState.Fenced; fenced != strings.Contains(c.want, "parantos dipager") {
+				t.Errorf("indung heubeul dipager = %v", fenced)
+			}
+		})
+	}
+
+	data, err := os.ReadFile(rolePath())
+	if err != nil || !strings.Contains(string(data), `"INDUNG"`) {
+		t.Errorf("file peran = %s, %v", data, err)
+	}
+}
@@ -0,0 +1,329 @@
+package replication
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/febrd/maungdb/internal/config"
+)
+
+// Token réplikasi (wajib): indung ngan ngalayanan anak nu ngirim
+// MAUNG_REPLIKASI_TOKEN nu sami dina header X-Maung-Token. Mun can disetel,
+// endpoint réplikasi ditutup sabab snapshot ngandung _system (pangguna, hak).
+const (
+	tokenEnv         = "MAUNG_REPLIKASI_TOKEN"
+	tokenHeader      = "X-Maung-Token"
+	generationHeader = "X-Maung-Generasi"
+	nodeHeader       = "X-Maung-Node"
+
+	walPollTimeout = 10 * time.Second
+	walBatchSize   = 512
+	walBatchBytes  = 8 << 20
+)
+
+type snapshotFile struct {
+	Path string `json:"path"`
+	Dir  bool   `json:"dir,omitempty"`
+	Data []byte `json:"data,omitempty"`
+}
+
+type snapshotResponse struct {
+	LSN   uint64         `json:"lsn"`
+	Files []snapshotFile `json:"files"`
+}
+
+// walResponse: LagBytes sareng PendingSince ngajelaskeun log nu can
+// kakirim saatos entri pamungkas dina batch ieu.
+type walResponse struct {
+	LSN          uint64  `json:"lsn"`
+	Entries      []Entry `json:"entries"`
+	LagBytes     int64   `json:"lag_bytes"`
+	PendingSince int64   `json:"pending_since,omitempty"`
+}
+
+// peer: ANAK nu nuju ngintil node ieu, dicatet tina pamundut /replikasi/wal.
+// Polling leres salami long-poll na masih jalan. Acked nyaéta LSN nu
+// dikonfirmasi ku ANAK (POST /replikasi/ack) saatos disimpen ka disk.
+type peer struct {
+	ID       string
+	Addr     string
+	LSN      uint64
+	Acked    uint64
+	Polling  bool
+	LastSeen time.Time
+}
+
+func peerID(r *http.Request) string {
+	if id := r.Header.Get(nodeHeader); id != "" {
+		return id
+	}
+	return r.RemoteAddr
+}
+
+// trackPeer ngapdet (atanapi nambihan) peer; peersMu kedah dicekel.
+func (rm *ReplicationManager) trackPeer(r *http.Request, lsn uint64, polling bool) *peer {
+	id := peerID(r)
+	if rm.peers == nil {
+		rm.peers = make(map[string]*peer)
+	}
+	p, ok := rm.peers[id]
+	if !ok {
+		p = &peer{ID: id}
+		rm.peers[id] = p
+	}
+	p.Addr, p.LSN, p.Polling, p.LastSeen = r.RemoteAddr, lsn, polling, time.Now()
+	return p
+}
+
+func (rm *ReplicationManager) pollPeer(r *http.Request, lsn uint64, polling bool) {
+	rm.peersMu.Lock()
+	defer rm.peersMu.Unlock()
+	rm.trackPeer(r, lsn, polling)
+}
+
+// ackPeer nyatet konfirmasi ANAK sareng ngahudangkeun commit sinkron nu
+// nuju ngantosan.
+func (rm *ReplicationManager) ackPeer(r *http.Request, lsn uint64) {
+	rm.peersMu.Lock()
+	defer rm.peersMu.Unlock()
+	p, ok := rm.peers[peerID(r)]
+	if !ok {
+		p = rm.trackPeer(r, lsn, false)
+	}
+	if lsn > p.Acked {
+		p.Acked = lsn
+	}
+	p.LastSeen = time.Now()
+
+	if rm.ackNotify != nil {
+		close(rm.ackNotify)
+		rm.ackNotify = nil
+	}
+}
+
+// peerGone: ANAK megatkeun long-poll (contona prosésna eureun).
+func (rm *ReplicationManager) peerGone(r *http.Request) {
+	rm.peersMu.Lock()
+	defer rm.peersMu.Unlock()
+	if p, ok := rm.peers[peerID(r)]; ok {
+		p.Polling = false
+	}
+}
+
+func TokenConfigured() bool {
+	return os.Getenv(tokenEnv) != ""
+}
+
+// authorize mariksa token réplikasi sareng ngawaler error mun teu lulus.
+func authorize(w http.ResponseWriter, r *http.Request) bool {
+	token := os.Getenv(tokenEnv)
+	if token == "" {
+		http.Error(w, "endpoint réplikasi ditutup: "+tokenEnv+" can disetel", http.StatusServiceUnavailable)
+		return false
+	}
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get(tokenHeader)), []byte(token)) != 1 {
+		http.Error(w, "token réplikasi teu valid", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+// servable mariksa naha node ieu tiasa ngalayanan anak.
+func (rm *ReplicationManager) servable(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method != method {
+		http.Error(w, "Method kudu "+method, http.StatusMethodNotAllowed)
+		return false
+	}
+	if !authorize(w, r) {
+		return false
+	}
+
+	rm.mu.RLock()
+	ok := rm.started && rm.CurrentRole == RoleMaster
+	rm.mu.RUnlock()
+	if !ok {
+		http.Error(w, "node ieu sanés INDUNG", http.StatusServiceUnavailable)
+		return false
+	}
+
+	gen, _ := strconv.ParseUint(r.Header.Get(generationHeader), 10, 64)
+	if rm.observePeer(gen) {
+		http.Error(w, "INDUNG ieu parantos dipager", http.StatusServiceUnavailable)
+		return false
+	}
+	w.Header().Set(generationHeader, strconv.FormatUint(rm.generation(), 10))
+	return true
+}
+
+// HandleSnapshot (GET /replikasi/snapshot) ngirim sadaya file nu
+// diréplikasi sareng LSN nu saluyu. Parobahan nu lumangsung bari maca
+// bakal kakirim deui dina log, sareng nerapkeunana deui teu aya pangaruhna.
+func (rm *ReplicationManager) HandleSnapshot(w http.ResponseWriter, r *http.Request) {
+	if !rm.servable(w, r, http.MethodGet) {
+		return
+	}
+	if err := rm.Capture(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rm.captureMu.Lock()
+	resp := snapshotResponse{LSN: rm.log.LastLSN(), Files: []snapshotFile{}}
+	var paths []string
+	for rel := range rm.manifest {
+		paths = append(paths, rel)
+	}
+	sort.Strings(paths)
+	for _, rel := range paths {
+		if rm.manifest[rel].Dir {
+			resp.Files = append(resp.Files, snapshotFile{Path: rel, Dir: true})
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(config.DataDir, filepath.FromSlash(rel)))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			rm.captureMu.Unlock()
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		resp.Files = append(resp.Files, snapshotFile{Path: rel, Data: data})
+	}
+	rm.captureMu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// HandleWAL (GET /replikasi/wal?dari=<lsn>) ngirim entri saatos LSN éta.
+// Mun can aya, ngantosan dugi ka walPollTimeout (long-poll), kecuali
+// tunggu=0 (pamundut munggaran saatos nyambung). 409 hartosna
+// anak kedah snapshot deui.
+func (rm *ReplicationManager) HandleWAL(w http.ResponseWriter, r *http.Request) {
+	if !rm.servable(w, r, http.MethodGet) {
+		return
+	}
+	from, err := strconv.ParseUint(r.URL.Query().Get("dari"), 10, 64)
+	if err != nil {
+		http.Error(w, "parameter 'dari' kedah LSN", http.StatusBadRequest)
+		return
+	}
+
+	rm.pollPeer(r, from, true)
+	if r.URL.Query().Get("tunggu") != "0" {
+		rm.log.Wait(from, walPollTimeout, r.Context().Done())
+	}
+	if r.Context().Err() != nil {
+		rm.peerGone(r)
+		return
+	}
+	entries, err := rm.log.Read(from, walBatchSize, walBatchBytes)
+	if errors.Is(err, ErrTooOld) || errors.Is(err, ErrAhead) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resp := walResponse{LSN: rm.log.LastLSN(), Entries: entries}
+	sent := from
+	if len(entries) > 0 {
+		sent = entries[len(entries)-1].LSN
+	}
+	resp.LagBytes, resp.PendingSince = rm.log.Lag(sent)
+	rm.pollPeer(r, from, false)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// HandleAck (POST /replikasi/ack?lsn=<lsn>) dikirim ku ANAK saatos log dugi
+// ka lsn diterapkeun sareng di-fsync. Ngan ieu nu diitung ku commit sinkron.
+func (rm *ReplicationManager) HandleAck(w http.ResponseWriter, r *http.Request) {
+	if !rm.servable(w, r, http.MethodPost) {
+		return
+	}
+	lsn, err := strconv.ParseUint(r.URL.Query().Get("lsn"), 10, 64)
+	if err != nil {
+		http.Error(w, "parameter 'lsn' kedah LSN", http.StatusBadRequest)
+		return
+	}
+	if last := rm.log.LastLSN(); lsn > last {
+		http.Error(w, fmt.Sprintf("LSN %d langkung ageung ti log indung (%d)", lsn, last), http.StatusConflict)
+		return
+	}
+	rm.ackPeer(r, lsn)
+	w.WriteHeader(http.StatusOK)
+}
+
+// HandleFence (POST /replikasi/pager) dipanggil ku ANAK nu nembé
+// dipromosikeun: mun generasina langkung énggal, node ieu dipager.
+func (rm *ReplicationManager) HandleFence(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method kudu POST", http.StatusMethodNotAllowed)
+		return
+	}
+	if !authorize(w, r) {
+		return
+	}
+	gen, err := strconv.ParseUint(r.Header.Get(generationHeader), 10, 64)
+	if err != nil {
+		http.Error(w, "header "+generationHeader+" kedah angka", http.StatusBadRequest)
+		return
+	}
+	if !rm.observePeer(gen) {
+		http.Error(w, "generasi teu langkung énggal ti node ieu", http.StatusConflict)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// HandleStatus (GET /replikasi/status) ngirim kaayaan réplikasi node ieu.
+func (rm *ReplicationManager) HandleStatus(w http.ResponseWriter, r *http.Request) {
+	if !authorize(w, r) {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(rm.Status())
+}
+
+// fence mundut indung heubeul di host sangkan eureun nampi tulisan.
+func fence(host string, gen uint64) error {
+	req, err := http.NewRequest(http.MethodPost, masterURL(host)+"/replikasi/pager", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(generationHeader, strconv.FormatUint(gen, 10))
+	if token := os.Getenv(tokenEnv); token != "" {
+		req.Header.Set(tokenHeader, token)
+	}
+
+	resp, err := (&http.Client{Timeout: 3 * time.Second}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("indung ngawaler %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func masterURL(host string) string {
+	if !strings.Contains(host, "://") {
+		host = "http://" + host
+	}
+	return strings.TrimRight(host, "/")
+}
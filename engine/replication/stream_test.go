@@ -0,0 +1,47 @@
+package replication
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthorize(t *testing.T) {
+	cases := []struct {
+		name   string
+		env    string
+		header string
+		want   int
+	}{
+		{"token can disetel", "", "", http.StatusServiceUnavailable},
+		{"token can disetel, header dikirim", "", "naon waé", http.StatusServiceUnavailable},
+		{"header kosong", "rusiah", "", http.StatusUnauthorized},
+		{"header salah", "rusiah", "rusiah2", http.StatusUnauthorized},
+		{"header leres", "rusiah", "rusiah", http.StatusOK},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Setenv(tokenEnv, c.env)
+			r := httptest.NewRequest(http.MethodGet, "/replikasi/snapshot", nil)
+			if c.header != "" {
+				r.Header.Set(tokenHeader, c.header)
+			}
+			w := httptest.NewRecorder()
+			ok := authorize(w, r)
+			if ok != (c.want == http.StatusOK) || w.Code != c.want {
+				t.Errorf("authorize = %v (%d), want %d", ok, w.Code, c.want)
+			}
+		})
+	}
+}
+
+func TestSetSlaveRequiresToken(t *testing.T) {
+	t.Setenv(tokenEnv, "")
+	rm := &ReplicationManager{CurrentRole: RoleMaster}
+	if err := rm.SetSlave("localhost:7070"); err == nil {
+		t.Fatal("JADI ANAK tanpa token kedah ditolak")
+	}
+	if rm.CurrentRole != RoleMaster {
+		t.Errorf("peran robih jadi %v", rm.CurrentRole)
+	}
+}
@@ -0,0 +1,114 @@
+package replication
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/febrd/maungdb/internal/config"
+)
+
+// DefaultSyncTimeout dipaké mun ATUR SINKRON teu nyebutkeun TUNGGU.
+const DefaultSyncTimeout = 5 * time.Second
+
+const syncFile = "sinkron.json"
+
+// SyncPolicy: aturan réplikasi sinkron hiji database, disimpen dina
+// db_<ngaran>/sinkron.json (ikut diréplikasi, janten tetep lumaku saatos
+// failover). Replicas 0 hartosna asinkron.
+type SyncPolicy struct {
+	Replicas  int     `json:"replicas"`
+	Timeout   float64 `json:"timeout_seconds,omitempty"`
+	OnTimeout string  `json:"on_timeout,omitempty"`
+}
+
+// TimeoutDuration mulihkeun Timeout, atanapi DefaultSyncTimeout.
+func (p SyncPolicy) TimeoutDuration() time.Duration {
+	if p.Timeout <= 0 {
+		return DefaultSyncTimeout
+	}
+	return time.Duration(p.Timeout * float64(time.Second))
+}
+
+func syncPath(db string) string {
+	return filepath.Join(config.DataDir, "db_"+db, syncFile)
+}
+
+// LoadSyncPolicy maca aturan sinkron database; kosong mun teu aya.
+func LoadSyncPolicy(db string) (SyncPolicy, error) {
+	var p SyncPolicy
+	data, err := os.ReadFile(syncPath(db))
+	if os.IsNotExist(err) {
+		return p, nil
+	}
+	if err != nil {
+		return p, err
+	}
+	err = json.Unmarshal(data, &p)
+	return p, err
+}
+
+// SaveSyncPolicy nyimpen aturan sinkron; Replicas 0 miceun filena.
+func SaveSyncPolicy(db string, p SyncPolicy) error {
+	if p.Replicas == 0 {
+		if err := os.Remove(syncPath(db)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(syncPath(db), data)
+}
+
+// Serving: leres mun prosés ieu INDUNG nu ngalayanan ANAK (maung server).
+func (rm *ReplicationManager) Serving() bool {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+	return rm.started && rm.CurrentRole == RoleMaster
+}
+
+// LastLSN mulihkeun LSN log pamungkas (0 mun réplikasi teu aktip).
+func (rm *ReplicationManager) LastLSN() uint64 {
+	rm.mu.RLock()
+	log := rm.log
+	rm.mu.RUnlock()
+	if log == nil {
+		return 0
+	}
+	return log.LastLSN()
+}
+
+// WaitForReplicas ngantosan dugi ka sahenteuna n ANAK ngirim konfirmasi
+// (HandleAck) yén log dugi ka LSN lsn parantos diterapkeun sareng di-fsync,
+// atanapi timeout. Pamundut /replikasi/wal wungkul teu diitung. Mulihkeun
+// jumlah ANAK nu parantos mastikeun.
+func (rm *ReplicationManager) WaitForReplicas(lsn uint64, n int, timeout time.Duration) int {
+	deadline := time.After(timeout)
+	for {
+		rm.peersMu.Lock()
+		acked := 0
+		for _, p := range rm.peers {
+			if p.Acked >= lsn {
+				acked++
+			}
+		}
+		if rm.ackNotify == nil {
+			rm.ackNotify = make(chan struct{})
+		}
+		ch := rm.ackNotify
+		rm.peersMu.Unlock()
+
+		if acked >= n {
+			return acked
+		}
+		select {
+		case <-ch:
+		case <-deadline:
+			return acked
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package replication
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func replicaRequest(method, path, node string) *http.Request {
+	r := httptest.NewRequest(method, path, nil)
+	r.Header.Set(tokenHeader, "rusiah")
+	r.Header.Set(nodeHeader, node)
+	r.Header.Set(generationHeader, "1")
+	return r
+}
+
+func TestHandleAck(t *testing.T) {
+	rm := newTestMaster(t, 3)
+	cases := []struct {
+		name   string
+		method string
+		query  string
+		want   int
+		acked  uint64
+	}{
+		{"kedah POST", http.MethodGet, "lsn=1", http.StatusMethodNotAllowed, 0},
+		{"lsn sanés angka", http.MethodPost, "lsn=x", http.StatusBadRequest, 0},
+		{"lsn ngalangkungan log", http.MethodPost, "lsn=4", http.StatusConflict, 0},
+		{"konfirmasi", http.MethodPost, "lsn=2", http.StatusOK, 2},
+		{"konfirmasi heubeul teu mundur", http.MethodPost, "lsn=1", http.StatusOK, 2},
+		{"konfirmasi pamungkas", http.MethodPost, "lsn=3", http.StatusOK, 3},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			rm.HandleAck(w, replicaRequest(c.method, "/replikasi/ack?"+c.query, "anak1"))
+			if w.Code != c.want {
+				t.Fatalf("status = %d (%s), want %d", w.Code, w.Body, c.want)
+			}
+			var acked uint64
+			if p := rm.peers["anak1"]; p != nil {
+				acked = p.Acked
+			}
+			if acked != c.acked {
+				t.Errorf("acked = %d, want %d", acked, c.acked)
+			}
+		})
+	}
+}
+
+// TestWaitForReplicasNeedsAck: pamundut /replikasi/wal wungkul teu diitung
+// salaku konfirmasi; ngan ack saatos fsync.
+func TestWaitForReplicasNeedsAck(t *testing.T) {
+	rm := newTestMaster(t, 2)
+
+	w := httptest.NewRecorder()
+	rm.HandleWAL(w, replicaRequest(http.MethodGet, "/replikasi/wal?dari=2&tunggu=0", "anak1"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("wal: %d %s", w.Code, w.Body)
+	}
+	if got := rm.WaitForReplicas(2, 1, 50*time.Millisecond); got != 0 {
+		t.Fatalf("polling diitung salaku konfirmasi: %d", got)
+	}
+
+	done := make(chan int)
+	go func() { done <- rm.WaitForReplicas(2, 1, 5*time.Second) }()
+	time.Sleep(20 * time.Millisecond)
+	rm.HandleAck(httptest.NewRecorder(), replicaRequest(http.MethodPost, "/replikasi/ack?lsn=2", "anak1"))
+	select {
+	case got := <-done:
+		if got != 1 {
+			t.Errorf("WaitForReplicas = %d, want 1", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("ack teu ngahudangkeun WaitForReplicas")
+	}
+}
@@ -0,0 +1,140 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/febrd/maungdb/internal/config"
+)
+
+// Katalog: sadaya definisi tabel hiji database disimpen dina hiji file JSON
+// (db_<nama>/_schema/katalog.json) nu gaduh nomer vérsi. Vérsi 1 nyaéta
+// format heubeul (hiji file .schema per tabel) nu dimigrasikeun otomatis.
+const CatalogVersion = 2
+
+type catalogFile struct {
+	Version int                    `json:"version"`
+	Tables  map[string]*Definition `json:"tables"`
+}
+
+// catalogMu ngajaga runtuyan maca-robah-tulis katalog.
+var catalogMu sync.Mutex
+
+func catalogPath(database string) string {
+	return filepath.Join(config.DataDir, "db_"+database, config.SchemaDir, config.CatalogFile)
+}
+
+// readCatalog maca katalog tina disk. Mulihkeun error os.IsNotExist mun
+// katalog can aya.
+func readCatalog(database string) (*catalogFile, error) {
+	content, err := os.ReadFile(catalogPath(database))
+	if err != nil {
+		return nil, err
+	}
+
+	var cat catalogFile
+	if err := json.Unmarshal(content, &cat); err != nil {
+		return nil, fmt.Errorf("katalog database '%s' ruksak: %v", database, err)
+	}
+	if cat.Version > CatalogVersion {
+		return nil, fmt.Errorf("katalog database '%s' vérsi %d, MaungDB ieu ngan ngartos nepi ka vérsi %d", database, cat.Version, CatalogVersion)
+	}
+	if cat.Tables == nil {
+		cat.Tables = make(map[string]*Definition)
+	}
+	return &cat, nil
+}
+
+// loadCatalog maca katalog; mun can aya, file .schema heubeul
+// dimigrasikeun heula.
+func loadCatalog(database string) (*catalogFile, error) {
+	cat, err := readCatalog(database)
+	if !os.IsNotExist(err) {
+		return cat, err
+	}
+
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	return readOrMigrate(database)
+}
+
+// readOrMigrate kedah disauran bari nyepeng catalogMu.
+func readOrMigrate(database string) (*catalogFile, error) {
+	cat, err := readCatalog(database)
+	if err == nil || !os.IsNotExist(err) {
+		return cat, err
+	}
+
+	cat = &catalogFile{Version: CatalogVersion, Tables: make(map[string]*Definition)}
+	migrated, err := migrateLegacy(database, cat)
+	if err != nil {
+		return nil, err
+	}
+	if migrated > 0 {
+		if err := writeCatalog(database, cat); err != nil {
+			return nil, err
+		}
+		finishLegacy(database, cat)
+	}
+	return cat, nil
+}
+
+// updateCatalog nerapkeun fn kana katalog teras nyimpen deui sacara atomik
+// (file samentawis + rename). File .schema heubeul nu kapanggih dilebetkeun
+// sakalian.
+func updateCatalog(database string, fn func(cat *catalogFile) error) error {
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+
+	cat, err := readOrMigrate(database)
+	if err != nil {
+		return err
+	}
+	migrated, err := migrateLegacy(database, cat)
+	if err != nil {
+		return err
+	}
+	if err := fn(cat); err != nil {
+		return err
+	}
+
+	cat.Version = CatalogVersion
+	if err := writeCatalog(database, cat); err != nil {
+		return err
+	}
+	if migrated > 0 {
+		finishLegacy(database, cat)
+	}
+	return nil
+}
+
+func writeCatalog(database string, cat *catalogFile) error {
+	dbPath := filepath.Join(config.DataDir, "db_"+database)
+	if info, err := os.Stat(dbPath); err != nil || !info.IsDir() {
+		return errors.New("database teu aya")
+	}
+
+	path := catalogPath(database)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(cat, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
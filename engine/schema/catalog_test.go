@@ -0,0 +1,124 @@
+package schema
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/febrd/maungdb/internal/config"
+)
+
+// useTempDB: DataDir samentawis sareng diréktori database db_<name>.
+func useTempDB(t *testing.T, name string) string {
+	t.Helper()
+	old := config.DataDir
+	config.DataDir = t.TempDir()
+	t.Cleanup(func() { config.DataDir = old })
+	dir := filepath.Join(config.DataDir, "db_"+name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestLegacyMigration(t *testing.T) {
+	dir := useTempDB(t, "toko")
+	legacy := "id:INT:PK|harga:DECIMAL(10,2):DEFAULT(0)|ngaran:STRING:NOT NULL:COLLATE(NOCASE)\nread=admin,user\nwrite=admin\n"
+	if err := os.WriteFile(filepath.Join(dir, "barang.schema"), []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := Load("toko", "barang")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Column{
+		{Name: "id", Type: "INT", IsPrimary: true, IsUnique: true, IsNotNull: true},
+		{Name: "harga", Type: "DECIMAL", Args: []string{"10", "2"}, Default: "0", HasDefault: true},
+		{Name: "ngaran", Type: "STRING", IsNotNull: true, Collation: CollateNoCase},
+	}
+	// Dibandingkeun tina citakanana: Args nil sareng [] dianggap sami.
+	if fmt.Sprintf("%+v", d.Columns) != fmt.Sprintf("%+v", want) {
+		t.Errorf("kolom = %+v\nwant %+v", d.Columns, want)
+	}
+	if !d.Can("user", "read") || d.Can("user", "write") {
+		t.Errorf("hak = %v", d.Perms)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "barang.schema")); !os.IsNotExist(err) {
+		t.Error("file .schema heubeul teu diganti ngaran")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "barang.schema.v1")); err != nil {
+		t.Errorf("cadangan .schema.v1: %v", err)
+	}
+	cat, err := readCatalog("toko")
+	if err != nil || cat.Version != CatalogVersion {
+		t.Fatalf("katalog = %+v, %v", cat, err)
+	}
+
+	// File .schema nu asup saatos katalog aya ogé dilebetkeun.
+	if err := os.WriteFile(filepath.Join(dir, "telat.schema"), []byte("kode:STRING:PK\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !Exists("toko", "telat") {
+		t.Error("tabel heubeul nu telat teu kapanggih")
+	}
+}
+
+func TestCatalogOperations(t *testing.T) {
+	useTempDB(t, "uji")
+	cols := []Column{{Name: "id", Type: "INT", IsPrimary: true}}
+
+	steps := []struct {
+		name   string
+		op     func() error
+		err    string
+		exists map[string]bool
+	}{
+		{"Save", func() error { return CreateComplex("uji", "a", cols, nil) }, "", map[string]bool{"a": true}},
+		{"Save kadua", func() error { return CreateComplex("uji", "b", cols, nil) }, "", map[string]bool{"a": true, "b": true}},
+		{"Rename", func() error { return Rename("uji", "a", "c") }, "", map[string]bool{"a": false, "c": true}},
+		{"Rename teu aya", func() error { return Rename("uji", "a", "d") }, "teu kapanggih", map[string]bool{"d": false}},
+		{"Delete", func() error { return Delete("uji", "b") }, "", map[string]bool{"b": false, "c": true}},
+		{"Delete teu aya", func() error { return Delete("uji", "b") }, "teu kapanggih", nil},
+		{"database teu aya", func() error { return CreateComplex("euweuh", "x", cols, nil) }, "database teu aya", nil},
+	}
+	for _, s := range steps {
+		t.Run(s.name, func(t *testing.T) {
+			err := s.op()
+			if s.err == "" && err != nil || s.err != "" && (err == nil || !strings.Contains(err.Error(), s.err)) {
+				t.Fatalf("err = %v, want %q", err, s.err)
+			}
+			for table, want := range s.exists {
+				if got := Exists("uji", table); got != want {
+					t.Errorf("Exists(%s) = %v, want %v", table, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCatalogVersionCheck(t *testing.T) {
+	dir := useTempDB(t, "anyar")
+	cases := []struct {
+		content string
+		err     string
+	}{
+		{`{"version": 99, "tables": {}}`, "vérsi 99"},
+		{`{"version": 2, "tables": `, "ruksak"},
+		{`{"version": 2}`, "teu kapanggih"},
+	}
+	for _, c := range cases {
+		path := filepath.Join(dir, config.SchemaDir, config.CatalogFile)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(c.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := Load("anyar", "x")
+		if err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("%s: err = %v, want %q", c.content, err, c.err)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package schema
+
+import (
+	"strings"
+)
+
+// Kolasi: aturan ngabandingkeun téks. Kolom tanpa kolasi nganggo BINARY
+// (per bait, sapertos samemehna).
+const (
+	CollateBinary = "BINARY"
+	CollateNoCase = "NOCASE"
+	CollateID     = "ID"
+)
+
+// NormalizeCollation mulihkeun ngaran kolasi baku, atanapi "" mun teu dikenal.
+func NormalizeCollation(name string) string {
+	switch strings.ToUpper(strings.TrimSpace(name)) {
+	case "BINARY", "BIN":
+		return CollateBinary
+	case "NOCASE", "CI":
+		return CollateNoCase
+	case "ID", "ID_ID", "INDONESIA":
+		return CollateID
+	}
+	return ""
+}
+
+// accentFold: hurup nu boga tanda (é, ñ, ü, ...) disaruakeun jeung hurup
+// dasarna, sapertos nami "José" = "Jose" dina kolasi ID.
+var accentFold = strings.NewReplacer(
+	"à", "a", "á", "a", "â", "a", "ã", "a", "ä", "a", "å", "a",
+	"ç", "c",
+	"è", "e", "é", "e", "ê", "e", "ë", "e",
+	"ì", "i", "í", "i", "î", "i", "ï", "i",
+	"ñ", "n",
+	"ò", "o", "ó", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ù", "u", "ú", "u", "û", "u", "ü", "u",
+	"ý", "y", "ÿ", "y",
+)
+
+// CollationKey mulihkeun konci téks: dua nilai sami numutkeun kolasi mun
+// koncina sami. Dipaké pikeun =, KUMPULKEUN, BEDA sareng UNIQUE.
+func CollationKey(collation, val string) string {
+	switch collation {
+	case CollateNoCase:
+		return strings.ToLower(val)
+	case CollateID:
+		return accentFold.Replace(strings.ToLower(val))
+	}
+	return val
+}
+
+// CompareText ngabandingkeun dua téks numutkeun kolasi.
+func CompareText(collation, a, b string) int {
+	return strings.Compare(CollationKey(collation, a), CollationKey(collation, b))
+}
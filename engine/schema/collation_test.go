@@ -0,0 +1,41 @@
+package schema
+
+import "testing"
+
+func TestNormalizeCollation(t *testing.T) {
+	cases := map[string]string{
+		"binary":    CollateBinary,
+		" BIN ":     CollateBinary,
+		"nocase":    CollateNoCase,
+		"CI":        CollateNoCase,
+		"id_id":     CollateID,
+		"Indonesia": CollateID,
+		"latin1":    "",
+		"":          "",
+	}
+	for in, want := range cases {
+		if got := NormalizeCollation(in); got != want {
+			t.Errorf("NormalizeCollation(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCompareText(t *testing.T) {
+	cases := []struct {
+		collation, a, b string
+		want            int
+	}{
+		{"", "Asep", "asep", -1},
+		{CollateBinary, "Asep", "asep", -1},
+		{CollateNoCase, "Asep", "asep", 0},
+		{CollateNoCase, "José", "jose", 1},
+		{CollateID, "José", "JOSE", 0},
+		{CollateID, "Ñoño", "nono", 0},
+		{CollateID, "ábc", "abd", -1},
+	}
+	for _, c := range cases {
+		if got := CompareText(c.collation, c.a, c.b); got != c.want {
+			t.Errorf("CompareText(%q, %q, %q) = %d, want %d", c.collation, c.a, c.b, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package schema
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/febrd/maungdb/internal/config"
+)
+
+// Format heubeul (vérsi 1): hiji file <tabel>.schema per tabel. Baris
+// kahiji "kolom:TIPE(args):FLAG|...", baris salajengna "peran=aksi,...".
+// Saatos dimigrasikeun, filena diganti ngaran jadi <tabel>.schema.v1.
+const legacyExt = ".schema"
+
+func legacyPath(database, table string) string {
+	return filepath.Join(config.DataDir, "db_"+database, table+legacyExt)
+}
+
+func legacyExists(database, table string) bool {
+	_, err := os.Stat(legacyPath(database, table))
+	return err == nil
+}
+
+// migrateLegacy ngalebetkeun sadaya file .schema heubeul kana cat. Tabel nu
+// geus aya dina katalog teu ditimpa.
+func migrateLegacy(database string, cat *catalogFile) (int, error) {
+	files, err := filepath.Glob(filepath.Join(config.DataDir, "db_"+database, "*"+legacyExt))
+	if err != nil {
+		return 0, err
+	}
+
+	migrated := 0
+	for _, file := range files {
+		table := strings.TrimSuffix(filepath.Base(file), legacyExt)
+		if _, ok := cat.Tables[table]; ok {
+			continue
+		}
+
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return 0, err
+		}
+		def, err := decodeLegacy(string(content))
+		if err != nil {
+			return 0, errors.New("schema heubeul '" + table + "' ruksak")
+		}
+		cat.Tables[table] = def
+		migrated++
+	}
+	return migrated, nil
+}
+
+// finishLegacy ngaganti ngaran file .schema nu geus aya dina katalog (nu
+// geus disimpen) jadi cadangan .schema.v1.
+func finishLegacy(database string, cat *catalogFile) {
+	for table := range cat.Tables {
+		path := legacyPath(database, table)
+		if _, err := os.Stat(path); err == nil {
+			os.Rename(path, path+".v1")
+		}
+	}
+}
+
+func decodeLegacy(content string) (*Definition, error) {
+	lines := strings.Split(content, "\n")
+	if strings.TrimSpace(lines[0]) == "" {
+		return nil, errors.New("schema ruksak")
+	}
+
+	def := &Definition{Perms: make(map[string][]string)}
+
+	for _, rc := range strings.Split(lines[0], "|") {
+		parts := SplitDefinition(rc)
+		if len(parts) < 2 {
+			continue
+		}
+
+		baseType, args := parseTypeAndArgs(parts[1])
+		col := Column{
+			Name: parts[0],
+			Type: baseType,
+			Args: args,
+		}
+		for _, flag := range parts[2:] {
+			ApplyFlag(&col, flag)
+		}
+		def.Columns = append(def.Columns, col)
+	}
+
+	for _, line := range lines[1:] {
+		if line == "" {
+			continue
+		}
+		parts := strings.Split(line, "=")
+		if len(parts) == 2 {
+			def.Perms[parts[0]] = strings.Split(parts[1], ",")
+		}
+	}
+	return def, nil
+}
@@ -2,82 +2,174 @@ package schema
 
 import (
 	"errors"
-	"fmt"
-	"os"
-	"path/filepath"
-	"strconv"
 	"strings"
-	"time"
-
-	"github.com/febrd/maungdb/internal/config"
 )
 
 
 
 type Column struct {
-	Name       string
-	Type       string
-	Args       []string 
-	IsPrimary  bool     
-	IsUnique   bool     
-	IsNotNull  bool     
-	ForeignKey string  
-
-	Primary    bool     `json:"primary"`     
-	Unique     bool     `json:"unique"`      
-	NotNull    bool     `json:"not_null"`   
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`
+	Args       []string `json:"args,omitempty"`
+	IsPrimary  bool     `json:"primary,omitempty"`
+	IsUnique   bool     `json:"unique,omitempty"`
+	IsNotNull  bool     `json:"not_null,omitempty"`
+	ForeignKey string   `json:"fk,omitempty"`
+
+	Default       string `json:"default,omitempty"`
+	HasDefault    bool   `json:"has_default,omitempty"`
+	Check         string `json:"check,omitempty"`
+	AutoIncrement bool   `json:"auto,omitempty"`
+	OnDelete      string `json:"on_delete,omitempty"`
+	OnUpdate      string `json:"on_update,omitempty"`
+	Collation     string `json:"collation,omitempty"` // kosong = BINARY
 }
 
 type Definition struct {
-	Columns     []Column
-	Perms       map[string][]string
-	Permissions map[string][]string 
+	Columns []Column            `json:"columns"`
+	Perms   map[string][]string `json:"perms,omitempty"`
+}
 
-	Database string   `json:"database"`
-	Table    string   `json:"table"`
-	Read     []string `json:"read"`
-	Write    []string `json:"write"`
+func CreateComplex(database, table string, columns []Column, perms map[string][]string) error {
+	return Save(database, table, &Definition{Columns: columns, Perms: perms})
+}
 
+// Save nulis deui definisi tabel kana katalog (atomik), dipaké ku ROBIH
+// TABEL sangkan schema teu satengah katulis.
+func Save(database, table string, d *Definition) error {
+	return updateCatalog(database, func(cat *catalogFile) error {
+		cat.Tables[table] = &Definition{Columns: d.Columns, Perms: d.Perms}
+		return nil
+	})
 }
 
+func Rename(database, oldTable, newTable string) error {
+	return updateCatalog(database, func(cat *catalogFile) error {
+		def, ok := cat.Tables[oldTable]
+		if !ok {
+			return errors.New("table teu kapanggih")
+		}
+		delete(cat.Tables, oldTable)
+		cat.Tables[newTable] = def
+		return nil
+	})
+}
 
-func CreateComplex(database, table string, columns []Column, perms map[string][]string) error {
-	path := filepath.Join(config.DataDir, "db_"+database, table+".schema")
+func Delete(database, table string) error {
+	return updateCatalog(database, func(cat *catalogFile) error {
+		if _, ok := cat.Tables[table]; !ok {
+			return errors.New("table teu kapanggih")
+		}
+		delete(cat.Tables, table)
+		return nil
+	})
+}
 
-	var headerParts []string
+func Exists(database, table string) bool {
+	_, err := Load(database, table)
+	return err == nil
+}
 
-	for _, col := range columns {
+// ApplyFlag nerapkeun hiji konstrain kolom (PK, UNIQUE, NOT NULL, FK(..),
+// ON DELETE/ON UPDATE <aksi>, AUTO, DEFAULT(..), CHECK(..), COLLATE(..)). Eusi DEFAULT jeung CHECK teu diropéa
+// hurufna. Mulihkeun false mun konstrain teu dikenal.
+func ApplyFlag(col *Column, raw string) bool {
+	raw = strings.TrimSpace(raw)
+	flag := strings.ToUpper(raw)
 
-		defStr := fmt.Sprintf("%s:%s", col.Name, col.Type)
+	inner := func() string {
+		return strings.TrimSpace(raw[strings.Index(raw, "(")+1 : len(raw)-1])
+	}
+	hasArg := func(prefix string) bool {
+		return strings.HasPrefix(strings.ReplaceAll(flag, " ", ""), prefix+"(") && strings.HasSuffix(flag, ")")
+	}
 
-		if len(col.Args) > 0 {
-			defStr += fmt.Sprintf("(%s)", strings.Join(col.Args, ","))
+	switch {
+	case flag == "PK" || flag == "PRIMARY" || flag == "PRIMARY KEY":
+		col.IsPrimary = true
+		col.IsNotNull = true
+		col.IsUnique = true
+	case flag == "UNIQUE":
+		col.IsUnique = true
+	case flag == "NOTNULL" || flag == "NOT NULL":
+		col.IsNotNull = true
+	case flag == "AUTO" || flag == "AUTO_INCREMENT":
+		col.AutoIncrement = true
+	case hasArg("FK"):
+		col.ForeignKey = strings.ToUpper(inner())
+	case strings.HasPrefix(flag, "ON DELETE ") || strings.HasPrefix(flag, "ON UPDATE "):
+		action := NormalizeFKAction(flag[len("ON DELETE "):])
+		if action == "" {
+			return false
 		}
-
-		if col.IsPrimary {
-			defStr += ":PK"
+		if strings.HasPrefix(flag, "ON DELETE ") {
+			col.OnDelete = action
 		} else {
-			if col.IsUnique {
-				defStr += ":UNIQUE"
-			}
-			if col.IsNotNull {
-				defStr += ":NOT NULL"
-			}
+			col.OnUpdate = action
 		}
-
-		if col.ForeignKey != "" {
-			defStr += fmt.Sprintf(":FK(%s)", col.ForeignKey)
+	case hasArg("DEFAULT"):
+		col.Default = strings.Trim(inner(), "'\"")
+		col.HasDefault = true
+	case hasArg("CHECK"):
+		col.Check = inner()
+	case hasArg("COLLATE") || hasArg("KOLASI"):
+		collation := NormalizeCollation(inner())
+		if collation == "" {
+			return false
 		}
-
-		headerParts = append(headerParts, defStr)
+		if collation == CollateBinary {
+			collation = ""
+		}
+		col.Collation = collation
+	default:
+		return false
 	}
+	return true
+}
+
+// Aksi referensial FK. Kosong dianggap FKNoAction.
+const (
+	FKCascade  = "CASCADE"
+	FKSetNull  = "SET NULL"
+	FKRestrict = "RESTRICT"
+	FKNoAction = "NO ACTION"
+)
 
-	content := strings.Join(headerParts, "|") + "\n"
-	for role, actions := range perms {
-		content += fmt.Sprintf("%s=%s\n", role, strings.Join(actions, ","))
+func NormalizeFKAction(action string) string {
+	switch strings.Join(strings.Fields(strings.ToUpper(action)), " ") {
+	case "CASCADE":
+		return FKCascade
+	case "SET NULL":
+		return FKSetNull
+	case "RESTRICT":
+		return FKRestrict
+	case "NO ACTION":
+		return FKNoAction
 	}
+	return ""
+}
 
-	return os.WriteFile(path, []byte(content), 0644)
+// SplitDefinition meulah "nama:TIPE:FLAG..." dumasar ':' di luar kurung,
+// sangkan DEFAULT(10:00) atanapi CHECK(...) teu papisah.
+func SplitDefinition(def string) []string {
+	var parts []string
+	var current strings.Builder
+	depth := 0
+
+	for _, r := range def {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == ':' && depth == 0:
+			parts = append(parts, current.String())
+			current.Reset()
+			continue
+		}
+		current.WriteRune(r)
+	}
+	return append(parts, current.String())
 }
 
 func Create(database, table string, fieldsRaw []string, perms map[string][]string) error {
@@ -106,73 +198,31 @@ func Create(database, table string, fieldsRaw []string, perms map[string][]strin
 	return CreateComplex(database, table, columns, perms)
 }
 
+// Load mangrupikeun hiji-hijina panto pikeun maca definisi tabel.
 func Load(database, table string) (*Definition, error) {
-	path := filepath.Join(config.DataDir, "db_"+database, table+".schema")
-
-	content, err := os.ReadFile(path)
+	cat, err := loadCatalog(database)
 	if err != nil {
-		return nil, errors.New("table teu kapanggih")
+		return nil, err
 	}
 
-	lines := strings.Split(string(content), "\n")
-	if len(lines) < 1 {
-		return nil, errors.New("schema ruksak")
-	}
-
-	rawCols := strings.Split(lines[0], "|")
-	var columns []Column
-
-	for _, rc := range rawCols {
-		parts := strings.Split(rc, ":")
-		
-		if len(parts) >= 2 {
-			colName := parts[0]
-			fullType := parts[1]
-			baseType, args := parseTypeAndArgs(fullType)
-
-			col := Column{
-				Name: colName,
-				Type: baseType,
-				Args: args,
-			}
-
-			if len(parts) > 2 {
-				for _, flag := range parts[2:] {
-					flag = strings.ToUpper(strings.TrimSpace(flag))
-					
-					switch {
-					case flag == "PK" || flag == "PRIMARY" || flag == "PRIMARY KEY":
-						col.IsPrimary = true
-						col.IsNotNull = true
-						col.IsUnique = true
-					case flag == "UNIQUE":
-						col.IsUnique = true
-					case flag == "NOTNULL" || flag == "NOT NULL":
-						col.IsNotNull = true
-					case strings.HasPrefix(flag, "FK(") && strings.HasSuffix(flag, ")"):
-						inner := flag[3 : len(flag)-1]
-						col.ForeignKey = inner
-					}
-				}
-			}
-
-			columns = append(columns, col)
+	def, ok := cat.Tables[table]
+	if !ok && legacyExists(database, table) {
+		// File .schema heubeul nu asup saatos katalog dijieun.
+		err := updateCatalog(database, func(c *catalogFile) error {
+			def, ok = c.Tables[table]
+			return nil
+		})
+		if err != nil {
+			return nil, err
 		}
 	}
-
-	def := &Definition{
-		Columns: columns,
-		Perms:   make(map[string][]string),
+	if !ok {
+		return nil, errors.New("table teu kapanggih")
 	}
 
-	for _, line := range lines[1:] {
-		if line == "" { continue }
-		parts := strings.Split(line, "=")
-		if len(parts) == 2 {
-			def.Perms[parts[0]] = strings.Split(parts[1], ",")
-		}
+	if def.Perms == nil {
+		def.Perms = make(map[string][]string)
 	}
-
 	return def, nil
 }
 
@@ -185,48 +235,12 @@ func (d *Definition) ValidateRow(data string) error {
 	for i, col := range d.Columns {
 		val := strings.TrimSpace(values[i])
 
-		if val == "" || strings.ToUpper(val) == "NULL" {
+		if val == "" || IsNull(val) {
 			continue
 		}
 
-		switch col.Type {
-		case "INT":
-			if _, err := strconv.Atoi(val); err != nil {
-				return fmt.Errorf("kolom '%s' kudu INT (angka)", col.Name)
-			}
-		case "FLOAT":
-			if _, err := strconv.ParseFloat(val, 64); err != nil {
-				return fmt.Errorf("kolom '%s' kudu FLOAT (desimal)", col.Name)
-			}
-		case "BOOL":
-			if val != "true" && val != "false" {
-				return fmt.Errorf("kolom '%s' kudu BOOL (true/false)", col.Name)
-			}
-		case "DATE":
-			if _, err := time.Parse("2006-01-02", val); err != nil {
-				return fmt.Errorf("kolom '%s' kudu DATE (YYYY-MM-DD)", col.Name)
-			}
-		case "CHAR":
-			if len(col.Args) > 0 {
-				limit, _ := strconv.Atoi(col.Args[0])
-				if len(val) > limit {
-					return fmt.Errorf("kolom '%s' maksimal %d karakter", col.Name, limit)
-				}
-			}
-		case "ENUM":
-			valid := false
-			for _, opt := range col.Args {
-				if val == opt {
-					valid = true
-					break
-				}
-			}
-			if !valid {
-				return fmt.Errorf("kolom '%s' kudu salah sahiji tina: %v", col.Name, col.Args)
-			}
-		case "STRING", "TEXT":
-		default:
-			return fmt.Errorf("tipe data teu dikenal: %s", col.Type)
+		if err := ValidateValue(col, val); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -258,10 +272,16 @@ func parseTypeAndArgs(fullType string) (string, []string) {
 	return base, args
 }
 
+func IsValidType(t string) bool {
+	return isValidType(t)
+}
+
 func isValidType(t string) bool {
 	valid := map[string]bool{
 		"INT": true, "STRING": true, "FLOAT": true, "BOOL": true,
 		"DATE": true, "CHAR": true, "ENUM": true, "TEXT": true,
+		"DATETIME": true, "TIMESTAMP": true, "DECIMAL": true,
+		"UUID": true, "JSON": true, "BLOB": true,
 	}
 	return valid[t]
 }
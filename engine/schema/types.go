@@ -0,0 +1,288 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	reDecimal = regexp.MustCompile(`^[+-]?\d+(\.\d+)?$`)
+	reUUID    = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// dateTimeLayouts: format DATETIME nu ditarima. Mun teu aya zona waktu,
+// dianggap UTC.
+var dateTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+func ParseDateTime(val string) (time.Time, error) {
+	for _, layout := range dateTimeLayouts {
+		if t, err := time.Parse(layout, val); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("format waktu teu dikenal: %s", val)
+}
+
+// decimalScale mulihkeun (presisi, skala) tina DECIMAL(p,s). Default (18,0).
+func decimalScale(col Column) (int, int) {
+	precision, scale := 18, 0
+	if len(col.Args) > 0 {
+		if p, err := strconv.Atoi(col.Args[0]); err == nil {
+			precision = p
+		}
+	}
+	if len(col.Args) > 1 {
+		if s, err := strconv.Atoi(col.Args[1]); err == nil {
+			scale = s
+		}
+	}
+	return precision, scale
+}
+
+// DecimalScale mulihkeun skala kolom DECIMAL, atanapi -1 mun sanés DECIMAL.
+func DecimalScale(col Column) int {
+	if col.Type != "DECIMAL" {
+		return -1
+	}
+	_, scale := decimalScale(col)
+	return scale
+}
+
+// ParseDecimal maca angka persis (tanpa pembulatan float).
+func ParseDecimal(val string) (*big.Rat, bool) {
+	val = strings.TrimSpace(val)
+	if !reDecimal.MatchString(val) {
+		if _, err := strconv.ParseFloat(val, 64); err != nil {
+			return nil, false
+		}
+	}
+	r, ok := new(big.Rat).SetString(val)
+	return r, ok
+}
+
+// ValidateValue mariksa hiji nilai (teu kosong) ngalawan tipe kolom.
+func ValidateValue(col Column, val string) error {
+	switch col.Type {
+	case "INT":
+		if _, err := strconv.Atoi(val); err != nil {
+			return fmt.Errorf("kolom '%s' kudu INT (angka)", col.Name)
+		}
+	case "FLOAT":
+		if _, err := strconv.ParseFloat(val, 64); err != nil {
+			return fmt.Errorf("kolom '%s' kudu FLOAT (desimal)", col.Name)
+		}
+	case "DECIMAL":
+		if !reDecimal.MatchString(val) {
+			return fmt.Errorf("kolom '%s' kudu DECIMAL (conto: 1250.50)", col.Name)
+		}
+		precision, scale := decimalScale(col)
+		digits := strings.TrimLeft(val, "+-")
+		intPart, fracPart := digits, ""
+		if i := strings.Index(digits, "."); i != -1 {
+			intPart, fracPart = digits[:i], digits[i+1:]
+		}
+		intPart = strings.TrimLeft(intPart, "0")
+		if len(fracPart) > scale {
+			return fmt.Errorf("kolom '%s' maksimal %d angka di tukangeun koma", col.Name, scale)
+		}
+		if len(intPart) > precision-scale {
+			return fmt.Errorf("kolom '%s' leuwih ti DECIMAL(%d,%d)", col.Name, precision, scale)
+		}
+	case "BOOL":
+		if val != "true" && val != "false" {
+			return fmt.Errorf("kolom '%s' kudu BOOL (true/false)", col.Name)
+		}
+	case "DATE":
+		if _, err := time.Parse("2006-01-02", val); err != nil {
+			return fmt.Errorf("kolom '%s' kudu DATE (YYYY-MM-DD)", col.Name)
+		}
+	case "DATETIME", "TIMESTAMP":
+		if _, err := ParseDateTime(val); err != nil {
+			return fmt.Errorf("kolom '%s' kudu %s (conto: 2024-01-31T08:00:00+07:00)", col.Name, col.Type)
+		}
+	case "UUID":
+		if !reUUID.MatchString(val) {
+			return fmt.Errorf("kolom '%s' kudu UUID (xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx)", col.Name)
+		}
+	case "JSON":
+		if !json.Valid([]byte(val)) {
+			return fmt.Errorf("kolom '%s' kudu JSON nu valid", col.Name)
+		}
+	case "BLOB":
+		if _, err := base64.StdEncoding.DecodeString(val); err != nil {
+			return fmt.Errorf("kolom '%s' kudu BLOB (base64)", col.Name)
+		}
+	case "CHAR":
+		if len(col.Args) > 0 {
+			limit, _ := strconv.Atoi(col.Args[0])
+			if len(val) > limit {
+				return fmt.Errorf("kolom '%s' maksimal %d karakter", col.Name, limit)
+			}
+		}
+	case "ENUM":
+		for _, opt := range col.Args {
+			if val == opt {
+				return nil
+			}
+		}
+		return fmt.Errorf("kolom '%s' kudu salah sahiji tina: %v", col.Name, col.Args)
+	case "STRING", "TEXT":
+	default:
+		return fmt.Errorf("tipe data teu dikenal: %s", col.Type)
+	}
+	return nil
+}
+
+// NormalizeValue ngarobah nilai jadi bentuk baku samemeh disimpen:
+// DECIMAL dieusi nepi ka skalana, DATETIME jadi RFC3339, UUID hurup leutik,
+// JSON dipadetkeun (teu aya baris anyar). Nilai nu teu valid diantep.
+func NormalizeValue(col Column, val string) string {
+	if val == "" || IsNull(val) || ValidateValue(col, val) != nil {
+		return val
+	}
+
+	switch col.Type {
+	case "DECIMAL":
+		if r, ok := ParseDecimal(val); ok {
+			_, scale := decimalScale(col)
+			return r.FloatString(scale)
+		}
+	case "DATETIME", "TIMESTAMP":
+		if t, err := ParseDateTime(val); err == nil {
+			return t.Format(time.RFC3339Nano)
+		}
+	case "UUID":
+		if reUUID.MatchString(val) {
+			return strings.ToLower(val)
+		}
+	case "JSON":
+		var buf bytes.Buffer
+		if json.Compact(&buf, []byte(val)) == nil {
+			return buf.String()
+		}
+	}
+	return val
+}
+
+// CompareValues ngabandingkeun dua nilai dumasar tipe kolom. ok=false mun
+// tipe teu boga urutan husus atanapi nilai teu bisa diparse; nu nyauran
+// kedah mundur ka babandingan string.
+func CompareValues(colType, a, b string) (int, bool) {
+	switch colType {
+	case "", "INT", "FLOAT":
+		fA, errA := strconv.ParseFloat(a, 64)
+		fB, errB := strconv.ParseFloat(b, 64)
+		if errA != nil || errB != nil {
+			return 0, false
+		}
+		switch {
+		case fA < fB:
+			return -1, true
+		case fA > fB:
+			return 1, true
+		}
+		return 0, true
+
+	case "DECIMAL":
+		rA, okA := ParseDecimal(a)
+		rB, okB := ParseDecimal(b)
+		if !okA || !okB {
+			return 0, false
+		}
+		return rA.Cmp(rB), true
+
+	case "DATE", "DATETIME", "TIMESTAMP":
+		tA, errA := ParseDateTime(a)
+		tB, errB := ParseDateTime(b)
+		if errA != nil || errB != nil {
+			return 0, false
+		}
+		return tA.Compare(tB), true
+
+	case "BOOL":
+		bA, errA := strconv.ParseBool(a)
+		bB, errB := strconv.ParseBool(b)
+		if errA != nil || errB != nil {
+			return 0, false
+		}
+		switch {
+		case bA == bB:
+			return 0, true
+		case !bA:
+			return -1, true
+		}
+		return 1, true
+
+	case "UUID":
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b)), true
+	}
+	return 0, false
+}
+
+// NullValue: panyiri NULL dina file data. Béda ti string kosong ("").
+const NullValue = `\N`
+
+// IsNull mariksa naha nilai mangrupikeun panyiri NULL.
+func IsNull(val string) bool {
+	return strings.TrimSpace(val) == NullValue
+}
+
+// IsTextType: tipe nu tiasa nyimpen string kosong. Dina tipe séjén, nilai
+// kosong (data heubeul) dianggap NULL.
+func IsTextType(colType string) bool {
+	switch colType {
+	case "STRING", "TEXT", "CHAR":
+		return true
+	}
+	return false
+}
+
+// IsNullFor sapertos IsNull, tapi nganggap nilai kosong dina kolom non-téks
+// salaku NULL. colType kosong hartosna tipe teu kanyahoan.
+func IsNullFor(colType, val string) bool {
+	if IsNull(val) {
+		return true
+	}
+	return strings.TrimSpace(val) == "" && colType != "" && !IsTextType(colType)
+}
+
+// InputValue narjamahkeun nilai ti pamaké: NULL (tanpa tanda petik) jadi
+// NullValue, 'NULL' jadi téks "NULL", '' jadi string kosong. Nilai kosong
+// dina kolom non-téks dianggap NULL.
+func InputValue(col Column, raw string) string {
+	val := strings.TrimSpace(raw)
+	switch {
+	case strings.EqualFold(val, "NULL"):
+		return NullValue
+	case val == "''" || val == `""`:
+		return ""
+	case len(val) == 6 && (val[0] == '\'' || val[0] == '"') && val[5] == val[0] && strings.EqualFold(val[1:5], "NULL"):
+		return val[1:5]
+	case val == "" && !IsTextType(col.Type):
+		return NullValue
+	}
+	return raw
+}
+
+// DisplayValue ngarobah panyiri NULL jadi "NULL" pikeun ditampilkeun.
+func DisplayValue(val string) string {
+	if IsNull(val) {
+		return "NULL"
+	}
+	return val
+}
@@ -0,0 +1,97 @@
+package schema
+
+import "testing"
+
+func TestValidateValue(t *testing.T) {
+	dec := Column{Name: "harga", Type: "DECIMAL", Args: []string{"6", "2"}}
+	cases := []struct {
+		col Column
+		val string
+		ok  bool
+	}{
+		{Column{Type: "INT"}, "-42", true},
+		{Column{Type: "INT"}, "4.2", false},
+		{Column{Type: "FLOAT"}, "4.2e3", true},
+		{Column{Type: "FLOAT"}, "opat", false},
+		{dec, "1234.56", true},
+		{dec, "-0001234.5", true},
+		{dec, "12345.6", false}, // 5 angka hareupeun koma > 6-2
+		{dec, "1.234", false},   // skala > 2
+		{dec, "1e3", false},
+		{Column{Type: "DECIMAL"}, "123456789012345678", true},
+		{Column{Type: "DECIMAL"}, "0.5", false}, // skala bawaan 0
+		{Column{Type: "BOOL"}, "true", true},
+		{Column{Type: "BOOL"}, "1", false},
+		{Column{Type: "DATE"}, "2024-02-29", true},
+		{Column{Type: "DATE"}, "2023-02-29", false},
+		{Column{Type: "DATETIME"}, "2024-01-31T08:00:00+07:00", true},
+		{Column{Type: "DATETIME"}, "2024-01-31 08:00", true},
+		{Column{Type: "TIMESTAMP"}, "31/01/2024", false},
+		{Column{Type: "UUID"}, "123E4567-E89B-12D3-A456-426614174000", true},
+		{Column{Type: "UUID"}, "123e4567e89b12d3a456426614174000", false},
+		{Column{Type: "JSON"}, `{"a":[1,2]}`, true},
+		{Column{Type: "JSON"}, `{"a":}`, false},
+		{Column{Type: "BLOB"}, "aGFsbw==", true},
+		{Column{Type: "BLOB"}, "aGFsbw", false},
+		{Column{Type: "CHAR", Args: []string{"3"}}, "abc", true},
+		{Column{Type: "CHAR", Args: []string{"3"}}, "abcd", false},
+		{Column{Type: "ENUM", Args: []string{"L", "P"}}, "P", true},
+		{Column{Type: "ENUM", Args: []string{"L", "P"}}, "X", false},
+		{Column{Type: "GEOMETRY"}, "x", false},
+	}
+	for _, c := range cases {
+		t.Run(c.col.Type+" "+c.val, func(t *testing.T) {
+			err := ValidateValue(c.col, c.val)
+			if (err == nil) != c.ok {
+				t.Errorf("ValidateValue = %v, want ok=%v", err, c.ok)
+			}
+		})
+	}
+}
+
+func TestNormalizeValue(t *testing.T) {
+	cases := []struct {
+		col       Column
+		val, want string
+	}{
+		{Column{Type: "DECIMAL", Args: []string{"10", "2"}}, "5", "5.00"},
+		{Column{Type: "DECIMAL", Args: []string{"10", "2"}}, "-0.5", "-0.50"},
+		{Column{Type: "DECIMAL", Args: []string{"10", "2"}}, "1.234", "1.234"}, // teu valid: diantep
+		{Column{Type: "DATETIME"}, "2024-01-31 08:00", "2024-01-31T08:00:00Z"},
+		{Column{Type: "DATETIME"}, "2024-01-31T08:00:00+07:00", "2024-01-31T08:00:00+07:00"},
+		{Column{Type: "UUID"}, "123E4567-E89B-12D3-A456-426614174000", "123e4567-e89b-12d3-a456-426614174000"},
+		{Column{Type: "JSON"}, "{ \"a\" : [1, 2] }", `{"a":[1,2]}`},
+		{Column{Type: "INT"}, NullValue, NullValue},
+		{Column{Type: "STRING"}, " Asep ", " Asep "},
+	}
+	for _, c := range cases {
+		if got := NormalizeValue(c.col, c.val); got != c.want {
+			t.Errorf("NormalizeValue(%s, %q) = %q, want %q", c.col.Type, c.val, got, c.want)
+		}
+	}
+}
+
+func TestCompareValues(t *testing.T) {
+	cases := []struct {
+		typ, a, b string
+		want      int
+		ok        bool
+	}{
+		{"INT", "9", "10", -1, true},
+		{"FLOAT", "2.50", "2.5", 0, true},
+		{"DECIMAL", "0.1", "0.10", 0, true},
+		{"DECIMAL", "12345678901234567.1", "12345678901234567.2", -1, true},
+		{"DATETIME", "2024-01-31T08:00:00+07:00", "2024-01-31T01:00:00Z", 0, true},
+		{"DATE", "2024-12-01", "2024-02-01", 1, true},
+		{"BOOL", "false", "true", -1, true},
+		{"UUID", "ABC", "abc", 0, true},
+		{"INT", "abc", "1", 0, false},
+		{"STRING", "a", "b", 0, false},
+	}
+	for _, c := range cases {
+		got, ok := CompareValues(c.typ, c.a, c.b)
+		if got != c.want || ok != c.ok {
+			t.Errorf("CompareValues(%s, %q, %q) = %d, %v; want %d, %v", c.typ, c.a, c.b, got, ok, c.want, c.ok)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// commitManifest: daptar tabel nu file .tmp-na siap di-rename dina hiji komit.
+const commitManifest = "komit.pending"
+
+// TableChanges: parobahan hiji tabel dina hiji komit.
+type TableChanges struct {
+	Table   string
+	Changes []RowChange
+}
+
+// CommitTables nerapkeun parobahan sababaraha tabel sacara atomik. Eusi anyar
+// unggal tabel ditulis heula ka file .tmp; saatos sadayana réngsé, daptar
+// tabelna dicatet dina manifest teras unggal .tmp di-rename. Mun prosés
+// eureun saméméh manifest ditulis, teu aya tabel nu robah; mun eureun di
+// tengah rename, RecoverCommits ngalengkepan sésana.
+func CommitTables(batches []TableChanges) error {
+	dbPath := GetDBPath()
+	if dbPath == "" {
+		return fmt.Errorf("database teu acan dipilih")
+	}
+
+	var staged []string
+	discard := func() {
+		for _, path := range staged {
+			os.Remove(path + ".tmp")
+		}
+	}
+	for _, b := range batches {
+		path := tableFile(dbPath, b.Table)
+		content, err := applyChanges(path, b.Table, b.Changes)
+		if err == nil {
+			err = writeSynced(path+".tmp", content)
+		}
+		if err != nil {
+			discard()
+			return err
+		}
+		staged = append(staged, path)
+	}
+
+	manifest := filepath.Join(dbPath, commitManifest)
+	if len(staged) > 1 {
+		var names []string
+		for _, path := range staged {
+			names = append(names, filepath.Base(path))
+		}
+		if err := writeSynced(manifest, strings.Join(names, "\n")+"\n"); err != nil {
+			discard()
+			os.Remove(manifest)
+			return err
+		}
+	}
+
+	for _, path := range staged {
+		if err := os.Rename(path+".tmp", path); err != nil {
+			return err
+		}
+	}
+	if len(staged) > 1 {
+		return os.Remove(manifest)
+	}
+	return nil
+}
+
+// RecoverCommits ngalengkepan komit nu eureun di tengah rename (manifest
+// masih aya) di sadaya database.
+func RecoverCommits() error {
+	dbs, err := ListDatabases()
+	if err != nil {
+		return nil
+	}
+	for _, db := range dbs {
+		if err := recoverCommit(DatabasePath(db)); err != nil {
+			return fmt.Errorf("gagal ngalengkepan komit di '%s': %v", db, err)
+		}
+	}
+	return nil
+}
+
+func recoverCommit(dbPath string) error {
+	manifest := filepath.Join(dbPath, commitManifest)
+	content, err := os.ReadFile(manifest)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, name := range strings.Split(string(content), "\n") {
+		if name == "" {
+			continue
+		}
+		path := filepath.Join(dbPath, name)
+		if _, err := os.Stat(path + ".tmp"); err != nil {
+			continue
+		}
+		if err := os.Rename(path+".tmp", path); err != nil {
+			return err
+		}
+	}
+	return os.Remove(manifest)
+}
+
+func writeSynced(path, content string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/febrd/maungdb/internal/config"
+)
+
+// useTempDB nyiapkeun DataDir samentawis sareng database aktip "uji" nu
+// eusina tables (ngaran tabel -> eusi file).
+func useTempDB(t *testing.T, tables map[string]string) string {
+	t.Helper()
+	dataDir, activeDB := config.DataDir, ActiveDB
+	t.Cleanup(func() { config.DataDir, ActiveDB = dataDir, activeDB })
+
+	config.DataDir = t.TempDir()
+	ActiveDB = "uji"
+	if err := CreateDatabase("uji"); err != nil {
+		t.Fatal(err)
+	}
+	dbPath := DatabasePath("uji")
+	for name, content := range tables {
+		if err := os.WriteFile(filepath.Join(dbPath, name+".mg"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dbPath
+}
+
+func readTable(t *testing.T, dbPath, table string) string {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join(dbPath, table+".mg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestCommitTables(t *testing.T) {
+	initial := map[string]string{
+		"induk": "1|a\n2|b\n",
+		"anak":  "10|1\n11|2\n",
+	}
+	tests := []struct {
+		name    string
+		batches []TableChanges
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "sababaraha tabel",
+			batches: []TableChanges{
+				{Table: "induk", Changes: []RowChange{{ID: "1", Delete: true}, {ID: "2", Data: "2|B"}, {Data: "3|c", Insert: true}}},
+				{Table: "anak", Changes: []RowChange{{ID: "10", Delete: true}}},
+			},
+			want: map[string]string{"induk": "2|B\n3|c\n", "anak": "11|2\n"},
+		},
+		{
+			name: "OMEAN ID lajeng MICEUN",
+			batches: []TableChanges{
+				{Table: "induk", Changes: []RowChange{{ID: "1", Data: "5|a"}, {ID: "5", Delete: true}}},
+			},
+			want: map[string]string{"induk": "2|b\n", "anak": initial["anak"]},
+		},
+		{
+			name: "gagal di tabel kadua, teu aya nu robah",
+			batches: []TableChanges{
+				{Table: "induk", Changes: []RowChange{{ID: "1", Delete: true}}},
+				{Table: "anak", Changes: []RowChange{{ID: "99", Delete: true}}},
+			},
+			want:    initial,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbPath := useTempDB(t, initial)
+			err := CommitTables(tt.batches)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			for table, want := range tt.want {
+				if got := readTable(t, dbPath, table); got != want {
+					t.Errorf("%s = %q, want %q", table, got, want)
+				}
+			}
+			leftovers, _ := filepath.Glob(filepath.Join(dbPath, "*.tmp"))
+			if _, err := os.Stat(filepath.Join(dbPath, commitManifest)); err == nil || len(leftovers) > 0 {
+				t.Errorf("manifest/.tmp teu dipiceun: %v", leftovers)
+			}
+		})
+	}
+}
+
+func TestRecoverCommits(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		staged   map[string]string
+		want     map[string]string
+	}{
+		{
+			name:     "rename nu kalangkung",
+			manifest: "induk.mg\nanak.mg\n",
+			staged:   map[string]string{"anak": "11|2\n"},
+			want:     map[string]string{"induk": "1|a\n", "anak": "11|2\n"},
+		},
+		{
+			name:   "tanpa manifest, .tmp teu dianggo",
+			staged: map[string]string{"anak": "11|2\n"},
+			want:   map[string]string{"induk": "1|a\n", "anak": "10|1\n11|2\n"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbPath := useTempDB(t, map[string]string{"induk": "1|a\n", "anak": "10|1\n11|2\n"})
+			for table, content := range tt.staged {
+				os.WriteFile(filepath.Join(dbPath, table+".mg.tmp"), []byte(content), 0644)
+			}
+			if tt.manifest != "" {
+				os.WriteFile(filepath.Join(dbPath, commitManifest), []byte(tt.manifest), 0644)
+			}
+
+			if err := RecoverCommits(); err != nil {
+				t.Fatal(err)
+			}
+			for table, want := range tt.want {
+				if got := readTable(t, dbPath, table); got != want {
+					t.Errorf("%s = %q, want %q", table, got, want)
+				}
+			}
+			if _, err := os.Stat(filepath.Join(dbPath, commitManifest)); err == nil {
+				t.Error("manifest teu dipiceun")
+			}
+		})
+	}
+}
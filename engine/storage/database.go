@@ -4,6 +4,8 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/febrd/maungdb/internal/config"
 )
@@ -19,6 +21,36 @@ func CreateDatabase(name string) error {
 	return nil
 }
 
+func DropDatabase(name string) error {
+	dbPath := filepath.Join(config.DataDir, "db_"+name)
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return errors.New("database teu aya")
+	}
+	return os.RemoveAll(dbPath)
+}
+
+func DatabaseExists(name string) bool {
+	info, err := os.Stat(filepath.Join(config.DataDir, "db_"+name))
+	return err == nil && info.IsDir()
+}
+
 func DatabasePath(name string) string {
 	return filepath.Join(config.DataDir, "db_"+name)
 }
+
+// ListDatabases: sadaya database (diréktori db_*) dina DataDir.
+func ListDatabases() ([]string, error) {
+	files, err := os.ReadDir(config.DataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var dbs []string
+	for _, f := range files {
+		if f.IsDir() && strings.HasPrefix(f.Name(), "db_") {
+			dbs = append(dbs, strings.TrimPrefix(f.Name(), "db_"))
+		}
+	}
+	sort.Strings(dbs)
+	return dbs, nil
+}
@@ -134,6 +134,73 @@ func CommitDelete(tableName, id string) error {
     
     return os.WriteFile(filePath, []byte(output), 0644)
 }
+// RowChange: hiji parobahan baris dumasar ID (kolom kahiji) pikeun
+// CommitBatch / CommitTables. Insert nambihan Data di tungtung tabel.
+type RowChange struct {
+	ID     string
+	Data   string
+	Delete bool
+	Insert bool
+}
+
+// CommitBatch nerapkeun runtuyan SIMPEN/OMEAN/MICEUN ka hiji tabel ku sakali
+// nulis file. ID bisa robah (OMEAN PK), parobahan saterusna nuturkeun ID anyar.
+func CommitBatch(tableName string, changes []RowChange) error {
+	return CommitTables([]TableChanges{{Table: tableName, Changes: changes}})
+}
+
+// applyChanges mulihkeun eusi file tabel saatos changes diterapkeun.
+func applyChanges(filePath, tableName string, changes []RowChange) (string, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("gagal maca file %s: %v", filePath, err)
+	}
+
+	var lines []string
+	for _, line := range strings.Split(string(content), "\n") {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	pos := make(map[string]int, len(lines))
+	for i, line := range lines {
+		pos[strings.SplitN(line, "|", 2)[0]] = i
+	}
+
+	removed := make([]bool, len(lines))
+	for _, ch := range changes {
+		if ch.Insert {
+			pos[strings.SplitN(ch.Data, "|", 2)[0]] = len(lines)
+			lines = append(lines, ch.Data)
+			removed = append(removed, false)
+			continue
+		}
+
+		i, ok := pos[ch.ID]
+		if !ok {
+			return "", fmt.Errorf("ID %s teu kapendak di tabel %s", ch.ID, tableName)
+		}
+		delete(pos, ch.ID)
+
+		if ch.Delete {
+			removed[i] = true
+			continue
+		}
+		lines[i] = ch.Data
+		pos[strings.SplitN(ch.Data, "|", 2)[0]] = i
+	}
+
+	var out strings.Builder
+	for i, line := range lines {
+		if !removed[i] {
+			out.WriteString(line)
+			out.WriteString("\n")
+		}
+	}
+	return out.String(), nil
+}
+
 func Init() error {
 	if err := os.MkdirAll(config.DataDir, 0755); err != nil {
 		return err
@@ -148,7 +215,7 @@ func Init() error {
 		return err
 	}
 
-	return nil
+	return RecoverCommits()
 }
 
 func tablePath(database, table string) (string, error) {
@@ -157,18 +224,28 @@ func tablePath(database, table string) (string, error) {
 	if _, err := os.Stat(dbPath); err != nil {
 		return "", errors.New("database teu kapanggih")
 	}
+	return tableFile(dbPath, table), nil
+}
 
+// tableFile: file tabel di dbPath (ekstensi munggaran nu aya, atanapi .mg).
+func tableFile(dbPath, table string) string {
 	for _, ext := range config.AllowedExt {
 		p := filepath.Join(dbPath, table+ext)
 		if _, err := os.Stat(p); err == nil {
-			return p, nil
+			return p
 		}
 	}
 
-	return filepath.Join(dbPath, table+config.AllowedExt[0]), nil
+	return filepath.Join(dbPath, table+config.AllowedExt[0])
 }
 
 func Append(table, data string) error {
+	return AppendRows(table, []string{data})
+}
+
+// AppendRows nambihan sababaraha baris dina hiji tulisan (SIMPEN ... NILAI
+// sababaraha baris).
+func AppendRows(table string, rows []string) error {
 	u, err := auth.CurrentUser()
 	if err != nil {
 		return err
@@ -193,11 +270,37 @@ func Append(table, data string) error {
 	}
 	defer file.Close()
 
-	_, err = file.WriteString(data + "\n")
+	_, err = file.WriteString(strings.Join(rows, "\n") + "\n")
 	return err
 }
 
 func ReadAll(table string) ([]string, error) {
+	ts, err := OpenTable(table)
+	if err != nil {
+		return nil, err
+	}
+	defer ts.Close()
+
+	var rows []string
+	for {
+		row, ok := ts.Next()
+		if !ok {
+			break
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, ts.Err()
+}
+
+// TableScanner maca file tabel baris-per-baris, teu ngamuat sakabéh
+// tabel kana memori. Dipaké ku iterator scan dina executor.
+type TableScanner struct {
+	file *os.File
+	sc   *bufio.Scanner
+}
+
+func OpenTable(table string) (*TableScanner, error) {
 	u, err := auth.CurrentUser()
 	if err != nil {
 		return nil, err
@@ -216,14 +319,31 @@ func ReadAll(table string) ([]string, error) {
 	if err != nil {
 		return nil, errors.New("table teu kapanggih")
 	}
-	defer file.Close()
-	var rows []string
+
 	sc := bufio.NewScanner(file)
-	for sc.Scan() {
-		rows = append(rows, sc.Text())
+	sc.Buffer(make([]byte, 64*1024), config.MaxRowSize)
+
+	return &TableScanner{file: file, sc: sc}, nil
+}
+
+func (ts *TableScanner) Next() (string, bool) {
+	if ts.file == nil || !ts.sc.Scan() {
+		return "", false
 	}
+	return ts.sc.Text(), true
+}
 
-	return rows, nil
+func (ts *TableScanner) Err() error {
+	return ts.sc.Err()
+}
+
+func (ts *TableScanner) Close() error {
+	if ts.file == nil {
+		return nil
+	}
+	err := ts.file.Close()
+	ts.file = nil
+	return err
 }
 
 func InitTableFile(database, table string) error {
@@ -316,7 +436,9 @@ func ExportCSV(table string) (string, error) {
 	return filename, nil
 }
 
-func ImportCSV(table string, filePath string) (int, error) {
+// ImportCSV ngimpor rékaman CSV ka tabel. prepare (upami aya) ngarobah
+// rékaman jadi baris nu siap disimpen; rékaman nu gagal dilewat.
+func ImportCSV(table string, filePath string, prepare func(record []string) (string, error)) (int, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return 0, err
@@ -324,6 +446,7 @@ func ImportCSV(table string, filePath string) (int, error) {
 	defer file.Close()
 
 	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
 	records, err := reader.ReadAll()
 	if err != nil {
 		return 0, err
@@ -332,6 +455,11 @@ func ImportCSV(table string, filePath string) (int, error) {
 	count := 0
 	for _, record := range records {
 		rowStr := strings.Join(record, "|")
+		if prepare != nil {
+			if rowStr, err = prepare(record); err != nil {
+				continue
+			}
+		}
 		if err := Append(table, rowStr); err == nil {
 			count++
 		}
@@ -354,11 +482,105 @@ func ListTables(dbName string) ([]string, error) {
     }
 
     var tables []string
+    seen := make(map[string]bool)
     for _, f := range files {
-        if !f.IsDir() && strings.HasSuffix(f.Name(), ".mg") {
-            tableName := strings.TrimSuffix(f.Name(), ".mg")
-            tables = append(tables, tableName)
+        if f.IsDir() {
+            continue
+        }
+        for _, ext := range config.AllowedExt {
+            tableName := strings.TrimSuffix(f.Name(), ext)
+            if tableName != f.Name() && !seen[tableName] {
+                seen[tableName] = true
+                tables = append(tables, tableName)
+            }
         }
     }
     return tables, nil
-}
\ No newline at end of file
+}
+
+// RowCount ngitung baris hiji tabel dina database mana waé (teu kedah
+// database aktif), dipaké ku join sareng katalog.
+func RowCount(dbName, table string) (int, error) {
+    f, err := os.Open(tableFile(DatabasePath(dbName), table))
+    if err != nil {
+        return 0, err
+    }
+    defer f.Close()
+
+    count := 0
+    sc := bufio.NewScanner(f)
+    sc.Buffer(make([]byte, 64*1024), config.MaxRowSize)
+    for sc.Scan() {
+        if strings.TrimSpace(sc.Text()) != "" {
+            count++
+        }
+    }
+    return count, sc.Err()
+}
+
+// StageRows nulis eusi tabel anyar ka file samentawis. Teu aya nu robah
+// dugi ka CommitStaged disauran, janten tabel tetep bisa dipaké mun gagal.
+func StageRows(database, table string, rows []string) (string, error) {
+	path, err := tablePath(database, table)
+	if err != nil {
+		return "", err
+	}
+
+	content := strings.Join(rows, "\n")
+	if len(rows) > 0 {
+		content += "\n"
+	}
+
+	staged := path + ".tmp"
+	if err := os.WriteFile(staged, []byte(content), 0644); err != nil {
+		os.Remove(staged)
+		return "", err
+	}
+	return staged, nil
+}
+
+func CommitStaged(database, table, stagedPath string) error {
+	path, err := tablePath(database, table)
+	if err != nil {
+		return err
+	}
+	return os.Rename(stagedPath, path)
+}
+
+func DiscardStaged(stagedPath string) {
+	os.Remove(stagedPath)
+}
+
+func RenameTable(database, oldTable, newTable string) error {
+	oldPath, err := tablePath(database, oldTable)
+	if err != nil {
+		return err
+	}
+	newPath := filepath.Join(filepath.Dir(oldPath), newTable+filepath.Ext(oldPath))
+	if _, err := os.Stat(newPath); err == nil {
+		return fmt.Errorf("tabel '%s' parantos aya", newTable)
+	}
+	if err := os.Rename(oldPath, newPath); err != nil {
+		return err
+	}
+
+	if err := os.Rename(sequencePath(database, oldTable), sequencePath(database, newTable)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func DropTable(database, table string) error {
+	path, err := tablePath(database, table)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+
+	if err := os.Remove(sequencePath(database, table)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
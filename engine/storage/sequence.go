@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/febrd/maungdb/internal/config"
+)
+
+// seqMu ngajaga sakabéh sequence AUTO sangkan dua SIMPEN babarengan
+// moal kéngingeun ID nu sami.
+var seqMu sync.Mutex
+
+func sequencePath(database, table string) string {
+	return filepath.Join(config.DataDir, "db_"+database, table+".seq")
+}
+
+// NextSequence ngaluarkeun nilai AUTO salajengna pikeun tabel. Nilai
+// terakhir disimpen dina <tabel>.seq; mun file can aya, dimimitian tina
+// nilai pangageungna nu aya di kolom colIdx.
+func NextSequence(database, table string, colIdx int) (int64, error) {
+	seqMu.Lock()
+	defer seqMu.Unlock()
+
+	last, err := loadSequence(database, table, colIdx)
+	if err != nil {
+		return 0, err
+	}
+	next := last + 1
+	if err := writeSequence(database, table, next); err != nil {
+		return 0, err
+	}
+	return next, nil
+}
+
+// BumpSequence mastikeun sequence moal ngaluarkeun nilai <= v, dipaké
+// mun ID AUTO dieusi manual.
+func BumpSequence(database, table string, colIdx int, v int64) error {
+	seqMu.Lock()
+	defer seqMu.Unlock()
+
+	last, err := loadSequence(database, table, colIdx)
+	if err != nil {
+		return err
+	}
+	if v <= last {
+		return nil
+	}
+	return writeSequence(database, table, v)
+}
+
+func SetSequence(database, table string, v int64) error {
+	seqMu.Lock()
+	defer seqMu.Unlock()
+
+	return writeSequence(database, table, v)
+}
+
+func loadSequence(database, table string, colIdx int) (int64, error) {
+	content, err := os.ReadFile(sequencePath(database, table))
+	if err == nil {
+		return strconv.ParseInt(strings.TrimSpace(string(content)), 10, 64)
+	}
+	if !os.IsNotExist(err) {
+		return 0, err
+	}
+
+	path, err := tablePath(database, table)
+	if err != nil {
+		return 0, err
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	var max int64
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), config.MaxRowSize)
+	for scanner.Scan() {
+		parts := strings.Split(scanner.Text(), "|")
+		if colIdx >= len(parts) {
+			continue
+		}
+		if v, err := strconv.ParseInt(strings.TrimSpace(parts[colIdx]), 10, 64); err == nil && v > max {
+			max = v
+		}
+	}
+	return max, scanner.Err()
+}
+
+func writeSequence(database, table string, v int64) error {
+	path := sequencePath(database, table)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strconv.FormatInt(v, 10)+"\n"), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -85,7 +85,7 @@ func (tm *TxManager) AddOperation(username string, opType OpType, table string,
 		return nil
 	}
 
-	return tm.applySingleToStorage(opType, table, data)
+	return tm.applySingleToStorage(WALEntry{Type: opType, TableName: table, Data: data, PrevData: prevData})
 }
 
 func (tm *TxManager) Commit(username string) error {
@@ -136,6 +136,41 @@ func (tm *TxManager) IsActive(username string) bool {
 	return exists
 }
 
+// Savepoint mulihkeun titik simpen transaksi aktif pangguna (jumlah
+// operasi nu parantos dicatet), kanggo RollbackTo.
+func (tm *TxManager) Savepoint(username string) int {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	tx, exists := tm.activeTxs[username]
+	if !exists {
+		return 0
+	}
+	return len(tx.Changes)
+}
+
+// RollbackTo miceun operasi saatos titik simpen mark; transaksina tetep
+// aktif. Dipaké pikeun ngabatalkeun hiji paréntah di jero MIMITIAN.
+func (tm *TxManager) RollbackTo(username string, mark int) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tx, exists := tm.activeTxs[username]
+	if exists && mark >= 0 && mark < len(tx.Changes) {
+		tx.Changes = tx.Changes[:mark]
+	}
+}
+
+// Pending mulihkeun salinan operasi transaksi aktif pangguna (kanggo
+// ngapdet index saatos JADIKEUN).
+func (tm *TxManager) Pending(username string) []WALEntry {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	tx, exists := tm.activeTxs[username]
+	if !exists {
+		return nil
+	}
+	return append([]WALEntry(nil), tx.Changes...)
+}
+
 func (tm *TxManager) writeLog(entries []WALEntry) error {
 	if tm.walFilePath == "" {
 		return fmt.Errorf("WAL path teu valid")
@@ -156,37 +191,68 @@ func (tm *TxManager) writeLog(entries []WALEntry) error {
 	return f.Sync()
 }
 
+// applyBatchToStorage ngahijikeun parobahan per tabel teras nerapkeunana
+// sakaligus ku storage.CommitTables, sangkan transaksi nu nyabak sababaraha
+// tabel (mis. CASCADE) robah sadayana atanapi henteu pisan.
 func (tm *TxManager) applyBatchToStorage(entries []WALEntry) error {
+	var batches []storage.TableChanges
+	pos := make(map[string]int)
 	for _, entry := range entries {
-		if err := tm.applySingleToStorage(entry.Type, entry.TableName, entry.Data); err != nil {
+		change, err := rowChange(entry)
+		if err != nil {
 			return err
 		}
+		i, ok := pos[entry.TableName]
+		if !ok {
+			i = len(batches)
+			pos[entry.TableName] = i
+			batches = append(batches, storage.TableChanges{Table: entry.TableName})
+		}
+		batches[i].Changes = append(batches[i].Changes, change)
 	}
-	return nil
+
+	// Ngan SIMPEN ka hiji tabel: cekap ditambihkeun, teu kedah nulis ulang.
+	if len(batches) == 1 {
+		var rows []string
+		for _, ch := range batches[0].Changes {
+			if !ch.Insert {
+				return storage.CommitTables(batches)
+			}
+			rows = append(rows, ch.Data)
+		}
+		return storage.AppendRows(batches[0].Table, rows)
+	}
+	return storage.CommitTables(batches)
 }
 
-func (tm *TxManager) applySingleToStorage(opType OpType, table, data string) error {
-	var err error
+func (tm *TxManager) applySingleToStorage(entry WALEntry) error {
+	if entry.Type == OpInsert {
+		return storage.Append(entry.TableName, entry.Data)
+	}
+
+	change, err := rowChange(entry)
+	if err != nil {
+		return err
+	}
+	return storage.CommitBatch(entry.TableName, []storage.RowChange{change})
+}
 
-	parts := strings.Split(data, "|")
-	rowID := ""
-	if len(parts) > 0 {
-		rowID = parts[0]
+// rowChange: ID baris dicandak tina PrevData (mun aya), sangkan OMEAN nu
+// ngarobah PK tetep manggihan baris heubeulna.
+func rowChange(entry WALEntry) (storage.RowChange, error) {
+	source := entry.Data
+	if entry.PrevData != "" {
+		source = entry.PrevData
 	}
+	id := strings.SplitN(source, "|", 2)[0]
 
-	switch opType {
+	switch entry.Type {
 	case OpInsert:
-		err = storage.Append(table, data)
-
+		return storage.RowChange{Data: entry.Data, Insert: true}, nil
 	case OpUpdate:
-		err = storage.CommitUpdate(table, rowID, data)
-
+		return storage.RowChange{ID: id, Data: entry.Data}, nil
 	case OpDelete:
-		err = storage.CommitDelete(table, rowID)
-
-	default:
-		return fmt.Errorf("operasi teu dikenal: %s", opType)
+		return storage.RowChange{ID: id, Delete: true}, nil
 	}
-
-	return err
-}
\ No newline at end of file
+	return storage.RowChange{}, fmt.Errorf("operasi teu dikenal: %s", entry.Type)
+}
@@ -0,0 +1,122 @@
+package transaction
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/febrd/maungdb/engine/auth"
+	"github.com/febrd/maungdb/engine/storage"
+	"github.com/febrd/maungdb/internal/config"
+)
+
+func newTestManager(t *testing.T) (*TxManager, string) {
+	t.Helper()
+	dataDir := config.DataDir
+	t.Cleanup(func() { config.DataDir = dataDir })
+
+	config.DataDir = t.TempDir()
+	if err := storage.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if err := auth.Login(config.DefaultUser, config.DefaultPass); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.CreateDatabase("uji"); err != nil {
+		t.Fatal(err)
+	}
+	if err := auth.SetDatabase("uji"); err != nil {
+		t.Fatal(err)
+	}
+	dbPath := storage.DatabasePath("uji")
+	for name, content := range map[string]string{"induk": "1|a\n2|b\n", "anak": "10|1\n11|2\n"} {
+		if err := os.WriteFile(filepath.Join(dbPath, name+".mg"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tm := &TxManager{activeTxs: make(map[string]*Transaction), walFilePath: filepath.Join(config.DataDir, "wal.log")}
+	return tm, dbPath
+}
+
+func TestCommitAcrossTables(t *testing.T) {
+	type op struct {
+		typ        OpType
+		table      string
+		data, prev string
+	}
+	tests := []struct {
+		name      string
+		ops       []op
+		wantErr   bool
+		wantInduk string
+		wantAnak  string
+	}{
+		{
+			name: "CASCADE dua tabel",
+			ops: []op{
+				{OpDelete, "anak", "", "10|1"},
+				{OpDelete, "induk", "", "1|a"},
+				{OpInsert, "anak", "12|2", ""},
+			},
+			wantInduk: "2|b\n",
+			wantAnak:  "11|2\n12|2\n",
+		},
+		{
+			name: "SIMPEN wungkul",
+			ops: []op{
+				{OpInsert, "induk", "3|c", ""},
+				{OpInsert, "induk", "4|d", ""},
+			},
+			wantInduk: "1|a\n2|b\n3|c\n4|d\n",
+			wantAnak:  "10|1\n11|2\n",
+		},
+		{
+			name: "gagal di tabel kadua, sadayana dibatalkeun",
+			ops: []op{
+				{OpDelete, "induk", "", "1|a"},
+				{OpUpdate, "anak", "99|1", "99|2"},
+			},
+			wantErr:   true,
+			wantInduk: "1|a\n2|b\n",
+			wantAnak:  "10|1\n11|2\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm, dbPath := newTestManager(t)
+			if _, err := tm.Begin("maung"); err != nil {
+				t.Fatal(err)
+			}
+			for _, o := range tt.ops {
+				if err := tm.AddOperation("maung", o.typ, o.table, o.data, o.prev); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := tm.Commit("maung"); (err != nil) != tt.wantErr {
+				t.Fatalf("Commit err = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			for table, want := range map[string]string{"induk": tt.wantInduk, "anak": tt.wantAnak} {
+				got, _ := os.ReadFile(filepath.Join(dbPath, table+".mg"))
+				if string(got) != want {
+					t.Errorf("%s = %q, want %q", table, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSavepoint(t *testing.T) {
+	tm, _ := newTestManager(t)
+	tm.Begin("maung")
+	tm.AddOperation("maung", OpInsert, "induk", "3|c", "")
+	mark := tm.Savepoint("maung")
+	tm.AddOperation("maung", OpInsert, "induk", "4|d", "")
+	tm.AddOperation("maung", OpDelete, "induk", "", "1|a")
+	tm.RollbackTo("maung", mark)
+
+	pending := tm.Pending("maung")
+	if len(pending) != 1 || pending[0].Data != "3|c" {
+		t.Errorf("Pending saatos RollbackTo = %v", pending)
+	}
+}
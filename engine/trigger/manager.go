@@ -15,10 +15,25 @@ type TriggerAction struct {
 	Name      string `json:"name"`
 	Event     string 
 	Table     string 
+	Timing    string `json:"timing,omitempty"`
 	ActionQL  string 
 	CreatedAt string `json:"created_at"`
 }
 
+// Waktos jarambah: SAMEMEH (tiasa nolak parobahan) atanapi SANGGEUS.
+const (
+	TimingBefore = "BEFORE"
+	TimingAfter  = "AFTER"
+)
+
+// When mulihkeun waktos jarambah; jarambah heubeul (tanpa Timing) = AFTER.
+func (t TriggerAction) When() string {
+	if t.Timing == "" {
+		return TimingAfter
+	}
+	return t.Timing
+}
+
 type TriggerManager struct {
 	mu sync.RWMutex
 }
@@ -76,4 +91,41 @@ func (tm *TriggerManager) GetTriggers(dbName, table, event string) ([]TriggerAct
 	}
 
 	return triggers, nil
-}
\ No newline at end of file
+}
+func (tm *TriggerManager) ListTriggers(dbName string) ([]TriggerAction, error) {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	triggerDir := filepath.Join(storage.GetDBPathExplicit(dbName), "triggers")
+	files, err := os.ReadDir(triggerDir)
+	if os.IsNotExist(err) {
+		return []TriggerAction{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var triggers []TriggerAction
+	for _, f := range files {
+		if !strings.HasSuffix(f.Name(), ".json") {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(triggerDir, f.Name()))
+		if err != nil {
+			continue
+		}
+		var t TriggerAction
+		if json.Unmarshal(content, &t) == nil {
+			triggers = append(triggers, t)
+		}
+	}
+	return triggers, nil
+}
+
+func (tm *TriggerManager) DeleteTrigger(dbName string, t TriggerAction) error {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	filename := fmt.Sprintf("%s_%s_%s.json", t.Table, t.Event, t.Name)
+	return os.Remove(filepath.Join(storage.GetDBPathExplicit(dbName), "triggers", filename))
+}
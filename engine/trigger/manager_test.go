@@ -0,0 +1,84 @@
+package trigger
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/febrd/maungdb/internal/config"
+)
+
+// TestTriggerFiles: jarambah disimpen per database, tiasa dipilarian per
+// tabel/kajadian, didaptar sadayana, sareng dipiceun hiji-hiji.
+func TestTriggerFiles(t *testing.T) {
+	old := config.DataDir
+	config.DataDir = t.TempDir()
+	defer func() { config.DataDir = old }()
+
+	tm := &TriggerManager{}
+	if got, err := tm.ListTriggers("uji"); err != nil || len(got) != 0 {
+		t.Fatalf("ListTriggers tanpa diréktori = %v, %v", got, err)
+	}
+
+	saved := []TriggerAction{
+		{Name: "audit", Event: "INSERT", Table: "warga", Timing: TimingAfter, ActionQL: "SIMPEN log 1|a"},
+		{Name: "cek", Event: "INSERT", Table: "warga", Timing: TimingBefore, ActionQL: "TOLAK 'x'"},
+		{Name: "audit", Event: "DELETE", Table: "warga", ActionQL: "SIMPEN log 2|b"},
+		{Name: "audit", Event: "INSERT", Table: "warga_arsip", ActionQL: "SIMPEN log 3|c"},
+	}
+	for _, tr := range saved {
+		if err := tm.SaveTrigger("uji", tr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tm.SaveTrigger("sanes", saved[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	names := func(ts []TriggerAction) []string {
+		out := []string{}
+		for _, tr := range ts {
+			out = append(out, tr.Table+"/"+tr.Event+"/"+tr.Name+"/"+tr.When())
+		}
+		sort.Strings(out)
+		return out
+	}
+
+	cases := []struct {
+		table, event string
+		want         []string
+	}{
+		{"warga", "INSERT", []string{"warga/INSERT/audit/AFTER", "warga/INSERT/cek/BEFORE"}},
+		{"warga", "delete", []string{"warga/DELETE/audit/AFTER"}},
+		{"warga", "UPDATE", []string{}},
+		{"warga_arsip", "INSERT", []string{"warga_arsip/INSERT/audit/AFTER"}},
+	}
+	for _, c := range cases {
+		got, err := tm.GetTriggers("uji", c.table, c.event)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(names(got), c.want) {
+			t.Errorf("GetTriggers(%s, %s) = %v, want %v", c.table, c.event, names(got), c.want)
+		}
+	}
+
+	all, err := tm.ListTriggers("uji")
+	if err != nil || len(all) != len(saved) {
+		t.Fatalf("ListTriggers = %v, %v", names(all), err)
+	}
+
+	if err := tm.DeleteTrigger("uji", saved[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := tm.DeleteTrigger("uji", saved[0]); err == nil {
+		t.Error("DeleteTrigger kadua kedah gagal")
+	}
+	got, _ := tm.GetTriggers("uji", "warga", "INSERT")
+	if want := []string{"warga/INSERT/cek/BEFORE"}; !reflect.DeepEqual(names(got), want) {
+		t.Errorf("saatos DeleteTrigger = %v, want %v", names(got), want)
+	}
+	if other, _ := tm.ListTriggers("sanes"); len(other) != 1 {
+		t.Errorf("database séjén kapangaruhan: %v", names(other))
+	}
+}
@@ -3,6 +3,8 @@ package view
 import (
 	"os"
 	"path/filepath"
+	"strings"
+
 	"github.com/febrd/maungdb/internal/config"
 )
 
@@ -33,4 +35,19 @@ func DeleteView(dbName, viewName string) error {
 
 func getViewPath(dbName, viewName string) string {
 	return filepath.Join(config.DataDir, "db_"+dbName, viewName+".view")
-}
\ No newline at end of file
+}
+
+func ListViews(dbName string) ([]string, error) {
+	files, err := os.ReadDir(filepath.Join(config.DataDir, "db_"+dbName))
+	if err != nil {
+		return nil, err
+	}
+
+	var views []string
+	for _, f := range files {
+		if !f.IsDir() && strings.HasSuffix(f.Name(), ".view") {
+			views = append(views, strings.TrimSuffix(f.Name(), ".view"))
+		}
+	}
+	return views, nil
+}
@@ -0,0 +1,67 @@
+package view
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/febrd/maungdb/internal/config"
+)
+
+func TestViewFiles(t *testing.T) {
+	old := config.DataDir
+	config.DataDir = t.TempDir()
+	defer func() { config.DataDir = old }()
+
+	if _, err := ListViews("uji"); err == nil {
+		t.Error("ListViews database nu teu aya kedah gagal")
+	}
+	if err := os.MkdirAll(filepath.Join(config.DataDir, "db_uji"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(config.DataDir, "db_uji", "warga.maung"), nil, 0644)
+
+	steps := []struct {
+		name  string
+		apply func() error
+		want  []string
+	}{
+		{"kosong", nil, nil},
+		{"simpen dua", func() error {
+			if err := SaveView("uji", "warga_aktif", "TINGALI * TI warga DIMANA aktif = 1"); err != nil {
+				return err
+			}
+			return SaveView("uji", "warga_ngaran", "TINGALI ngaran TI warga")
+		}, []string{"warga_aktif", "warga_ngaran"}},
+		{"piceun hiji", func() error { return DeleteView("uji", "warga_aktif") }, []string{"warga_ngaran"}},
+	}
+	for _, s := range steps {
+		t.Run(s.name, func(t *testing.T) {
+			if s.apply != nil {
+				if err := s.apply(); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, err := ListViews("uji")
+			if err != nil {
+				t.Fatal(err)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, s.want) {
+				t.Errorf("ListViews = %v, want %v", got, s.want)
+			}
+		})
+	}
+
+	if !IsView("uji", "warga_ngaran") || IsView("uji", "warga_aktif") || IsView("uji", "warga") {
+		t.Error("IsView teu cocog sareng ListViews")
+	}
+	if q, err := LoadView("uji", "warga_ngaran"); err != nil || q != "TINGALI ngaran TI warga" {
+		t.Errorf("LoadView = %q, %v", q, err)
+	}
+	if err := DeleteView("uji", "warga_aktif"); err == nil {
+		t.Error("DeleteView kadua kedah gagal")
+	}
+}
@@ -5,6 +5,8 @@ var (
 	SystemDir = "_system"
 	SchemaDir = "_schema"
 
+	CatalogFile = "katalog.json"
+
 	AllowedExt = []string{".mg", ".maung"}
 
 	DefaultUser = "maung"
@@ -19,4 +21,11 @@ var (
 
 	SessionFile = "session.maung"
 	GrantsFile  = "grants.maung"
+	RoleFile    = "peran.maung"
+
+	MaxRowSize       = 16 << 20
+	SortMemoryBudget = 64 << 20
+
+	ReplicationDir      = "_replikasi"
+	ReplicationLogLimit = int64(64 << 20)
 )